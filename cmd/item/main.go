@@ -9,7 +9,8 @@ import (
 	"item-service/internal/app"
 	"item-service/internal/config"
 	"item-service/internal/lib/logger/handlers/slogpretty"
-
+	"item-service/internal/lib/logger/levels"
+	"item-service/internal/lib/logger/sl"
 )
 
 const (
@@ -19,15 +20,31 @@ const (
 )
 
 func main() {
+	if runSubcommand(os.Args[1:]) {
+		return
+	}
+
 	cfg := config.MustLoad()
 
-	log := setupLogger(cfg.Env)
+	log, logLevels := setupLogger(cfg.Env, cfg.Log.Redact)
 
 	log.Info("starting item service", slog.Any("cfg", cfg))
 
-	application := app.New(log, cfg.GRPC.Port)
+	application := app.New(log, logLevels, cfg)
 
-	application.GRPCServer.MustRun()
+	go application.GRPCServer.MustRun()
+
+	if application.AdminServer != nil {
+		go application.AdminServer.MustRun()
+	}
+
+	if application.AssetsServer != nil {
+		go application.AssetsServer.MustRun()
+	}
+
+	for _, worker := range application.Workers {
+		go worker.MustRun()
+	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT) // The program will wait for SIGINT or SIGTERM signal to terminate.
@@ -36,38 +53,61 @@ func main() {
 
 	log.Info("stopping application", slog.String("signal", sing.String()))
 
+	if application.AdminServer != nil {
+		application.AdminServer.Stop()
+	}
+
+	if application.AssetsServer != nil {
+		application.AssetsServer.Stop()
+	}
+
+	for _, worker := range application.Workers {
+		worker.Stop()
+	}
+
 	application.GRPCServer.Stop()
+	application.Storage.Close()
 
 	log.Info("application stopped")
 }
 
-func setupLogger(env string) *slog.Logger {
-	var log *slog.Logger
+// setupLogger returns the root logger and the registry used to change its
+// level at runtime. The handlers themselves accept every level, filtering is
+// done by the registry.
+func setupLogger(env string, redact []string) (*slog.Logger, *levels.Registry) {
+	var handler slog.Handler
+
+	level := slog.LevelInfo
 
 	switch env {
 	case envLocal:
-		log = setupPrettySlog()
+		handler = setupPrettyHandler(redact)
+		level = slog.LevelDebug
 	case envDev:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level:       slog.LevelDebug,
+			ReplaceAttr: sl.Redact(redact),
+		})
+		level = slog.LevelDebug
 	case envProd:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level:       slog.LevelDebug,
+			ReplaceAttr: sl.Redact(redact),
+		})
 	}
 
-	return log
+	logLevels := levels.New(level)
+
+	return slog.New(logLevels.Handler(handler)), logLevels
 }
 
-func setupPrettySlog() *slog.Logger {
+func setupPrettyHandler(redact []string) slog.Handler {
 	opts := slogpretty.PrettyHandlerOptions{
 		SlogOpts: &slog.HandlerOptions{
-			Level: slog.LevelDebug,
+			Level:       slog.LevelDebug,
+			ReplaceAttr: sl.Redact(redact),
 		},
 	}
 
-	handler := opts.NewPrettyHandler(os.Stdout)
-
-	return slog.New(handler)
+	return opts.NewPrettyHandler(os.Stdout)
 }
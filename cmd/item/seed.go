@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"item-service/internal/config"
+	"item-service/internal/lib/seed"
+	"item-service/internal/lib/snapshot"
+	"item-service/internal/storage"
+)
+
+// seedCommand generates fake items and loads them into the empty database,
+// or writes them as a snapshot to the file given.
+//
+//	item seed [-items 10000] [-definitions 200] [-owners 100] [-seed 1] [file]
+//
+// The rarities of the items follow the drop table of the loot config. A
+// written file is restored with item restore, or into the in-memory
+// storage with the /snapshot endpoint of the admin server (see admin.token).
+func seedCommand(fs *flag.FlagSet) runFunc {
+	var opts seed.Options
+
+	fs.Int64Var(&opts.Seed, "seed", 1, "random seed; the same seed gives the same data")
+	fs.IntVar(&opts.Items, "items", seed.DefaultItems, "number of items")
+	fs.IntVar(&opts.Definitions, "definitions", seed.DefaultDefinitions, "number of item definitions")
+	fs.IntVar(&opts.Owners, "owners", seed.DefaultOwners, "number of owners the items are spread over")
+
+	return func(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error {
+		if len(args) > 1 {
+			return errors.New("usage: item seed [flags] [file]")
+		}
+
+		opts.Rarities = cfg.Loot.DropTable
+
+		write := func(w io.Writer) (map[string]int, error) {
+			sw, err := snapshot.NewWriter(w, storage.SchemaVersion)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := seed.Write(sw, opts); err != nil {
+				_ = sw.Abort()
+
+				return nil, err
+			}
+
+			return sw.Rows(), sw.Close()
+		}
+
+		log = log.With(
+			slog.Int64("seed", opts.Seed),
+			slog.Int("items", opts.Items),
+			slog.Int("definitions", opts.Definitions),
+			slog.Int("owners", opts.Owners),
+		)
+
+		if len(args) == 1 {
+			path := args[0]
+
+			var rows map[string]int
+			err := writeFile(path, func(w io.Writer) (err error) {
+				rows, err = write(w)
+
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			log.Info("seed written", slog.String("path", path), slog.Any("rows", rows))
+
+			return nil
+		}
+
+		st, err := openStorage(log, cfg)
+		if err != nil {
+			return fmt.Errorf("%w; write the seed to a file and restore it with the /snapshot endpoint of the admin server (see admin.token)", err)
+		}
+		defer st.Close()
+
+		// The seed is restored as it is generated.
+		pr, pw := io.Pipe()
+		go func() {
+			_, err := write(pw)
+			pw.CloseWithError(err)
+		}()
+
+		_, rows, err := storage.RestoreSnapshot(ctx, st, pr)
+		// Unblocks the generator if the restore stopped early.
+		pr.CloseWithError(err)
+		if err != nil {
+			return err
+		}
+
+		log.Info("seed restored", slog.Any("rows", rows))
+
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"item-service/internal/config"
+	"item-service/internal/storage"
+)
+
+// runSnapshot writes a snapshot of the database to the file given, or to
+// stdout if none or - is given.
+//
+//	item snapshot [-config config.yaml] [file]
+func runSnapshot(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error {
+	if len(args) > 1 {
+		return errors.New("usage: item snapshot [flags] [file]")
+	}
+
+	path := "-"
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	st, err := openStorage(log, cfg)
+	if err != nil {
+		return fmt.Errorf("%w; use the /snapshot endpoint of the admin server (see admin.token)", err)
+	}
+	defer st.Close()
+
+	var rows map[string]int
+	err = writeFile(path, func(w io.Writer) error {
+		rows, err = storage.WriteSnapshot(ctx, st, w)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("snapshot written", slog.String("path", path), slog.Any("rows", rows))
+
+	return nil
+}
+
+// runRestore restores the snapshot in the file given, or on stdin for -,
+// into the empty database.
+//
+//	item restore [-config config.yaml] file
+func runRestore(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: item restore [flags] file")
+	}
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	st, err := openStorage(log, cfg)
+	if err != nil {
+		return fmt.Errorf("%w; use the /snapshot endpoint of the admin server (see admin.token)", err)
+	}
+	defer st.Close()
+
+	h, rows, err := storage.RestoreSnapshot(ctx, st, in)
+	if err != nil {
+		return err
+	}
+
+	log.Info("snapshot restored",
+		slog.Time("created_at", h.CreatedAt),
+		slog.Int("schema_version", h.SchemaVersion),
+		slog.Any("rows", rows),
+	)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"item-service/internal/app"
+	"item-service/internal/config"
+)
+
+// errMemoryStorage is returned by the subcommands working on the database
+// when the in-memory storage is configured: it lives inside the running
+// service, so no other process can reach it.
+var errMemoryStorage = errors.New("the in-memory storage cannot be reached from another process")
+
+// runFunc runs a subcommand with the arguments left after the flags.
+type runFunc func(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error
+
+// subcommands are run instead of the service when named by the first
+// argument. Each registers its own flags besides those of the service
+// and returns the function running it.
+var subcommands = map[string]func(fs *flag.FlagSet) runFunc{
+	"snapshot": func(*flag.FlagSet) runFunc { return runSnapshot },
+	"restore":  func(*flag.FlagSet) runFunc { return runRestore },
+	"seed":     seedCommand,
+}
+
+// runSubcommand runs the subcommand named by args[0], if there is one.
+// Subcommands log to stderr, so that stdout is left for their output.
+func runSubcommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	setup, ok := subcommands[args[0]]
+	if !ok {
+		return false
+	}
+
+	fs := flag.NewFlagSet("item "+args[0], flag.ContinueOnError)
+	run := setup(fs)
+
+	cfg, err := config.LoadFlags(fs, args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: cannot load config: %v\n", args[0], err)
+		os.Exit(2)
+	}
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	if err := run(ctx, log, cfg, fs.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		stop()
+		os.Exit(1)
+	}
+
+	return true
+}
+
+func openStorage(log *slog.Logger, cfg *config.Config) (app.Storage, error) {
+	if cfg.Storage.Driver == config.StorageDriverMemory {
+		return nil, errMemoryStorage
+	}
+
+	return app.NewStorage(log, cfg.Storage)
+}
+
+// writeFile calls write with the file at path, or with stdout for -. The
+// file is written next to its path and renamed once write succeeds, so
+// that an interrupted subcommand leaves no partial file behind.
+func writeFile(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
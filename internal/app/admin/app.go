@@ -0,0 +1,88 @@
+package adminapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	adminhttp "item-service/internal/http/admin"
+	"item-service/internal/lib/logger/levels"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type App struct {
+	log        *slog.Logger
+	httpServer *http.Server
+	port       int
+}
+
+// New creates new admin HTTP server app. With a token the storage is
+// snapshotted and restored under /snapshot by requests carrying it.
+func New(log *slog.Logger, lv *levels.Registry, port int, st storage.Snapshotter, token string) *App {
+	mux := http.NewServeMux()
+
+	adminhttp.Register(mux, lv)
+
+	if token != "" {
+		adminhttp.RegisterSnapshot(mux, log, st, token)
+	}
+
+	return &App{
+		log: log,
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		port: port,
+	}
+}
+
+// MustRun runs the admin server and panics if any error occurs.
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Run runs the admin server.
+func (a *App) Run() error {
+	const op = "adminapp.Run"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("port", a.port),
+	)
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("admin server is running", slog.String("addr", l.Addr().String()))
+
+	if err := a.httpServer.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stop stops admin server.
+func (a *App) Stop() {
+	const op = "adminapp.Stop"
+
+	a.log.With(slog.String("op", op)).
+		Info("admin server is stopping", slog.Int("port", a.port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error("failed to stop admin server", sl.Err(err))
+	}
+}
@@ -1,29 +1,206 @@
 package app
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
 
+	"golang.org/x/text/language"
+
+	adminapp "item-service/internal/app/admin"
+	assetsapp "item-service/internal/app/assets"
 	grpcapp "item-service/internal/app/grpc"
-	"item-service/internal/service"
+	workerapp "item-service/internal/app/worker"
+	"item-service/internal/config"
+	"item-service/internal/lib/blob"
+	"item-service/internal/lib/blob/local"
+	"item-service/internal/lib/blob/s3"
+	"item-service/internal/lib/fx"
+	"item-service/internal/lib/logger/levels"
+	item "item-service/internal/service"
+	"item-service/internal/storage"
+	"item-service/internal/storage/memory"
 	db "item-service/internal/storage/postgresql"
+)
 
+// Log components whose levels can be changed at runtime.
+const (
+	componentGRPC    = "grpc"
+	componentService = "service"
+	componentStorage = "storage"
+	componentWorker  = "worker"
 )
 
 type App struct {
-	GRPCServer *grpcapp.App
+	GRPCServer  *grpcapp.App
+	AdminServer *adminapp.App
+	// AssetsServer serves locally stored images; it is nil with S3.
+	AssetsServer *assetsapp.App
+	// Workers run in the background until the application stops.
+	Workers []*workerapp.App
+	Storage Storage
+}
+
+// Storage is the repository of the item service. Close releases its resources.
+type Storage interface {
+	item.Repository
+	storage.Snapshotter
+	Close()
 }
 
-func New(log *slog.Logger, grpcPort int) *App {
-	storage := db.New(log)
-	if storage == nil {
-		panic("Failed to create storage")
+// New creates the application from the configuration. Admin port 0
+// disables the admin server.
+func New(log *slog.Logger, logLevels *levels.Registry, cfg *config.Config) *App {
+	st, err := NewStorage(logLevels.Component(log, componentStorage), cfg.Storage)
+	if err != nil {
+		panic("failed to create storage: " + err.Error())
+	}
+
+	rates, err := newRates(log, cfg.Pricing)
+	if err != nil {
+		panic("failed to load exchange rates: " + err.Error())
 	}
 
-	itemService := item.New(log, storage)
+	assets, assetsHandler, err := newAssets(log, cfg.Assets)
+	if err != nil {
+		panic("failed to create asset store: " + err.Error())
+	}
+
+	itemService := item.New(logLevels.Component(log, componentService), st, item.Options{
+		DropTable:         cfg.Loot.DropTable,
+		RarityOrder:       cfg.Loot.RarityOrder,
+		ValuationCurrency: cfg.Pricing.Currency,
+		ValuationWindow:   cfg.Pricing.ValuationWindow,
+		Rates:             rates,
+		Assets:            assets,
+		ThumbnailSizes:    cfg.Assets.ThumbnailSizes,
+		MaxImageSize:      cfg.Assets.MaxImageSize,
+		Locales:           locales(cfg.I18n),
+		TradeHold:         cfg.Lifecycle.TradeHold,
+		TransitionBatch:   cfg.Lifecycle.WorkerBatch,
+		PurchaseHold:      cfg.Lifecycle.PurchaseHold,
+		ImportBatch:       cfg.Import.BatchSize,
+		MaxImportErrors:   cfg.Import.MaxErrors,
+		ExportBatch:       cfg.Export.FetchSize,
+	})
+
+	grpcApp := grpcapp.New(logLevels.Component(log, componentGRPC), itemService, cfg.GRPC.Port)
 
-	grpcApp := grpcapp.New(log, itemService, grpcPort)
+	var adminApp *adminapp.App
+	if cfg.Admin.Port != 0 {
+		adminApp = adminapp.New(log, logLevels, cfg.Admin.Port, st, cfg.Admin.Token)
+	}
+
+	var assetsApp *assetsapp.App
+	if assetsHandler != nil {
+		assetsApp = assetsapp.New(log, cfg.Assets.Port, assetsHandler)
+	}
+
+	workerLog := logLevels.Component(log, componentWorker)
+
+	transitions := workerapp.New(workerLog, "item-transitions", cfg.Lifecycle.WorkerInterval,
+		func(ctx context.Context) error {
+			_, err := itemService.ApplyDueTransitions(ctx)
+
+			return err
+		},
+	)
+
+	locks := workerapp.New(workerLog, "item-locks", cfg.Lifecycle.WorkerInterval,
+		func(ctx context.Context) error {
+			if _, err := itemService.ReleaseTradeHolds(ctx); err != nil {
+				return err
+			}
+
+			_, err := itemService.DeleteExpiredLocks(ctx)
+
+			return err
+		},
+	)
 
 	return &App{
-		GRPCServer: grpcApp,
+		GRPCServer:   grpcApp,
+		AdminServer:  adminApp,
+		AssetsServer: assetsApp,
+		Workers:      []*workerapp.App{transitions, locks},
+		Storage:      st,
+	}
+}
+
+// NewStorage creates the storage of the configured driver.
+func NewStorage(log *slog.Logger, cfg config.StorageConfig) (Storage, error) {
+	if cfg.Driver == config.StorageDriverMemory {
+		log.Warn("using in-memory storage, data is lost on shutdown")
+
+		return memory.New(), nil
+	}
+
+	st, err := db.New(context.Background(), log, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// newRates loads the exchange-rate table, if a file is configured.
+func newRates(log *slog.Logger, cfg config.PricingConfig) (*fx.Rates, error) {
+	if cfg.ExchangeRatesFile == "" {
+		log.Info("no exchange rates configured, prices are not converted")
+
+		return nil, nil
 	}
+
+	rates, err := fx.Load(cfg.ExchangeRatesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("loaded exchange rates", slog.String("base", rates.Base()))
+
+	return rates, nil
+}
+
+// newAssets creates the store of item images. For the local driver it also
+// returns the handler serving the stored files.
+func newAssets(log *slog.Logger, cfg config.AssetsConfig) (blob.Store, http.Handler, error) {
+	if cfg.Driver == config.AssetsDriverS3 {
+		store, err := s3.New(context.Background(), s3.Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+			BaseURL:   cfg.BaseURL,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		log.Info("storing images in object store", slog.String("bucket", cfg.S3.Bucket))
+
+		return store, nil, nil
+	}
+
+	store, err := local.New(cfg.Dir, cfg.BaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Info("storing images on disk", slog.String("dir", cfg.Dir))
+
+	return store, store.Handler(), nil
+}
+
+// locales returns the configured locales, the default one first.
+func locales(cfg config.I18nConfig) []language.Tag {
+	tags := []language.Tag{language.Make(cfg.DefaultLocale)}
+	for _, locale := range cfg.Locales {
+		if tag := language.Make(locale); tag != tags[0] {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
 }
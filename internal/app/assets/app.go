@@ -0,0 +1,82 @@
+package assetsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"item-service/internal/lib/logger/sl"
+)
+
+// App is the public HTTP server of locally stored item images. It is kept
+// apart from the admin server, so that making images public exposes
+// nothing else.
+type App struct {
+	log        *slog.Logger
+	httpServer *http.Server
+	port       int
+}
+
+// New creates new assets HTTP server app serving the handler under /assets/.
+func New(log *slog.Logger, port int, assets http.Handler) *App {
+	mux := http.NewServeMux()
+	mux.Handle("/assets/", http.StripPrefix("/assets", assets))
+
+	return &App{
+		log: log,
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		port: port,
+	}
+}
+
+// MustRun runs the assets server and panics if any error occurs.
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Run runs the assets server.
+func (a *App) Run() error {
+	const op = "assetsapp.Run"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("port", a.port),
+	)
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("assets server is running", slog.String("addr", l.Addr().String()))
+
+	if err := a.httpServer.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stop stops assets server.
+func (a *App) Stop() {
+	const op = "assetsapp.Stop"
+
+	a.log.With(slog.String("op", op)).
+		Info("assets server is stopping", slog.Int("port", a.port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error("failed to stop assets server", sl.Err(err))
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"google.golang.org/grpc/status"
 
 	itemgrpc "item-service/internal/grpc/item"
-
 )
 
 type App struct {
@@ -39,10 +38,25 @@ func New(log *slog.Logger, itemService itemgrpc.Item, port int) *App {
 		),
 	}
 
-	gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
-        recovery.UnaryServerInterceptor(recoveryOpts...),
-        logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
-    ))
+	// Streams carry file chunks, so their payloads are not logged.
+	streamLoggingOpts := []logging.Option{
+		logging.WithLogOnEvents(
+			logging.StartCall, logging.FinishCall,
+		),
+	}
+
+	gRPCServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryServerInterceptor(recoveryOpts...),
+			logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
+			SessionInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamServerInterceptor(recoveryOpts...),
+			logging.StreamServerInterceptor(InterceptorLogger(log), streamLoggingOpts...),
+			SessionStreamInterceptor(),
+		),
+	)
 
 	itemgrpc.Register(gRPCServer, itemService)
 
@@ -97,4 +111,4 @@ func InterceptorLogger(l *slog.Logger) logging.Logger {
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
 		l.Log(ctx, slog.Level(lvl), msg, fields...)
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,48 @@
+package grpcapp
+
+import (
+	"context"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"item-service/internal/storage"
+)
+
+// sessionHeader is the metadata key clients may use to identify their
+// session across connections. Without it the peer address is used.
+const sessionHeader = "x-session-id"
+
+// SessionInterceptor stores the client session id in the request context
+// so that the storage can route reads after writes to the primary.
+func SessionInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(storage.WithSession(ctx, sessionID(ctx)), req)
+	}
+}
+
+// SessionStreamInterceptor is SessionInterceptor for streaming calls.
+func SessionStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = storage.WithSession(ss.Context(), sessionID(ss.Context()))
+
+		return handler(srv, wrapped)
+	}
+}
+
+func sessionID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(sessionHeader); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,86 @@
+package workerapp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"item-service/internal/lib/logger/sl"
+)
+
+// Job is the work done on every tick of a worker.
+type Job func(ctx context.Context) error
+
+// App runs a job periodically until it is stopped.
+type App struct {
+	log      *slog.Logger
+	name     string
+	interval time.Duration
+	job      Job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+	done   chan struct{}
+}
+
+// New creates new worker app that runs job every interval.
+func New(log *slog.Logger, name string, interval time.Duration, job Job) *App {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &App{
+		log:      log.With(slog.String("worker", name)),
+		name:     name,
+		interval: interval,
+		job:      job,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// MustRun runs the worker and panics if any error occurs.
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Run runs the job every interval until the worker is stopped. A failed run
+// is logged and retried on the next tick.
+func (a *App) Run() error {
+	const op = "workerapp.Run"
+
+	log := a.log.With(slog.String("op", op))
+
+	defer close(a.done)
+
+	log.Info("worker is running", slog.Duration("interval", a.interval))
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.job(a.ctx); err != nil && a.ctx.Err() == nil {
+				log.Error("worker run failed", sl.Err(err))
+			}
+		}
+	}
+}
+
+// Stop stops the worker, cancelling a running job, and waits for it to
+// return. It must only be called after Run.
+func (a *App) Stop() {
+	const op = "workerapp.Stop"
+
+	a.log.With(slog.String("op", op)).
+		Info("worker is stopping")
+
+	a.once.Do(a.cancel)
+	<-a.done
+}
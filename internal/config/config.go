@@ -1,59 +1,453 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 
+	"item-service/internal/lib/logger/sl"
 )
 
-var configPath string
+const (
+	defaultConfigPath = "./config/config.yaml"
+	configPathEnv     = "CONFIG_PATH"
+)
 
-func init() {
-	flag.StringVar(&configPath, "config", "./config/config.yaml", "Path to the config file")
-	flag.Parse()
+type Config struct {
+	Env       string          `yaml:"env" env:"ENV" env-default:"local"`
+	Log       LogConfig       `yaml:"log" env-prefix:"LOG_"`
+	GRPC      GRPCConfig      `yaml:"grpc" env-prefix:"GRPC_"`
+	Admin     AdminConfig     `yaml:"admin" env-prefix:"ADMIN_"`
+	Storage   StorageConfig   `yaml:"storage" env-prefix:"STORAGE_"`
+	Loot      LootConfig      `yaml:"loot" env-prefix:"LOOT_"`
+	Pricing   PricingConfig   `yaml:"pricing" env-prefix:"PRICING_"`
+	Assets    AssetsConfig    `yaml:"assets" env-prefix:"ASSETS_"`
+	I18n      I18nConfig      `yaml:"i18n" env-prefix:"I18N_"`
+	Lifecycle LifecycleConfig `yaml:"lifecycle" env-prefix:"LIFECYCLE_"`
+	Import    ImportConfig    `yaml:"import" env-prefix:"IMPORT_"`
+	Export    ExportConfig    `yaml:"export" env-prefix:"EXPORT_"`
 }
 
-type Config struct {
-	Env     string        `yaml:"env" env-default:"local"`
-	GRPC    GRPCConfig    `yaml:"grpc"`
-	Storage StorageConfig `yaml:"storage"`
+type LogConfig struct {
+	// Redact lists attribute keys whose values are masked by the log handlers.
+	Redact []string `yaml:"redact" env:"REDACT" env-default:"password,secret,token,authorization"`
 }
 
 type GRPCConfig struct {
-	Port    int           `yaml:"port"`
-	Timeout time.Duration `yaml:"timeout"`
+	Port    int           `yaml:"port" env:"PORT" env-default:"44044"`
+	Timeout time.Duration `yaml:"timeout" env:"TIMEOUT" env-default:"5s"`
+}
+
+type AdminConfig struct {
+	// Port of the admin HTTP server; 0 disables it.
+	Port int `yaml:"port" env:"PORT"`
+	// Token authenticates requests to the snapshot endpoints, which read
+	// and load the whole database; without a token they are disabled.
+	Token string `yaml:"token" env:"TOKEN"`
+}
+
+type LootConfig struct {
+	// DropTable maps item rarities to their relative drop weights in cases.
+	// Rarities without a weight never drop.
+	DropTable map[string]float64 `yaml:"drop_table" env:"DROP_TABLE" env-default:"Mil-Spec:79.92,Restricted:15.98,Classified:3.2,Covert:0.64,Rare Special:0.26"`
+	// RarityOrder lists the rarities from lowest to highest. A trade-up
+	// contract turns items of one rarity into an item of the next one.
+	RarityOrder []string `yaml:"rarity_order" env:"RARITY_ORDER" env-default:"Consumer Grade,Industrial Grade,Mil-Spec,Restricted,Classified,Covert"`
+}
+
+type PricingConfig struct {
+	// Currency of the item valuations, as an ISO 4217 code.
+	Currency string `yaml:"currency" env:"CURRENCY" env-default:"USD"`
+	// ValuationWindow is how far back price observations count towards a valuation.
+	ValuationWindow time.Duration `yaml:"valuation_window" env:"VALUATION_WINDOW" env-default:"168h"`
+	// ExchangeRatesFile is a YAML or JSON file of exchange rates used to
+	// convert prices between currencies. Without it no conversions are made.
+	ExchangeRatesFile string `yaml:"exchange_rates_file" env:"EXCHANGE_RATES_FILE"`
+}
+
+// I18nConfig lists the locales item names are translated into, as BCP 47
+// language tags. Requests are served the best-matching locale, falling
+// back to the default one.
+type I18nConfig struct {
+	DefaultLocale string   `yaml:"default_locale" env:"DEFAULT_LOCALE" env-default:"en"`
+	Locales       []string `yaml:"locales" env:"LOCALES" env-default:"en,de,fr,es,pt-BR,ru"`
+}
+
+// LifecycleConfig configures item statuses, item locks and the workers
+// applying scheduled transitions and cleaning up expired locks.
+type LifecycleConfig struct {
+	// TradeHold is how long the trade hold lock of a locked item lasts
+	// unless a hold is given; 0 keeps it until it is removed or the item is
+	// published explicitly.
+	TradeHold time.Duration `yaml:"trade_hold" env:"TRADE_HOLD" env-default:"168h"`
+	// PurchaseHold is how long items are locked after they changed hands;
+	// 0 disables the hold.
+	PurchaseHold time.Duration `yaml:"purchase_hold" env:"PURCHASE_HOLD" env-default:"168h"`
+	// WorkerInterval is how often due transitions are applied, trade holds
+	// are released and expired locks are deleted.
+	WorkerInterval time.Duration `yaml:"worker_interval" env:"WORKER_INTERVAL" env-default:"1m"`
+	// WorkerBatch bounds the transitions applied, the items released and the
+	// locks deleted per run.
+	WorkerBatch int `yaml:"worker_batch" env:"WORKER_BATCH" env-default:"500"`
+}
+
+// ImportConfig configures bulk imports of items.
+type ImportConfig struct {
+	// BatchSize is the number of imported items written at a time.
+	BatchSize int `yaml:"batch_size" env:"BATCH_SIZE" env-default:"1000"`
+	// MaxErrors bounds the row errors reported by an import; further
+	// failed rows are only counted.
+	MaxErrors int `yaml:"max_errors" env:"MAX_ERRORS" env-default:"100"`
+}
+
+// ExportConfig configures streaming exports of items.
+type ExportConfig struct {
+	// FetchSize is the number of items fetched from the export cursor at a
+	// time.
+	FetchSize int `yaml:"fetch_size" env:"FETCH_SIZE" env-default:"1000"`
+}
+
+// AssetsConfig describes where item images are stored. The local driver
+// keeps them in Dir and serves them under /assets/ from a public HTTP
+// server of its own on Port, apart from the admin server; the s3 driver
+// uses any S3-compatible object store, such as MinIO.
+type AssetsConfig struct {
+	Driver string `yaml:"driver" env:"DRIVER" env-default:"local"`
+	// BaseURL is prefixed to object keys to build public image URLs.
+	BaseURL string `yaml:"base_url" env:"BASE_URL" env-default:"http://localhost:44046/assets"`
+	Dir     string `yaml:"dir" env:"DIR" env-default:"./data/assets"`
+	// Port of the HTTP server of the local driver.
+	Port int      `yaml:"port" env:"PORT" env-default:"44046"`
+	S3   S3Config `yaml:"s3" env-prefix:"S3_"`
+
+	// MaxImageSize is the largest accepted upload in bytes.
+	MaxImageSize int64 `yaml:"max_image_size" env:"MAX_IMAGE_SIZE" env-default:"10485760"`
+	// ThumbnailSizes are the edge lengths in pixels of the generated thumbnails.
+	ThumbnailSizes []int `yaml:"thumbnail_sizes" env:"THUMBNAIL_SIZES" env-default:"64,128,256"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"ENDPOINT"`
+	Region    string `yaml:"region" env:"REGION"`
+	Bucket    string `yaml:"bucket" env:"BUCKET"`
+	AccessKey string `yaml:"access_key" env:"ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"SECRET_KEY"`
+	UseSSL    bool   `yaml:"use_ssl" env:"USE_SSL" env-default:"true"`
 }
 
+// Asset drivers.
+const (
+	AssetsDriverLocal = "local"
+	AssetsDriverS3    = "s3"
+)
+
+// StorageConfig describes the storage. With the postgres driver either DSN
+// or the discrete connection fields must be set; DSN takes precedence. The
+// memory driver keeps everything in process and ignores the other settings.
 type StorageConfig struct {
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	Database string `json:"database"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Driver string `yaml:"driver" env:"DRIVER" env-default:"postgres"`
+
+	DSN      string `yaml:"dsn" env:"DSN"`
+	Host     string `yaml:"host" env:"HOST" env-default:"localhost"`
+	Port     string `yaml:"port" env:"PORT" env-default:"5432"`
+	Database string `yaml:"database" env:"DATABASE"`
+	Username string `yaml:"username" env:"USERNAME"`
+	Password string `yaml:"password" env:"PASSWORD"`
+	SSLMode  string `yaml:"sslmode" env:"SSLMODE" env-default:"disable"`
+
+	MaxConns          int32         `yaml:"max_conns" env:"MAX_CONNS" env-default:"10"`
+	MinConns          int32         `yaml:"min_conns" env:"MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"HEALTH_CHECK_PERIOD" env-default:"1m"`
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" env:"CONNECT_TIMEOUT" env-default:"5s"`
+	ConnectAttempts   int           `yaml:"connect_attempts" env:"CONNECT_ATTEMPTS" env-default:"5"`
+	// StatementTimeout aborts statements running longer; 0 leaves the server default.
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"STATEMENT_TIMEOUT"`
+	ApplicationName  string        `yaml:"application_name" env:"APPLICATION_NAME" env-default:"item-service"`
+
+	// Replicas are connection strings of read replicas. They share the pool
+	// settings of the primary.
+	Replicas []string `yaml:"replicas" env:"REPLICAS"`
+	// ReadYourWrites keeps reads of a client session on the primary for this
+	// long after its last write; 0 disables it.
+	ReadYourWrites time.Duration `yaml:"read_your_writes" env:"READ_YOUR_WRITES" env-default:"2s"`
+	// ReplicaCheckPeriod is how often replica health is checked.
+	ReplicaCheckPeriod time.Duration `yaml:"replica_check_period" env:"REPLICA_CHECK_PERIOD" env-default:"5s"`
+
+	QueryLog QueryLogConfig `yaml:"query_log" env-prefix:"QUERY_LOG_"`
+}
+
+// Storage drivers.
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverMemory   = "memory"
+)
+
+// Query log modes.
+const (
+	QueryLogOff       = "off"
+	QueryLogStatement = "statement"
+	QueryLogDuration  = "duration"
+	QueryLogSlow      = "slow"
+)
+
+type QueryLogConfig struct {
+	Mode          string        `yaml:"mode" env:"MODE" env-default:"off"`
+	SlowThreshold time.Duration `yaml:"slow_threshold" env:"SLOW_THRESHOLD" env-default:"200ms"`
 }
 
+// ConnString returns the connection string for the storage. When DSN is
+// empty it is built from the discrete fields. Pool settings are not part of
+// it, they are applied by the client.
+func (s StorageConfig) ConnString() string {
+	if s.DSN != "" {
+		return s.DSN
+	}
+
+	q := url.Values{}
+	q.Set("sslmode", s.SSLMode)
+
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(s.Username, s.Password),
+		Host:     net.JoinHostPort(s.Host, s.Port),
+		Path:     "/" + s.Database,
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
+
+// LogValue implements slog.LogValuer so the config never leaks secrets into logs.
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("env", c.Env),
+		slog.Any("log", c.Log),
+		slog.Any("grpc", c.GRPC),
+		slog.Any("admin", c.Admin),
+		slog.Any("storage", c.Storage),
+		slog.Any("loot", c.Loot),
+		slog.Any("pricing", c.Pricing),
+		slog.Any("assets", c.Assets),
+		slog.Any("i18n", c.I18n),
+		slog.Any("lifecycle", c.Lifecycle),
+		slog.Any("import", c.Import),
+		slog.Any("export", c.Export),
+	)
+}
+
+// ReplicaConfig returns the config of the replica with the given connection string.
+func (s StorageConfig) ReplicaConfig(dsn string) StorageConfig {
+	replica := s
+	replica.DSN = dsn
+	replica.Replicas = nil
+
+	return replica
+}
+
+// LogValue implements slog.LogValuer and masks the password.
+func (s StorageConfig) LogValue() slog.Value {
+	password := ""
+	if s.Password != "" {
+		password = sl.Redacted
+	}
+
+	dsn := ""
+	if s.DSN != "" {
+		dsn = redactDSN(s.DSN)
+	}
+
+	replicas := make([]string, len(s.Replicas))
+	for i, r := range s.Replicas {
+		replicas[i] = redactDSN(r)
+	}
+
+	return slog.GroupValue(
+		slog.String("driver", s.Driver),
+		slog.String("dsn", dsn),
+		slog.String("host", s.Host),
+		slog.String("port", s.Port),
+		slog.String("database", s.Database),
+		slog.String("username", s.Username),
+		slog.String("password", password),
+		slog.String("sslmode", s.SSLMode),
+		slog.Int("max_conns", int(s.MaxConns)),
+		slog.Int("min_conns", int(s.MinConns)),
+		slog.Duration("max_conn_lifetime", s.MaxConnLifetime),
+		slog.Duration("max_conn_idle_time", s.MaxConnIdleTime),
+		slog.Duration("health_check_period", s.HealthCheckPeriod),
+		slog.Duration("connect_timeout", s.ConnectTimeout),
+		slog.Int("connect_attempts", s.ConnectAttempts),
+		slog.Duration("statement_timeout", s.StatementTimeout),
+		slog.String("application_name", s.ApplicationName),
+		slog.Any("replicas", replicas),
+		slog.Duration("read_your_writes", s.ReadYourWrites),
+		slog.Duration("replica_check_period", s.ReplicaCheckPeriod),
+		slog.Group("query_log",
+			slog.String("mode", s.QueryLog.Mode),
+			slog.Duration("slow_threshold", s.QueryLog.SlowThreshold),
+		),
+	)
+}
+
+// redactDSN masks the password of a URL-style DSN. Anything that does not
+// parse as a URL is masked entirely.
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		return sl.Redacted
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), sl.Redacted)
+	}
+
+	return u.String()
+}
+
+// Load builds the configuration from, in increasing order of precedence,
+// defaults, the config file, environment variables and command line flags,
+// and validates the result.
+//
+// The config file is taken from the -config flag or CONFIG_PATH; without
+// either a missing ./config/config.yaml is not an error.
+func Load(args []string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet("item", flag.ContinueOnError), args)
+}
+
+// LoadFlags is Load for subcommands: it parses args with fs, which may
+// have flags of its own besides those of the service. The arguments left
+// after the flags are in fs.Args().
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	var (
+		configPath = fs.String("config", "", "path to the config file (env "+configPathEnv+")")
+		env        = fs.String("env", "", "environment: local, dev or prod")
+		grpcPort   = fs.Int("grpc-port", 0, "gRPC server port")
+		adminPort  = fs.Int("admin-port", 0, "admin HTTP server port, 0 disables it")
+		dsn        = fs.String("storage-dsn", "", "PostgreSQL connection string")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	path, explicit := *configPath, true
+	if path == "" {
+		path = os.Getenv(configPathEnv)
+	}
+	if path == "" {
+		path, explicit = defaultConfigPath, false
+	}
+
+	var cfg Config
+
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+			return nil, fmt.Errorf("cannot read config %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		if err := cleanenv.ReadEnv(&cfg); err != nil {
+			return nil, fmt.Errorf("cannot read config from environment: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+
+	if err := readSecretFiles(&cfg); err != nil {
+		return nil, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "env":
+			cfg.Env = *env
+		case "grpc-port":
+			cfg.GRPC.Port = *grpcPort
+		case "admin-port":
+			cfg.Admin.Port = *adminPort
+		case "storage-dsn":
+			cfg.Storage.DSN = *dsn
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// MustLoad loads the configuration from the command line arguments and
+// panics if it cannot be loaded or is invalid.
 func MustLoad() *Config {
-	if configPath == "" {
-		panic("config path is empty")
+	cfg, err := Load(os.Args[1:])
+	if err != nil {
+		panic("cannot load config: " + err.Error())
 	}
 
-	return MustLoadPath(configPath)
+	return cfg
 }
 
+// MustLoadPath loads the configuration from the given file and panics on error.
 func MustLoadPath(configPath string) *Config {
-	// check if file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		panic("config file does not exist: " + configPath)
+	cfg, err := Load([]string{"-config", configPath})
+	if err != nil {
+		panic("cannot load config: " + err.Error())
 	}
 
-	var cfg Config
+	return cfg
+}
+
+// LogValue implements slog.LogValuer and masks the token.
+func (a AdminConfig) LogValue() slog.Value {
+	token := ""
+	if a.Token != "" {
+		token = sl.Redacted
+	}
+
+	return slog.GroupValue(
+		slog.Int("port", a.Port),
+		slog.String("token", token),
+	)
+}
+
+// LogValue implements slog.LogValuer. Without it the S3 config would be
+// encoded as a plain struct and its secret key logged.
+func (a AssetsConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("driver", a.Driver),
+		slog.String("base_url", a.BaseURL),
+		slog.String("dir", a.Dir),
+		slog.Int("port", a.Port),
+		slog.Any("s3", a.S3.LogValue()),
+		slog.Int64("max_image_size", a.MaxImageSize),
+		slog.Any("thumbnail_sizes", a.ThumbnailSizes),
+	)
+}
 
-	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		panic("cannot read config: " + err.Error())
+// LogValue implements slog.LogValuer and masks the secret key.
+func (s S3Config) LogValue() slog.Value {
+	secretKey := ""
+	if s.SecretKey != "" {
+		secretKey = sl.Redacted
 	}
 
-	return &cfg
+	return slog.GroupValue(
+		slog.String("endpoint", s.Endpoint),
+		slog.String("region", s.Region),
+		slog.String("bucket", s.Bucket),
+		slog.String("access_key", s.AccessKey),
+		slog.String("secret_key", secretKey),
+		slog.Bool("use_ssl", s.UseSSL),
+	)
 }
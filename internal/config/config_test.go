@@ -0,0 +1,281 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"item-service/internal/config"
+)
+
+// minimal is a config file that is valid with the defaults of every other
+// setting.
+const minimal = `
+storage:
+  driver: memory
+`
+
+// writeFile writes content to a file in a temporary directory and returns
+// its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func load(t *testing.T, content string, args ...string) (*config.Config, error) {
+	t.Helper()
+
+	return config.Load(append([]string{"-config", writeFile(t, "config.yaml", content)}, args...))
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	const file = minimal + `
+grpc:
+  port: 1000
+admin:
+  port: 2000
+`
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		args      []string
+		wantGRPC  int
+		wantAdmin int
+	}{
+		{
+			name:      "file",
+			wantGRPC:  1000,
+			wantAdmin: 2000,
+		},
+		{
+			name:      "env overrides file",
+			env:       map[string]string{"GRPC_PORT": "1001"},
+			wantGRPC:  1001,
+			wantAdmin: 2000,
+		},
+		{
+			name:      "flags override env",
+			env:       map[string]string{"GRPC_PORT": "1001", "ADMIN_PORT": "2001"},
+			args:      []string{"-grpc-port", "1002"},
+			wantGRPC:  1002,
+			wantAdmin: 2001,
+		},
+		{
+			name:      "a zero flag is still set",
+			args:      []string{"-admin-port", "0"},
+			wantGRPC:  1000,
+			wantAdmin: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := load(t, file, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.GRPC.Port != tt.wantGRPC || cfg.Admin.Port != tt.wantAdmin {
+				t.Errorf("ports = %d, %d, want %d, %d", cfg.GRPC.Port, cfg.Admin.Port, tt.wantGRPC, tt.wantAdmin)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := config.Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+		t.Error("Load of a missing config file given explicitly succeeded")
+	}
+}
+
+func TestSecretFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		// content of the secret file; the trailing newline is dropped.
+		content string
+		get     func(*config.Config) string
+		want    string
+	}{
+		{
+			name:    "storage password",
+			env:     "STORAGE_PASSWORD_FILE",
+			content: "s3cret\n",
+			get:     func(c *config.Config) string { return c.Storage.Password },
+			want:    "s3cret",
+		},
+		{
+			name:    "nested prefix",
+			env:     "ASSETS_S3_SECRET_KEY_FILE",
+			content: "key\r\n",
+			get:     func(c *config.Config) string { return c.Assets.S3.SecretKey },
+			want:    "key",
+		},
+		{
+			name:    "admin token",
+			env:     "ADMIN_TOKEN_FILE",
+			content: "token",
+			get:     func(c *config.Config) string { return c.Admin.Token },
+			want:    "token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, writeFile(t, "secret", tt.content))
+
+			cfg, err := load(t, minimal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tt.get(cfg); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretFileMissing(t *testing.T) {
+	t.Setenv("STORAGE_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := load(t, minimal)
+	if err == nil || !strings.Contains(err.Error(), "STORAGE_PASSWORD_FILE") {
+		t.Errorf("Load error = %v, want one naming STORAGE_PASSWORD_FILE", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*config.Config)
+		// want are the fields reported, in order; none if the config is valid.
+		want []string
+	}{
+		{
+			name:   "defaults",
+			change: func(*config.Config) {},
+		},
+		{
+			name:   "unknown env",
+			change: func(c *config.Config) { c.Env = "staging" },
+			want:   []string{"env"},
+		},
+		{
+			name:   "admin port equals grpc port",
+			change: func(c *config.Config) { c.Admin.Port = c.GRPC.Port },
+			want:   []string{"admin.port"},
+		},
+		{
+			name: "postgres without dsn or fields",
+			change: func(c *config.Config) {
+				c.Storage.Driver = config.StorageDriverPostgres
+				c.Storage.Host, c.Storage.Database, c.Storage.Username = "", "", ""
+			},
+			want: []string{"storage.database", "storage.host", "storage.username"},
+		},
+		{
+			name: "postgres with dsn",
+			change: func(c *config.Config) {
+				c.Storage.Driver = config.StorageDriverPostgres
+				c.Storage.DSN = "postgres://localhost/items"
+			},
+		},
+		{
+			name:   "min conns above max conns",
+			change: func(c *config.Config) { c.Storage.MinConns = c.Storage.MaxConns + 1 },
+			want:   []string{"storage.min_conns"},
+		},
+		{
+			name:   "drop table without positive weight",
+			change: func(c *config.Config) { c.Loot.DropTable = map[string]float64{"Covert": 0} },
+			want:   []string{"loot.drop_table"},
+		},
+		{
+			name:   "repeated rarity",
+			change: func(c *config.Config) { c.Loot.RarityOrder = []string{"Covert", "Covert"} },
+			want:   []string{"loot.rarity_order"},
+		},
+		{
+			name:   "lower case currency",
+			change: func(c *config.Config) { c.Pricing.Currency = "usd" },
+			want:   []string{"pricing.currency"},
+		},
+		{
+			name:   "assets port taken",
+			change: func(c *config.Config) { c.Assets.Port = c.GRPC.Port },
+			want:   []string{"assets.port"},
+		},
+		{
+			name: "s3 without endpoint and bucket",
+			change: func(c *config.Config) {
+				c.Assets.Driver = config.AssetsDriverS3
+				c.Assets.Port = 0
+			},
+			want: []string{"assets.s3.bucket", "assets.s3.endpoint"},
+		},
+		{
+			name:   "default locale not listed",
+			change: func(c *config.Config) { c.I18n.Locales = []string{"de"} },
+			want:   []string{"i18n.locales"},
+		},
+		{
+			name:   "negative trade hold",
+			change: func(c *config.Config) { c.Lifecycle.TradeHold = -1 },
+			want:   []string{"lifecycle.trade_hold"},
+		},
+		{
+			name:   "negative purchase hold",
+			change: func(c *config.Config) { c.Lifecycle.PurchaseHold = -1 },
+			want:   []string{"lifecycle.purchase_hold"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, minimal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(cfg)
+
+			err = cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var verr *config.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate = %v, want a *ValidationError", err)
+			}
+
+			got := make([]string, 0, len(verr.Problems))
+			for _, p := range verr.Problems {
+				field, _, _ := strings.Cut(p, ":")
+				got = append(got, field)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate reported %v, want %v", verr.Problems, tt.want)
+			}
+		})
+	}
+}
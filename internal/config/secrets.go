@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// secretFileSuffix marks environment variables that point to a file holding
+// the value, as used by Docker and Kubernetes secrets (STORAGE_PASSWORD_FILE).
+const secretFileSuffix = "_FILE"
+
+// readSecretFiles fills every string field that has an env tag from the file
+// named by the corresponding <ENV>_FILE variable, if set.
+func readSecretFiles(cfg *Config) error {
+	return readSecretFilesValue(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func readSecretFilesValue(v reflect.Value, prefix string) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := readSecretFilesValue(value, prefix+field.Tag.Get("env-prefix")); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		env := field.Tag.Get("env")
+		if env == "" || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		name := prefix + env + secretFileSuffix
+
+		path := os.Getenv(name)
+		if path == "" {
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %w", name, err)
+		}
+
+		value.SetString(strings.TrimRight(string(b), "\r\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// ValidationError lists every problem found in the configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks that the configuration is complete and consistent.
+func (c *Config) Validate() error {
+	var problems []string
+
+	add := func(field, format string, args ...any) {
+		problems = append(problems, field+": "+fmt.Sprintf(format, args...))
+	}
+
+	if !oneOf(c.Env, "local", "dev", "prod") {
+		add("env", "must be one of local, dev, prod, got %q", c.Env)
+	}
+
+	if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+		add("grpc.port", "must be between 1 and 65535, got %d", c.GRPC.Port)
+	}
+
+	if c.Admin.Port < 0 || c.Admin.Port > 65535 {
+		add("admin.port", "must be between 0 and 65535, got %d", c.Admin.Port)
+	}
+
+	if c.Admin.Port != 0 && c.Admin.Port == c.GRPC.Port {
+		add("admin.port", "must differ from grpc.port")
+	}
+
+	s := c.Storage
+	if !oneOf(s.Driver, StorageDriverPostgres, StorageDriverMemory) {
+		add("storage.driver", "must be one of postgres, memory, got %q", s.Driver)
+	}
+
+	if s.Driver == StorageDriverPostgres && s.DSN == "" {
+		if s.Host == "" {
+			add("storage.host", "is required when storage.dsn is not set")
+		}
+		if s.Database == "" {
+			add("storage.database", "is required when storage.dsn is not set")
+		}
+		if s.Username == "" {
+			add("storage.username", "is required when storage.dsn is not set")
+		}
+	}
+
+	if !oneOf(s.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full") {
+		add("storage.sslmode", "must be one of disable, allow, prefer, require, verify-ca, verify-full, got %q", s.SSLMode)
+	}
+
+	if s.MaxConns < 1 {
+		add("storage.max_conns", "must be at least 1, got %d", s.MaxConns)
+	}
+
+	if s.MinConns < 0 || s.MinConns > s.MaxConns {
+		add("storage.min_conns", "must be between 0 and storage.max_conns (%d), got %d", s.MaxConns, s.MinConns)
+	}
+
+	if s.ConnectAttempts < 1 {
+		add("storage.connect_attempts", "must be at least 1, got %d", s.ConnectAttempts)
+	}
+
+	for field, d := range map[string]time.Duration{
+		"storage.max_conn_lifetime":   s.MaxConnLifetime,
+		"storage.max_conn_idle_time":  s.MaxConnIdleTime,
+		"storage.health_check_period": s.HealthCheckPeriod,
+		"storage.connect_timeout":     s.ConnectTimeout,
+		"storage.statement_timeout":   s.StatementTimeout,
+		"storage.read_your_writes":    s.ReadYourWrites,
+	} {
+		if d < 0 {
+			add(field, "must not be negative, got %s", d)
+		}
+	}
+
+	if len(s.Replicas) > 0 && s.ReplicaCheckPeriod <= 0 {
+		add("storage.replica_check_period", "must be positive when storage.replicas are set, got %s", s.ReplicaCheckPeriod)
+	}
+
+	for i, r := range s.Replicas {
+		if strings.TrimSpace(r) == "" {
+			add(fmt.Sprintf("storage.replicas[%d]", i), "must not be empty")
+		}
+	}
+
+	if !oneOf(s.QueryLog.Mode, QueryLogOff, QueryLogStatement, QueryLogDuration, QueryLogSlow) {
+		add("storage.query_log.mode", "must be one of off, statement, duration, slow, got %q", s.QueryLog.Mode)
+	}
+
+	var positive bool
+	for rarity, weight := range c.Loot.DropTable {
+		if weight < 0 {
+			add(fmt.Sprintf("loot.drop_table[%s]", rarity), "must not be negative, got %g", weight)
+		}
+		positive = positive || weight > 0
+	}
+
+	if !positive {
+		add("loot.drop_table", "must have at least one positive weight")
+	}
+
+	if len(c.Loot.RarityOrder) < 2 {
+		add("loot.rarity_order", "must list at least two rarities, got %d", len(c.Loot.RarityOrder))
+	}
+
+	seen := make(map[string]bool, len(c.Loot.RarityOrder))
+	for _, rarity := range c.Loot.RarityOrder {
+		if seen[rarity] {
+			add("loot.rarity_order", "must not repeat %q", rarity)
+		}
+		seen[rarity] = true
+	}
+
+	if !isCurrencyCode(c.Pricing.Currency) {
+		add("pricing.currency", "must be a three-letter ISO 4217 code, got %q", c.Pricing.Currency)
+	}
+
+	if c.Pricing.ValuationWindow <= 0 {
+		add("pricing.valuation_window", "must be positive, got %s", c.Pricing.ValuationWindow)
+	}
+
+	a := c.Assets
+
+	if !oneOf(a.Driver, AssetsDriverLocal, AssetsDriverS3) {
+		add("assets.driver", "must be one of local, s3, got %q", a.Driver)
+	}
+
+	if a.Driver == AssetsDriverLocal {
+		if a.Dir == "" {
+			add("assets.dir", "is required with the local driver")
+		}
+		if a.Port < 1 || a.Port > 65535 {
+			add("assets.port", "must be between 1 and 65535 with the local driver, got %d", a.Port)
+		}
+		if a.Port == c.GRPC.Port || a.Port == c.Admin.Port {
+			add("assets.port", "must differ from grpc.port and admin.port")
+		}
+	}
+
+	if a.Driver == AssetsDriverS3 {
+		if a.S3.Endpoint == "" {
+			add("assets.s3.endpoint", "is required with the s3 driver")
+		}
+		if a.S3.Bucket == "" {
+			add("assets.s3.bucket", "is required with the s3 driver")
+		}
+	}
+
+	if a.BaseURL == "" {
+		add("assets.base_url", "is required")
+	}
+
+	if a.MaxImageSize <= 0 {
+		add("assets.max_image_size", "must be positive, got %d", a.MaxImageSize)
+	}
+
+	for i, size := range a.ThumbnailSizes {
+		if size < 1 || size > 4096 {
+			add(fmt.Sprintf("assets.thumbnail_sizes[%d]", i), "must be between 1 and 4096, got %d", size)
+		}
+	}
+
+	if _, err := language.Parse(c.I18n.DefaultLocale); err != nil {
+		add("i18n.default_locale", "must be a BCP 47 language tag, got %q", c.I18n.DefaultLocale)
+	}
+
+	defaultListed := false
+	for i, locale := range c.I18n.Locales {
+		if _, err := language.Parse(locale); err != nil {
+			add(fmt.Sprintf("i18n.locales[%d]", i), "must be a BCP 47 language tag, got %q", locale)
+		}
+		if locale == c.I18n.DefaultLocale {
+			defaultListed = true
+		}
+	}
+
+	if !defaultListed {
+		add("i18n.locales", "must include the default locale %q", c.I18n.DefaultLocale)
+	}
+
+	if c.Lifecycle.TradeHold < 0 {
+		add("lifecycle.trade_hold", "must not be negative, got %s", c.Lifecycle.TradeHold)
+	}
+
+	if c.Lifecycle.PurchaseHold < 0 {
+		add("lifecycle.purchase_hold", "must not be negative, got %s", c.Lifecycle.PurchaseHold)
+	}
+
+	if c.Lifecycle.WorkerInterval <= 0 {
+		add("lifecycle.worker_interval", "must be positive, got %s", c.Lifecycle.WorkerInterval)
+	}
+
+	if c.Lifecycle.WorkerBatch < 1 {
+		add("lifecycle.worker_batch", "must be at least 1, got %d", c.Lifecycle.WorkerBatch)
+	}
+
+	if c.Import.BatchSize < 1 {
+		add("import.batch_size", "must be at least 1, got %d", c.Import.BatchSize)
+	}
+
+	if c.Import.MaxErrors < 1 {
+		add("import.max_errors", "must be at least 1, got %d", c.Import.MaxErrors)
+	}
+
+	if c.Export.FetchSize < 1 {
+		add("export.fetch_size", "must be at least 1, got %d", c.Export.FetchSize)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func oneOf(v string, allowed ...string) bool {
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCurrencyCode reports whether v looks like an ISO 4217 code.
+func isCurrencyCode(v string) bool {
+	if len(v) != 3 {
+		return false
+	}
+
+	for _, r := range v {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type AttributeType string
+
+const (
+	AttributeString   AttributeType = "string"
+	AttributeInt      AttributeType = "int"
+	AttributeBool     AttributeType = "bool"
+	AttributeFloat    AttributeType = "float"
+	AttributeStickers AttributeType = "stickers"
+)
+
+// Names of the attributes known to DefaultAttributes.
+const (
+	AttrStickers      = "stickers"
+	AttrStatTrakKills = "stattrak_kills"
+	AttrNameTag       = "name_tag"
+	AttrSouvenir      = "souvenir"
+)
+
+const (
+	// MaxStickerSlots is the number of sticker slots of an item.
+	MaxStickerSlots = 5
+	// maxAttributeString is the maximum length of a string attribute.
+	maxAttributeString = 100
+)
+
+// Sticker is a sticker applied to a slot of an item.
+type Sticker struct {
+	StickerId string  `json:"sticker_id"`
+	Slot      int     `json:"slot"`
+	Wear      float64 `json:"wear"`
+}
+
+// Attributes are typed values keyed by attribute name. Depending on the
+// attribute type a value is a string, int64, bool, float64 or []Sticker.
+type Attributes map[string]any
+
+// UnmarshalJSON decodes the values of the attributes known to
+// DefaultAttributes into their Go types.
+func (a *Attributes) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	attrs := make(Attributes, len(raw))
+	for key, value := range raw {
+		v, err := DefaultAttributes.decode(key, value)
+		if err != nil {
+			return fmt.Errorf("attribute %q: %w", key, err)
+		}
+
+		attrs[key] = v
+	}
+
+	*a = attrs
+
+	return nil
+}
+
+// AttributeRegistry defines the allowed attribute keys and their types.
+type AttributeRegistry map[string]AttributeType
+
+// DefaultAttributes is the registry of the attributes items can carry.
+var DefaultAttributes = AttributeRegistry{
+	AttrStickers:      AttributeStickers,
+	AttrStatTrakKills: AttributeInt,
+	AttrNameTag:       AttributeString,
+	AttrSouvenir:      AttributeBool,
+}
+
+// Validate checks that every attribute is registered and holds a valid value of its type.
+func (r AttributeRegistry) Validate(attrs Attributes) error {
+	for key, value := range attrs {
+		typ, ok := r[key]
+		if !ok {
+			return fmt.Errorf("unknown attribute %q", key)
+		}
+
+		if err := validateAttribute(typ, value); err != nil {
+			return fmt.Errorf("attribute %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func validateAttribute(typ AttributeType, value any) error {
+	switch typ {
+	case AttributeString:
+		s, ok := value.(string)
+		if !ok {
+			break
+		}
+		if len(s) > maxAttributeString {
+			return fmt.Errorf("longer than %d characters", maxAttributeString)
+		}
+
+		return nil
+	case AttributeInt:
+		n, ok := value.(int64)
+		if !ok {
+			break
+		}
+		if n < 0 {
+			return fmt.Errorf("must not be negative")
+		}
+
+		return nil
+	case AttributeBool:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case AttributeFloat:
+		if _, ok := value.(float64); ok {
+			return nil
+		}
+	case AttributeStickers:
+		stickers, ok := value.([]Sticker)
+		if !ok {
+			break
+		}
+
+		return validateStickers(stickers)
+	}
+
+	return fmt.Errorf("expected a value of type %s, got %T", typ, value)
+}
+
+func validateStickers(stickers []Sticker) error {
+	var used [MaxStickerSlots]bool
+
+	for _, st := range stickers {
+		switch {
+		case st.StickerId == "":
+			return fmt.Errorf("sticker id is required")
+		case st.Slot < 0 || st.Slot >= MaxStickerSlots:
+			return fmt.Errorf("sticker slot %d is out of range [0, %d)", st.Slot, MaxStickerSlots)
+		case used[st.Slot]:
+			return fmt.Errorf("sticker slot %d is used twice", st.Slot)
+		case st.Wear < 0 || st.Wear > 1:
+			return fmt.Errorf("sticker wear must be within [0, 1]")
+		}
+
+		used[st.Slot] = true
+	}
+
+	return nil
+}
+
+// decode decodes the JSON value of the attribute into the Go type of the
+// attribute. Values of unknown attributes are decoded as by json.Unmarshal.
+func (r AttributeRegistry) decode(key string, data json.RawMessage) (any, error) {
+	switch r[key] {
+	case AttributeString:
+		var v string
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case AttributeInt:
+		var v int64
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case AttributeBool:
+		var v bool
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case AttributeFloat:
+		var v float64
+		err := json.Unmarshal(data, &v)
+		return v, err
+	case AttributeStickers:
+		var v []Sticker
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}
+
+	var v any
+	err := json.Unmarshal(data, &v)
+
+	return v, err
+}
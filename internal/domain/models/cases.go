@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CaseOpening is the audit record of an opened case. The drop can be
+// reproduced from Seed, DropTable and Pool.
+type CaseOpening struct {
+	OpeningId    uuid.UUID `json:"opening_id"`
+	CollectionId uuid.UUID `json:"collection_id"`
+	OwnerId      uuid.UUID `json:"owner_id"`
+	// ItemId is the instance created in the owner's inventory.
+	ItemId       uuid.UUID `json:"item_id"`
+	DefinitionId uuid.UUID `json:"definition_id"`
+	Rarity       string    `json:"rarity"`
+	Float        float64   `json:"float"`
+	PatternSeed  int       `json:"pattern_seed"`
+	Seed         int64     `json:"seed"`
+	// DropTable are the rarity weights in effect when the case was opened.
+	DropTable map[string]float64 `json:"drop_table"`
+	// Pool are the definitions of the case when it was opened.
+	Pool     []uuid.UUID `json:"pool"`
+	OpenedAt time.Time   `json:"opened_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CollectionKind string
+
+const (
+	CollectionKindCollection CollectionKind = "collection"
+	// CollectionKindCase is a collection that is the drop pool of a case.
+	CollectionKindCase CollectionKind = "case"
+)
+
+// Collection groups item definitions, e.g. the drop pool of a case.
+type Collection struct {
+	CollectionId uuid.UUID      `json:"collection_id"`
+	Name         string         `json:"name" validate:"required,min=3,max=100"`
+	Kind         CollectionKind `json:"kind" validate:"required,oneof=collection case"`
+	Description  string         `json:"description,omitempty" validate:"max=1000"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemDefinition is a catalogue entry: what an item is, shared by all its copies.
+type ItemDefinition struct {
+	DefinitionId uuid.UUID `json:"definition_id"`
+	Name         string    `json:"name" validate:"required,min=3,max=100"`
+	Rarity       string    `json:"rarity" validate:"required,min=3,max=20"`
+	// MinFloat and MaxFloat bound the float of the definition's instances.
+	MinFloat  float64   `json:"min_float" validate:"gte=0,lte=1"`
+	MaxFloat  float64   `json:"max_float" validate:"gte=0,lte=1,gtfield=MinFloat"`
+	CreatedAt time.Time `json:"created_at"`
+	// Description and Locale are set when Name was replaced by a
+	// translation; they are not stored with the definition.
+	Description string `json:"description,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
+
+// ItemInstance is a concrete copy of an item definition.
+type ItemInstance struct {
+	InstanceId   uuid.UUID `json:"instance_id"`
+	DefinitionId uuid.UUID `json:"definition_id" validate:"required"`
+	OwnerId      uuid.UUID `json:"owner_id,omitempty"`
+	Quality      string    `json:"quality" validate:"required,min=3,max=1000"`
+	Float        float64   `json:"float" validate:"gte=0,lte=1"`
+	PatternSeed  int       `json:"pattern_seed" validate:"gte=0,lte=999"`
+	// Exterior is derived from Float and is not stored.
+	Exterior   Exterior   `json:"exterior"`
+	Attributes Attributes `json:"attributes,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Status     ItemStatus `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// ConsumedAt is set once the instance was used up by a trade-up contract.
+	ConsumedAt time.Time `json:"consumed_at,omitempty"`
+}
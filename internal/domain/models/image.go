@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemImage is the image of an item definition. Images are stored under
+// the SHA-256 of their content, so identical uploads share stored objects.
+type ItemImage struct {
+	DefinitionId   uuid.UUID `json:"definition_id"`
+	Hash           string    `json:"hash"`
+	ContentType    string    `json:"content_type"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	Size           int64     `json:"size"`
+	ThumbnailSizes []int     `json:"thumbnail_sizes"`
+	UploadedAt     time.Time `json:"uploaded_at"`
+	// URLs are derived from the hash and are not stored.
+	URLs ImageURLs `json:"urls"`
+}
+
+// ImageURLs are the public URLs of an image and its thumbnails.
+type ImageURLs struct {
+	Full string `json:"full"`
+	// Thumbnails maps the edge length of each thumbnail to its URL.
+	Thumbnails map[int]string `json:"thumbnails"`
+}
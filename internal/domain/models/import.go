@@ -0,0 +1,20 @@
+package models
+
+// ImportRowError describes a row of an import that was not imported.
+type ImportRowError struct {
+	// Row is the line of the row in the imported file, starting at 1.
+	Row int `json:"row"`
+	// Field is the item field at fault, if the error is about one.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport is the outcome of an import. Errors holds the first of the
+// failed rows only; Failed counts all of them.
+type ImportReport struct {
+	Rows     int              `json:"rows"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	DryRun   bool             `json:"dry_run"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
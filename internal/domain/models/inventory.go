@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InventoryQuery selects a page of the items held by an owner.
+type InventoryQuery struct {
+	OwnerId      uuid.UUID
+	DefinitionId uuid.UUID
+	Rarity       string
+	Quality      string
+	// Statuses matches items having any of the statuses.
+	Statuses []ItemStatus
+	// PageSize is the maximum number of items to return.
+	PageSize int
+	// After is the ID of the last item of the previous page.
+	After uuid.UUID
+}
+
+// OwnershipTransfer is a change of the owner of an item.
+// FromOwner is uuid.Nil when the item was created in ToOwner's inventory.
+type OwnershipTransfer struct {
+	TransferId    uuid.UUID `json:"transfer_id"`
+	ItemId        uuid.UUID `json:"item_id"`
+	FromOwner     uuid.UUID `json:"from_owner,omitempty"`
+	ToOwner       uuid.UUID `json:"to_owner"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
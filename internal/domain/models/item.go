@@ -2,9 +2,53 @@ package models
 
 import "github.com/google/uuid"
 
+// Item is an item instance together with the definition it is a copy of.
 type Item struct {
-	ItemId  uuid.UUID `json:"item_id"`
-	Name    string    `json:"name" validate:"required,min=3,max=100"`
-	Rarity  string    `json:"rarity" validate:"required,min=3,max=20"`
-	Quality string    `json:"quality,omitempty" validate:"required,min=3,max=1000"`
+	ItemId       uuid.UUID `json:"item_id"`
+	DefinitionId uuid.UUID `json:"definition_id"`
+	OwnerId      uuid.UUID `json:"owner_id,omitempty"`
+	Name         string    `json:"name" validate:"required,min=3,max=100"`
+	Rarity       string    `json:"rarity" validate:"required,min=3,max=20"`
+	Quality      string    `json:"quality,omitempty" validate:"required,min=3,max=1000"`
+	Float        float64   `json:"float" validate:"gte=0,lte=1"`
+	PatternSeed  int       `json:"pattern_seed" validate:"gte=0,lte=999"`
+	// Exterior is derived from Float and is not stored.
+	Exterior   Exterior   `json:"exterior"`
+	Attributes Attributes `json:"attributes,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Status     ItemStatus `json:"status,omitempty"`
+	// Valuation is computed from the price history and is not stored.
+	Valuation *Valuation `json:"valuation,omitempty"`
+	// Images are the URLs of the definition's image, if it has one.
+	Images *ImageURLs `json:"images,omitempty"`
+	// Description and Locale are set when Name was replaced by a
+	// translation; they are not stored with the item.
+	Description string `json:"description,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
+
+type ItemSort string
+
+const (
+	ItemSortDefault   ItemSort = ""
+	ItemSortFloatAsc  ItemSort = "float_asc"
+	ItemSortFloatDesc ItemSort = "float_desc"
+)
+
+// ItemFilter selects and orders items. Nil bounds are not applied.
+type ItemFilter struct {
+	MinFloat *float64
+	MaxFloat *float64
+	// Attributes matches items having all the given attribute values.
+	Attributes Attributes
+	// StickerIds matches items having all the given stickers in any slot.
+	StickerIds []string
+	// CollectionId matches items whose definition is in the collection.
+	CollectionId uuid.UUID
+	// Tags matches items by tag, as selected by TagMatch.
+	Tags     []string
+	TagMatch TagMatch
+	// Statuses matches items having any of the statuses.
+	Statuses []ItemStatus
+	Sort     ItemSort
 }
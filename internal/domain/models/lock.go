@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LockReasonTradeHold is the reason of the locks placed on items their new
+// owner received and on items entering the locked status.
+const LockReasonTradeHold = "trade hold"
+
+// ItemLock keeps an item from being transferred, traded or deleted until
+// it expires or is removed. A zero ExpiresAt never expires.
+type ItemLock struct {
+	LockId    uuid.UUID `json:"lock_id"`
+	ItemId    uuid.UUID `json:"item_id"`
+	Reason    string    `json:"reason" validate:"required,max=200"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActiveAt reports whether the lock is in effect at t.
+func (l *ItemLock) ActiveAt(t time.Time) bool {
+	return l.ExpiresAt.IsZero() || l.ExpiresAt.After(t)
+}
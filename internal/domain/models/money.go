@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts of different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// nanosPerUnit is the number of nano units in a major unit, as used by google.type.Money.
+const nanosPerUnit = 1_000_000_000
+
+// minorUnitDigits lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit. Every other currency has two decimal digits.
+var minorUnitDigits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorUnitDigits returns the number of decimal digits of the currency's minor unit.
+func MinorUnitDigits(currency string) int {
+	if d, ok := minorUnitDigits[currency]; ok {
+		return d
+	}
+
+	return 2
+}
+
+// Money is an exact amount in integer minor units of an ISO 4217 currency,
+// e.g. 1999 USD is $19.99.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" validate:"required,iso4217"`
+}
+
+// Add returns the sum of m and o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// String formats m in major units, e.g. "19.99 USD".
+func (m Money) String() string {
+	digits := MinorUnitDigits(m.Currency)
+	if digits == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	scale := pow10(digits)
+
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, digits, amount%scale, m.Currency)
+}
+
+// Units returns m as whole major units and nano units of the same sign,
+// the representation of google.type.Money.
+func (m Money) Units() (units int64, nanos int32) {
+	scale := pow10(MinorUnitDigits(m.Currency))
+
+	return m.Amount / scale, int32(m.Amount % scale * (nanosPerUnit / scale))
+}
+
+// MoneyFromUnits returns the amount given in whole major units and nano
+// units. It fails if the amount is not a whole number of minor units.
+func MoneyFromUnits(units int64, nanos int32, currency string) (Money, error) {
+	if nanos <= -nanosPerUnit || nanos >= nanosPerUnit || (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return Money{}, fmt.Errorf("invalid nanos %d for units %d", nanos, units)
+	}
+
+	scale := pow10(MinorUnitDigits(currency))
+	perMinor := int32(nanosPerUnit / scale)
+
+	if nanos%perMinor != 0 {
+		return Money{}, fmt.Errorf("%d.%09d %s is not a whole number of minor units", units, abs32(nanos), currency)
+	}
+
+	if units > math.MaxInt64/scale || units < math.MinInt64/scale {
+		return Money{}, fmt.Errorf("%d %s is out of range", units, currency)
+	}
+
+	return Money{Amount: units*scale + int64(nanos/perMinor), Currency: currency}, nil
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+
+	return p
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,86 @@
+package models_test
+
+import (
+	"testing"
+
+	"item-service/internal/domain/models"
+)
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		m    models.Money
+		want string
+	}{
+		{models.Money{Amount: 1999, Currency: "USD"}, "19.99 USD"},
+		{models.Money{Amount: 5, Currency: "EUR"}, "0.05 EUR"},
+		{models.Money{Amount: -1999, Currency: "USD"}, "-19.99 USD"},
+		{models.Money{Amount: -5, Currency: "USD"}, "-0.05 USD"},
+		{models.Money{Amount: 150, Currency: "JPY"}, "150 JPY"},
+		{models.Money{Amount: 1234, Currency: "KWD"}, "1.234 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnits(t *testing.T) {
+	tests := []struct {
+		m     models.Money
+		units int64
+		nanos int32
+	}{
+		{models.Money{Amount: 1999, Currency: "USD"}, 19, 990_000_000},
+		{models.Money{Amount: -1999, Currency: "USD"}, -19, -990_000_000},
+		{models.Money{Amount: 150, Currency: "JPY"}, 150, 0},
+		{models.Money{Amount: 1234, Currency: "KWD"}, 1, 234_000_000},
+	}
+
+	for _, tt := range tests {
+		units, nanos := tt.m.Units()
+		if units != tt.units || nanos != tt.nanos {
+			t.Errorf("%s.Units() = %d, %d, want %d, %d", tt.m, units, nanos, tt.units, tt.nanos)
+		}
+
+		got, err := models.MoneyFromUnits(units, nanos, tt.m.Currency)
+		if err != nil || got != tt.m {
+			t.Errorf("MoneyFromUnits(%d, %d, %s) = %s, %v, want %s", units, nanos, tt.m.Currency, got, err, tt.m)
+		}
+	}
+}
+
+func TestMoneyFromUnitsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    int64
+		nanos    int32
+		currency string
+	}{
+		{"fraction of a minor unit", 1, 5_000_000, "USD"},
+		{"fraction of a yen", 1, 500_000_000, "JPY"},
+		{"signs differ", 1, -10_000_000, "USD"},
+		{"nanos out of range", 0, 1_000_000_000, "USD"},
+		{"units out of range", 1 << 62, 0, "USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := models.MoneyFromUnits(tt.units, tt.nanos, tt.currency); err == nil {
+				t.Errorf("MoneyFromUnits(%d, %d, %s) = %s, want an error", tt.units, tt.nanos, tt.currency, got)
+			}
+		})
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	got, err := models.Money{Amount: 100, Currency: "USD"}.Add(models.Money{Amount: 250, Currency: "USD"})
+	if err != nil || got != (models.Money{Amount: 350, Currency: "USD"}) {
+		t.Errorf("Add = %s, %v, want 3.50 USD", got, err)
+	}
+
+	if _, err := (models.Money{Amount: 100, Currency: "USD"}).Add(models.Money{Amount: 100, Currency: "EUR"}); err == nil {
+		t.Error("Add of different currencies succeeded")
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceObservation is a price of an item definition seen at a source.
+type PriceObservation struct {
+	DefinitionId uuid.UUID `json:"definition_id" validate:"required"`
+	Source       string    `json:"source" validate:"required,max=100"`
+	Price        Money     `json:"price"`
+	Volume       int64     `json:"volume" validate:"gte=0"`
+	ObservedAt   time.Time `json:"observed_at" validate:"required"`
+}
+
+// PriceInterval is the width of a price history bucket.
+type PriceInterval string
+
+const (
+	PriceIntervalHour PriceInterval = "hour"
+	PriceIntervalDay  PriceInterval = "day"
+)
+
+// PriceHistoryQuery selects the price history of a definition in one
+// currency. An empty Source includes every source; zero bounds are open.
+type PriceHistoryQuery struct {
+	DefinitionId uuid.UUID
+	Currency     string
+	Source       string
+	Interval     PriceInterval
+	From         time.Time
+	To           time.Time
+}
+
+// PriceCandle summarises the observations of one bucket: open and close are
+// the earliest and the latest price, high and low the extremes.
+type PriceCandle struct {
+	Start        time.Time `json:"start"`
+	Open         Money     `json:"open"`
+	High         Money     `json:"high"`
+	Low          Money     `json:"low"`
+	Close        Money     `json:"close"`
+	Volume       int64     `json:"volume"`
+	Observations int       `json:"observations"`
+}
+
+// Valuation is the current market value of an item definition: the
+// volume-weighted average price of the recent observations, or the latest
+// price when there are none, converted into the valuation currency.
+// Observations without volume count once each.
+type Valuation struct {
+	Price        Money     `json:"price"`
+	Volume       int64     `json:"volume"`
+	Observations int       `json:"observations"`
+	ObservedAt   time.Time `json:"observed_at"`
+}
+
+// PriceAggregate sums up the observations of a valuation in one currency.
+// Price is their average weighted by Weight, which counts every observation
+// by its volume, or once if it has none.
+type PriceAggregate struct {
+	Price        Money
+	Weight       int64
+	Volume       int64
+	Observations int
+	ObservedAt   time.Time
+}
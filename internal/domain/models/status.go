@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemStatus is the lifecycle state of an item.
+type ItemStatus string
+
+const (
+	// ItemStatusDraft items are being prepared and cannot be traded yet.
+	ItemStatusDraft ItemStatus = "draft"
+	// ItemStatusPublished items are live and can be traded.
+	ItemStatusPublished ItemStatus = "published"
+	// ItemStatusLocked items are on a trade hold: they have a trade hold
+	// lock and are published again once none is left.
+	ItemStatusLocked ItemStatus = "locked"
+	// ItemStatusRetired items are withdrawn for good.
+	ItemStatusRetired ItemStatus = "retired"
+)
+
+// Transition is a change of the status of an item. A scheduled transition
+// is applied once DueAt has passed.
+type Transition struct {
+	ItemId    uuid.UUID  `json:"item_id"`
+	To        ItemStatus `json:"to"`
+	DueAt     time.Time  `json:"due_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ItemLifecycle is the status of an item and its scheduled transition, if any.
+type ItemLifecycle struct {
+	ItemId    uuid.UUID   `json:"item_id"`
+	Status    ItemStatus  `json:"status"`
+	Scheduled *Transition `json:"scheduled,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "sort"
+
+// MaxItemTags bounds the tags of a single item.
+const MaxItemTags = 32
+
+// TagMatch is how ItemFilter.Tags are matched.
+type TagMatch string
+
+const (
+	// TagMatchAll matches items having every tag; it is the default.
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny matches items having at least one of the tags.
+	TagMatchAny TagMatch = "any"
+)
+
+// FacetCount is the number of items having a facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Facets counts the items matching a filter by rarity, quality and tag,
+// most frequent values first.
+type Facets struct {
+	Total     int64        `json:"total"`
+	Rarities  []FacetCount `json:"rarities"`
+	Qualities []FacetCount `json:"qualities"`
+	Tags      []FacetCount `json:"tags"`
+}
+
+// SortFacetCounts orders counts by count, highest first, then by value.
+func SortFacetCounts(counts []FacetCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+
+		return counts[i].Value < counts[j].Value
+	})
+}
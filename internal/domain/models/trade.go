@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TradeStatus string
+
+const (
+	TradePending   TradeStatus = "pending"
+	TradeAccepted  TradeStatus = "accepted"
+	TradeDeclined  TradeStatus = "declined"
+	TradeCancelled TradeStatus = "cancelled"
+	TradeExpired   TradeStatus = "expired"
+)
+
+// TradeOffer is an offer to swap the proposer's items for the recipient's items.
+type TradeOffer struct {
+	TradeId     uuid.UUID `json:"trade_id"`
+	ProposerId  uuid.UUID `json:"proposer_id"`
+	RecipientId uuid.UUID `json:"recipient_id"`
+	// ProposerItems are given by the proposer, RecipientItems are asked from the recipient.
+	ProposerItems  []uuid.UUID `json:"proposer_items"`
+	RecipientItems []uuid.UUID `json:"recipient_items"`
+	Status         TradeStatus `json:"status"`
+	CreatedAt      time.Time   `json:"created_at"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+	// ResolvedAt is zero while the offer is pending.
+	ResolvedAt time.Time `json:"resolved_at,omitempty"`
+}
+
+// TradeQuery selects the trade offers of an owner.
+type TradeQuery struct {
+	// OwnerId matches offers where the owner is either the proposer or the recipient.
+	OwnerId uuid.UUID
+	// Status matches offers in the given status; empty matches all.
+	Status TradeStatus
+}
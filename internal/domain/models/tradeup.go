@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TradeUpInput is an item consumed by a trade-up contract.
+type TradeUpInput struct {
+	ItemId       uuid.UUID `json:"item_id"`
+	DefinitionId uuid.UUID `json:"definition_id"`
+	Float        float64   `json:"float"`
+}
+
+// TradeUp is the audit record of a trade-up contract: the consumed items
+// and the item produced from them. The output can be reproduced from Seed.
+type TradeUp struct {
+	TradeUpId          uuid.UUID      `json:"trade_up_id"`
+	OwnerId            uuid.UUID      `json:"owner_id"`
+	Inputs             []TradeUpInput `json:"inputs"`
+	OutputItemId       uuid.UUID      `json:"output_item_id"`
+	OutputDefinitionId uuid.UUID      `json:"output_definition_id"`
+	OutputRarity       string         `json:"output_rarity"`
+	OutputFloat        float64        `json:"output_float"`
+	OutputPatternSeed  int            `json:"output_pattern_seed"`
+	Seed               int64          `json:"seed"`
+	CreatedAt          time.Time      `json:"created_at"`
+}
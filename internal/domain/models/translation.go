@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Translation is the name and description of an item definition in one
+// locale. Locales are BCP 47 language tags such as "de" or "pt-BR".
+type Translation struct {
+	DefinitionId uuid.UUID `json:"definition_id"`
+	Locale       string    `json:"locale" validate:"required,bcp47_language_tag"`
+	Name         string    `json:"name" validate:"required,min=1,max=100"`
+	Description  string    `json:"description,omitempty" validate:"max=2000"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DefinitionSearch is a full-text search of item definitions in a locale.
+// Definitions without a translation in the locale match on their name.
+type DefinitionSearch struct {
+	Query  string
+	Locale string
+	Limit  int
+}
@@ -0,0 +1,54 @@
+package models
+
+// Exterior is the wear grade of an item, derived from its float.
+type Exterior string
+
+const (
+	FactoryNew    Exterior = "Factory New"
+	MinimalWear   Exterior = "Minimal Wear"
+	FieldTested   Exterior = "Field-Tested"
+	WellWorn      Exterior = "Well-Worn"
+	BattleScarred Exterior = "Battle-Scarred"
+)
+
+// ExteriorRange is the float range [MinFloat, MaxFloat) of an exterior grade.
+type ExteriorRange struct {
+	Exterior Exterior
+	MinFloat float64
+	MaxFloat float64
+}
+
+// Exteriors are the exterior grades ordered by float. The last range also
+// includes its MaxFloat.
+var Exteriors = []ExteriorRange{
+	{Exterior: FactoryNew, MinFloat: 0, MaxFloat: 0.07},
+	{Exterior: MinimalWear, MinFloat: 0.07, MaxFloat: 0.15},
+	{Exterior: FieldTested, MinFloat: 0.15, MaxFloat: 0.38},
+	{Exterior: WellWorn, MinFloat: 0.38, MaxFloat: 0.45},
+	{Exterior: BattleScarred, MinFloat: 0.45, MaxFloat: 1},
+}
+
+// ExteriorOf returns the exterior grade of an item with the given float.
+func ExteriorOf(float float64) Exterior {
+	for _, r := range Exteriors {
+		if float < r.MaxFloat {
+			return r.Exterior
+		}
+	}
+
+	return Exteriors[len(Exteriors)-1].Exterior
+}
+
+// Exteriors returns the exterior grades an instance of the definition can
+// have, given its float range.
+func (d *ItemDefinition) Exteriors() []Exterior {
+	exteriors := make([]Exterior, 0, len(Exteriors))
+	for i, r := range Exteriors {
+		last := i == len(Exteriors)-1
+		if (d.MinFloat < r.MaxFloat || last) && r.MinFloat <= d.MaxFloat {
+			exteriors = append(exteriors, r.Exterior)
+		}
+	}
+
+	return exteriors
+}
@@ -0,0 +1,108 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type ItemAttributes interface {
+	SetItemAttributes(ctx context.Context, itemID uuid.UUID, set models.Attributes, remove []string) (attrs models.Attributes, err error)
+}
+
+func (s *serverAPI) SetItemAttributes(ctx context.Context, req *itemv1.SetItemAttributesRequest) (*itemv1.SetItemAttributesResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	set, err := fromAttributesV1(req.GetSet())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attrs, err := s.item.SetItemAttributes(ctx, itemID, set, req.GetRemove())
+	if err != nil {
+		switch {
+		case errors.Is(err, itemsvc.ErrInvalidAttributes):
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		case errors.Is(err, itemsvc.ErrItemNotFound):
+			return nil, status.Error(codes.NotFound, "item not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to set item attributes")
+	}
+
+	return &itemv1.SetItemAttributesResponse{
+		Attributes: toAttributesV1(attrs),
+	}, nil
+}
+
+func fromAttributesV1(values map[string]*itemv1.AttributeValue) (models.Attributes, error) {
+	attrs := make(models.Attributes, len(values))
+
+	for key, value := range values {
+		switch kind := value.GetKind().(type) {
+		case *itemv1.AttributeValue_StringValue:
+			attrs[key] = kind.StringValue
+		case *itemv1.AttributeValue_IntValue:
+			attrs[key] = kind.IntValue
+		case *itemv1.AttributeValue_BoolValue:
+			attrs[key] = kind.BoolValue
+		case *itemv1.AttributeValue_FloatValue:
+			attrs[key] = kind.FloatValue
+		case *itemv1.AttributeValue_Stickers:
+			stickers := make([]models.Sticker, 0, len(kind.Stickers.GetStickers()))
+			for _, st := range kind.Stickers.GetStickers() {
+				stickers = append(stickers, models.Sticker{
+					StickerId: st.GetStickerId(),
+					Slot:      int(st.GetSlot()),
+					Wear:      st.GetWear(),
+				})
+			}
+			attrs[key] = stickers
+		default:
+			return nil, fmt.Errorf("attribute %q has no value", key)
+		}
+	}
+
+	return attrs, nil
+}
+
+func toAttributesV1(attrs models.Attributes) map[string]*itemv1.AttributeValue {
+	values := make(map[string]*itemv1.AttributeValue, len(attrs))
+
+	for key, value := range attrs {
+		switch v := value.(type) {
+		case string:
+			values[key] = &itemv1.AttributeValue{Kind: &itemv1.AttributeValue_StringValue{StringValue: v}}
+		case int64:
+			values[key] = &itemv1.AttributeValue{Kind: &itemv1.AttributeValue_IntValue{IntValue: v}}
+		case bool:
+			values[key] = &itemv1.AttributeValue{Kind: &itemv1.AttributeValue_BoolValue{BoolValue: v}}
+		case float64:
+			values[key] = &itemv1.AttributeValue{Kind: &itemv1.AttributeValue_FloatValue{FloatValue: v}}
+		case []models.Sticker:
+			stickers := &itemv1.StickerList{Stickers: make([]*itemv1.Sticker, 0, len(v))}
+			for _, st := range v {
+				stickers.Stickers = append(stickers.Stickers, &itemv1.Sticker{
+					StickerId: st.StickerId,
+					Slot:      int32(st.Slot),
+					Wear:      st.Wear,
+				})
+			}
+			values[key] = &itemv1.AttributeValue{Kind: &itemv1.AttributeValue_Stickers{Stickers: stickers}}
+		}
+		// Values of unregistered attributes have no typed representation and are left out.
+	}
+
+	return values
+}
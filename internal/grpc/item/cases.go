@@ -0,0 +1,95 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Cases interface {
+	OpenCase(ctx context.Context, collectionID, ownerID uuid.UUID) (opening *models.CaseOpening, err error)
+	GetCaseOpening(ctx context.Context, openingID uuid.UUID) (opening *models.CaseOpening, err error)
+}
+
+func (s *serverAPI) OpenCase(ctx context.Context, req *itemv1.OpenCaseRequest) (*itemv1.OpenCaseResponse, error) {
+	collectionID, err := uuid.Parse(req.GetCollectionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	ownerID, err := uuid.Parse(req.GetOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse owner id")
+	}
+
+	opening, err := s.item.OpenCase(ctx, collectionID, ownerID)
+	if err != nil {
+		return nil, casesStatusError(err, "failed to open case")
+	}
+
+	return &itemv1.OpenCaseResponse{
+		Opening: toCaseOpeningV1(opening),
+	}, nil
+}
+
+func (s *serverAPI) GetCaseOpening(ctx context.Context, req *itemv1.GetCaseOpeningRequest) (*itemv1.GetCaseOpeningResponse, error) {
+	openingID, err := uuid.Parse(req.GetOpeningId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse opening id")
+	}
+
+	opening, err := s.item.GetCaseOpening(ctx, openingID)
+	if err != nil {
+		return nil, casesStatusError(err, "failed to get case opening")
+	}
+
+	return &itemv1.GetCaseOpeningResponse{
+		Opening: toCaseOpeningV1(opening),
+	}, nil
+}
+
+// casesStatusError maps service errors of the case operations to gRPC status errors.
+func casesStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidOwner):
+		return status.Error(codes.InvalidArgument, "invalid owner")
+	case errors.Is(err, itemsvc.ErrCollectionNotFound):
+		return status.Error(codes.NotFound, "collection not found")
+	case errors.Is(err, itemsvc.ErrCaseOpeningNotFound):
+		return status.Error(codes.NotFound, "case opening not found")
+	case errors.Is(err, itemsvc.ErrNotACase):
+		return status.Error(codes.FailedPrecondition, "collection is not a case")
+	case errors.Is(err, itemsvc.ErrEmptyDropPool):
+		return status.Error(codes.FailedPrecondition, "case has no droppable items")
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.Aborted, "dropped item definition was deleted")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toCaseOpeningV1(o *models.CaseOpening) *itemv1.CaseOpening {
+	return &itemv1.CaseOpening{
+		OpeningId:         o.OpeningId.String(),
+		CollectionId:      o.CollectionId.String(),
+		OwnerId:           o.OwnerId.String(),
+		ItemId:            o.ItemId.String(),
+		DefinitionId:      o.DefinitionId.String(),
+		Rarity:            o.Rarity,
+		Float:             o.Float,
+		PatternSeed:       int32(o.PatternSeed),
+		Exterior:          string(models.ExteriorOf(o.Float)),
+		Seed:              o.Seed,
+		DropTable:         o.DropTable,
+		PoolDefinitionIds: uuidStrings(o.Pool),
+		OpenedAt:          timestamppb.New(o.OpenedAt),
+	}
+}
@@ -0,0 +1,209 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Collections interface {
+	CreateCollection(ctx context.Context, c *models.Collection) (collectionID uuid.UUID, err error)
+	GetCollection(ctx context.Context, collectionID uuid.UUID) (c *models.Collection, err error)
+	GetAllCollections(ctx context.Context) (collections []*models.Collection, err error)
+	UpdateCollection(ctx context.Context, c *models.Collection) (err error)
+	DeleteCollection(ctx context.Context, collectionID uuid.UUID) (err error)
+	AddCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) (err error)
+	RemoveCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) (err error)
+	GetCollectionItems(ctx context.Context, collectionID uuid.UUID) (defs []*models.ItemDefinition, err error)
+}
+
+func (s *serverAPI) CreateCollection(ctx context.Context, req *itemv1.CreateCollectionRequest) (*itemv1.CreateCollectionResponse, error) {
+	c := &models.Collection{
+		Name:        req.GetName(),
+		Kind:        models.CollectionKind(req.GetKind()),
+		Description: req.GetDescription(),
+	}
+	if c.Kind == "" {
+		c.Kind = models.CollectionKindCollection
+	}
+
+	if err := s.validator.Struct(c); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	collectionID, err := s.item.CreateCollection(ctx, c)
+	if err != nil {
+		return nil, collectionStatusError(err, "failed to create collection")
+	}
+
+	return &itemv1.CreateCollectionResponse{
+		CollectionId: collectionID.String(),
+	}, nil
+}
+
+func (s *serverAPI) GetCollection(ctx context.Context, req *itemv1.GetCollectionRequest) (*itemv1.GetCollectionResponse, error) {
+	collectionID, err := uuid.Parse(req.GetCollectionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	c, err := s.item.GetCollection(ctx, collectionID)
+	if err != nil {
+		return nil, collectionStatusError(err, "failed to get collection")
+	}
+
+	return &itemv1.GetCollectionResponse{
+		Collection: toCollectionV1(c),
+	}, nil
+}
+
+func (s *serverAPI) ListCollections(ctx context.Context, _ *itemv1.ListCollectionsRequest) (*itemv1.ListCollectionsResponse, error) {
+	collections, err := s.item.GetAllCollections(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list collections")
+	}
+
+	resp := &itemv1.ListCollectionsResponse{
+		Collections: make([]*itemv1.Collection, 0, len(collections)),
+	}
+	for _, c := range collections {
+		resp.Collections = append(resp.Collections, toCollectionV1(c))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) UpdateCollection(ctx context.Context, req *itemv1.UpdateCollectionRequest) (*itemv1.UpdateCollectionResponse, error) {
+	collectionID, err := uuid.Parse(req.GetCollectionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	c := &models.Collection{
+		CollectionId: collectionID,
+		Name:         req.GetName(),
+		Kind:         models.CollectionKind(req.GetKind()),
+		Description:  req.GetDescription(),
+	}
+	if err := s.validator.Struct(c); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.item.UpdateCollection(ctx, c); err != nil {
+		return nil, collectionStatusError(err, "failed to update collection")
+	}
+
+	return &itemv1.UpdateCollectionResponse{
+		Collection: toCollectionV1(c),
+	}, nil
+}
+
+func (s *serverAPI) DeleteCollection(ctx context.Context, req *itemv1.DeleteCollectionRequest) (*itemv1.DeleteCollectionResponse, error) {
+	collectionID, err := uuid.Parse(req.GetCollectionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	if err := s.item.DeleteCollection(ctx, collectionID); err != nil {
+		return nil, collectionStatusError(err, "failed to delete collection")
+	}
+
+	return &itemv1.DeleteCollectionResponse{}, nil
+}
+
+func (s *serverAPI) AddCollectionItems(ctx context.Context, req *itemv1.AddCollectionItemsRequest) (*itemv1.AddCollectionItemsResponse, error) {
+	collectionID, definitionIDs, err := parseCollectionItems(req.GetCollectionId(), req.GetDefinitionIds())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.item.AddCollectionItems(ctx, collectionID, definitionIDs); err != nil {
+		return nil, collectionStatusError(err, "failed to add collection items")
+	}
+
+	return &itemv1.AddCollectionItemsResponse{}, nil
+}
+
+func (s *serverAPI) RemoveCollectionItems(ctx context.Context, req *itemv1.RemoveCollectionItemsRequest) (*itemv1.RemoveCollectionItemsResponse, error) {
+	collectionID, definitionIDs, err := parseCollectionItems(req.GetCollectionId(), req.GetDefinitionIds())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.item.RemoveCollectionItems(ctx, collectionID, definitionIDs); err != nil {
+		return nil, collectionStatusError(err, "failed to remove collection items")
+	}
+
+	return &itemv1.RemoveCollectionItemsResponse{}, nil
+}
+
+func (s *serverAPI) ListCollectionItems(ctx context.Context, req *itemv1.ListCollectionItemsRequest) (*itemv1.ListCollectionItemsResponse, error) {
+	collectionID, err := uuid.Parse(req.GetCollectionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	defs, err := s.item.GetCollectionItems(ctx, collectionID)
+	if err != nil {
+		return nil, collectionStatusError(err, "failed to list collection items")
+	}
+
+	resp := &itemv1.ListCollectionItemsResponse{
+		Definitions: make([]*itemv1.ItemDefinition, 0, len(defs)),
+	}
+	for _, def := range defs {
+		resp.Definitions = append(resp.Definitions, toItemDefinitionV1(def))
+	}
+
+	return resp, nil
+}
+
+func parseCollectionItems(collectionID string, definitionIDs []string) (uuid.UUID, []uuid.UUID, error) {
+	collection, err := uuid.Parse(collectionID)
+	if err != nil {
+		return uuid.Nil, nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	if len(definitionIDs) == 0 {
+		return uuid.Nil, nil, status.Error(codes.InvalidArgument, "definition ids are required")
+	}
+
+	definitions, err := parseUUIDs(definitionIDs)
+	if err != nil {
+		return uuid.Nil, nil, status.Error(codes.InvalidArgument, "failed to parse definition ids")
+	}
+
+	return collection, definitions, nil
+}
+
+// collectionStatusError maps service errors of the collection operations to gRPC status errors.
+func collectionStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrCollectionExists):
+		return status.Error(codes.AlreadyExists, "collection already exists")
+	case errors.Is(err, itemsvc.ErrCollectionNotFound):
+		return status.Error(codes.NotFound, "collection not found")
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.NotFound, "item definition not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toCollectionV1(c *models.Collection) *itemv1.Collection {
+	return &itemv1.Collection{
+		CollectionId: c.CollectionId.String(),
+		Name:         c.Name,
+		Kind:         string(c.Kind),
+		Description:  c.Description,
+		CreatedAt:    timestamppb.New(c.CreatedAt),
+	}
+}
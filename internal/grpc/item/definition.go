@@ -0,0 +1,137 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type ItemDefinitions interface {
+	CreateItemDefinition(ctx context.Context, def *models.ItemDefinition) (definitionID uuid.UUID, err error)
+	GetItemDefinition(ctx context.Context, definitionID uuid.UUID) (def *models.ItemDefinition, err error)
+	GetAllItemDefinitions(ctx context.Context) (defs []*models.ItemDefinition, err error)
+	DeleteItemDefinition(ctx context.Context, definitionID uuid.UUID) (err error)
+}
+
+func (s *serverAPI) CreateItemDefinition(ctx context.Context, req *itemv1.CreateItemDefinitionRequest) (*itemv1.CreateItemDefinitionResponse, error) {
+	def := &models.ItemDefinition{
+		Name:     req.GetName(),
+		Rarity:   req.GetRarity(),
+		MinFloat: req.GetMinFloat(),
+		MaxFloat: req.GetMaxFloat(),
+	}
+	// An unset range allows every float.
+	if def.MinFloat == 0 && def.MaxFloat == 0 {
+		def.MaxFloat = 1
+	}
+
+	if err := s.validator.Struct(def); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	definitionID, err := s.item.CreateItemDefinition(ctx, def)
+	if err != nil {
+		if errors.Is(err, itemsvc.ErrDefinitionExists) {
+			return nil, status.Error(codes.AlreadyExists, "item definition already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create item definition")
+	}
+
+	return &itemv1.CreateItemDefinitionResponse{
+		DefinitionId: definitionID.String(),
+	}, nil
+}
+
+func (s *serverAPI) GetItemDefinition(ctx context.Context, req *itemv1.GetItemDefinitionRequest) (*itemv1.GetItemDefinitionResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	def, err := s.item.GetItemDefinition(ctx, definitionID)
+	if err != nil {
+		if errors.Is(err, itemsvc.ErrDefinitionNotFound) {
+			return nil, status.Error(codes.NotFound, "item definition not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get item definition")
+	}
+
+	if err := s.item.LocalizeDefinitions(ctx, requestLocale(ctx, req.GetLocale()), def); err != nil {
+		return nil, translationStatusError(err, "failed to localize item definition")
+	}
+
+	return &itemv1.GetItemDefinitionResponse{
+		Definition: toItemDefinitionV1(def),
+	}, nil
+}
+
+func (s *serverAPI) ListItemDefinitions(ctx context.Context, req *itemv1.ListItemDefinitionsRequest) (*itemv1.ListItemDefinitionsResponse, error) {
+	defs, err := s.item.GetAllItemDefinitions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list item definitions")
+	}
+
+	if err := s.item.LocalizeDefinitions(ctx, requestLocale(ctx, req.GetLocale()), defs...); err != nil {
+		return nil, translationStatusError(err, "failed to localize item definitions")
+	}
+
+	resp := &itemv1.ListItemDefinitionsResponse{
+		Definitions: make([]*itemv1.ItemDefinition, 0, len(defs)),
+	}
+	for _, def := range defs {
+		resp.Definitions = append(resp.Definitions, toItemDefinitionV1(def))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteItemDefinition(ctx context.Context, req *itemv1.DeleteItemDefinitionRequest) (*itemv1.DeleteItemDefinitionResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	if err := s.item.DeleteItemDefinition(ctx, definitionID); err != nil {
+		switch {
+		case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+			return nil, status.Error(codes.NotFound, "item definition not found")
+		case errors.Is(err, itemsvc.ErrDefinitionInUse):
+			return nil, status.Error(codes.FailedPrecondition, "item definition has instances")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to delete item definition")
+	}
+
+	return &itemv1.DeleteItemDefinitionResponse{}, nil
+}
+
+func toItemDefinitionV1(def *models.ItemDefinition) *itemv1.ItemDefinition {
+	return &itemv1.ItemDefinition{
+		DefinitionId: def.DefinitionId.String(),
+		Name:         def.Name,
+		Rarity:       def.Rarity,
+		MinFloat:     def.MinFloat,
+		MaxFloat:     def.MaxFloat,
+		Exteriors:    exteriorStrings(def.Exteriors()),
+		Description:  def.Description,
+		Locale:       def.Locale,
+	}
+}
+
+func exteriorStrings(exteriors []models.Exterior) []string {
+	ss := make([]string, 0, len(exteriors))
+	for _, e := range exteriors {
+		ss = append(ss, string(e))
+	}
+
+	return ss
+}
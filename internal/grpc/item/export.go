@@ -0,0 +1,143 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/itemio"
+	itemsvc "item-service/internal/service"
+)
+
+// exportChunkSize bounds the file chunks sent by ExportItems.
+const exportChunkSize = 64 << 10
+
+// exportedItemsTrailer is the trailer carrying the number of exported items.
+const exportedItemsTrailer = "x-exported-items"
+
+type Exports interface {
+	ExportItems(ctx context.Context, filter models.ItemFilter, w itemsvc.ItemWriter) (exported int, err error)
+}
+
+// ExportItems streams the items matching the filter as a CSV, JSON Lines or
+// Parquet file, in chunks. The number of items is sent in the
+// x-exported-items trailer.
+func (s *serverAPI) ExportItems(req *itemv1.ExportItemsRequest, stream itemv1.ItemService_ExportItemsServer) error {
+	format, err := itemio.ParseFormat(req.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	compression, err := itemio.ParseCompression(req.GetCompression())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attrs, err := fromAttributesV1(req.GetAttributes())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := models.ItemFilter{
+		MinFloat:   req.MinFloat,
+		MaxFloat:   req.MaxFloat,
+		Attributes: attrs,
+		StickerIds: req.GetStickerIds(),
+		Tags:       req.GetTags(),
+		TagMatch:   models.TagMatch(req.GetTagMatch()),
+		Statuses:   fromStatusesV1(req.GetStatuses()),
+		Sort:       models.ItemSort(req.GetSort()),
+	}
+	if filter.CollectionId, err = parseOptionalUUID(req.GetCollectionId()); err != nil {
+		return status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	cw := &exportChunkWriter{stream: stream}
+
+	w, err := itemio.NewWriter(cw, format, compression)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to export items")
+	}
+
+	n, err := s.item.ExportItems(stream.Context(), filter, w)
+	if err != nil {
+		return exportStatusError(err)
+	}
+
+	if err := w.Close(); err != nil {
+		return exportStatusError(err)
+	}
+
+	if err := cw.flush(); err != nil {
+		return exportStatusError(err)
+	}
+
+	stream.SetTrailer(metadata.Pairs(exportedItemsTrailer, strconv.Itoa(n)))
+
+	return nil
+}
+
+// exportChunkWriter sends what is written to it as chunks of at most
+// exportChunkSize bytes.
+type exportChunkWriter struct {
+	stream itemv1.ItemService_ExportItemsServer
+	buf    []byte
+}
+
+func (w *exportChunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	for len(p) > 0 {
+		if w.buf == nil {
+			w.buf = make([]byte, 0, exportChunkSize)
+		}
+
+		k := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf, p = w.buf[:len(w.buf)+k], p[k:]
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// flush sends the buffered bytes, if any. The buffer is handed over to the
+// message, which may be marshalled after Send returns.
+func (w *exportChunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	chunk := w.buf
+	w.buf = nil
+
+	return w.stream.Send(&itemv1.ExportItemsResponse{Chunk: chunk})
+}
+
+// exportStatusError maps errors of an export to gRPC status errors. Errors
+// of the stream itself keep their status.
+func exportStatusError(err error) error {
+	var streamErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &streamErr) {
+		return streamErr.GRPCStatus().Err()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, itemsvc.ErrInvalidFilter):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	}
+
+	return status.Error(codes.Internal, "failed to export items")
+}
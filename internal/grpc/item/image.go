@@ -0,0 +1,124 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Images interface {
+	UploadItemImage(ctx context.Context, definitionID uuid.UUID, r io.Reader) (img *models.ItemImage, err error)
+}
+
+// errUnexpectedDefinitionID is returned by imageChunkReader when a message
+// after the first one names the definition again.
+var errUnexpectedDefinitionID = errors.New("only the first message may carry the definition id")
+
+// UploadItemImage receives an image as a stream: the first message names
+// the definition, the following ones carry chunks of the image.
+func (s *serverAPI) UploadItemImage(stream itemv1.ItemService_UploadItemImageServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "empty upload")
+		}
+
+		return err
+	}
+
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry a valid definition id")
+	}
+
+	img, err := s.item.UploadItemImage(stream.Context(), definitionID, &imageChunkReader{stream: stream})
+	if err != nil {
+		return imageStatusError(err, "failed to upload item image")
+	}
+
+	return stream.SendAndClose(&itemv1.UploadItemImageResponse{
+		Image: toItemImageV1(img),
+	})
+}
+
+// imageChunkReader reads the image chunks of an upload stream.
+type imageChunkReader struct {
+	stream itemv1.ItemService_UploadItemImageServer
+	chunk  []byte
+}
+
+func (r *imageChunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if _, ok := req.GetData().(*itemv1.UploadItemImageRequest_DefinitionId); ok {
+			return 0, errUnexpectedDefinitionID
+		}
+
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+// imageStatusError maps service errors of the image operations to gRPC status errors.
+func imageStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, errUnexpectedDefinitionID):
+		return status.Error(codes.InvalidArgument, errUnexpectedDefinitionID.Error())
+	case errors.Is(err, itemsvc.ErrInvalidImage):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrImageTooLarge):
+		return status.Error(codes.ResourceExhausted, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.NotFound, "item definition not found")
+	case status.Code(err) == codes.Canceled || status.Code(err) == codes.DeadlineExceeded:
+		return err
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toItemImageV1(img *models.ItemImage) *itemv1.ItemImage {
+	return &itemv1.ItemImage{
+		DefinitionId: img.DefinitionId.String(),
+		Hash:         img.Hash,
+		ContentType:  img.ContentType,
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+		Size:         img.Size,
+		UploadedAt:   timestamppb.New(img.UploadedAt),
+		Urls:         toImageURLsV1(&img.URLs),
+	}
+}
+
+func toImageURLsV1(urls *models.ImageURLs) *itemv1.ImageUrls {
+	if urls == nil {
+		return nil
+	}
+
+	thumbnails := make(map[int32]string, len(urls.Thumbnails))
+	for size, url := range urls.Thumbnails {
+		thumbnails[int32(size)] = url
+	}
+
+	return &itemv1.ImageUrls{
+		Full:       urls.Full,
+		Thumbnails: thumbnails,
+	}
+}
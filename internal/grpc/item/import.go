@@ -0,0 +1,140 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/itemio"
+	itemsvc "item-service/internal/service"
+)
+
+type Imports interface {
+	ImportItems(ctx context.Context, r itemsvc.ItemReader, dryRun bool) (report *models.ImportReport, err error)
+}
+
+// errUnexpectedImportOptions is returned by importChunkReader when a message
+// after the first one carries the import options again.
+var errUnexpectedImportOptions = errors.New("only the first message may carry the import options")
+
+// ImportItems receives a CSV or JSON Lines file as a stream: the first
+// message carries the import options, the following ones chunks of the file.
+func (s *serverAPI) ImportItems(stream itemv1.ItemService_ImportItemsServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "empty import")
+		}
+
+		return err
+	}
+
+	opts := req.GetOptions()
+	if opts == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the import options")
+	}
+
+	format, err := itemio.ParseFormat(opts.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	mapping, err := itemio.ParseMapping([]byte(opts.GetMapping()))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rd, err := itemio.NewReader(&importChunkReader{stream: stream}, format, mapping)
+	if err != nil {
+		return importStatusError(err, nil)
+	}
+
+	report, err := s.item.ImportItems(stream.Context(), rd, opts.GetDryRun())
+	if err != nil {
+		return importStatusError(err, report)
+	}
+
+	return stream.SendAndClose(toImportReportV1(report))
+}
+
+// importChunkReader reads the file chunks of an import stream.
+type importChunkReader struct {
+	stream itemv1.ItemService_ImportItemsServer
+	chunk  []byte
+}
+
+func (r *importChunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if _, ok := req.GetData().(*itemv1.ImportItemsRequest_Options); ok {
+			return 0, errUnexpectedImportOptions
+		}
+
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+// importStatusError maps errors of an import to gRPC status errors. Errors
+// of the stream itself keep their status. Items imported before the error
+// are mentioned, since they are kept.
+func importStatusError(err error, report *models.ImportReport) error {
+	var streamErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &streamErr) {
+		return streamErr.GRPCStatus().Err()
+	}
+
+	code, msg := codes.Internal, "failed to import items"
+
+	switch {
+	case errors.Is(err, errUnexpectedImportOptions):
+		return status.Error(codes.InvalidArgument, errUnexpectedImportOptions.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case report == nil:
+		// The header of the file does not fit the mapping.
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, itemsvc.ErrInvalidImport):
+		code, msg = codes.InvalidArgument, errors.Unwrap(err).Error()
+	}
+
+	if report.Imported > 0 && !report.DryRun {
+		msg = fmt.Sprintf("%s; %d items were imported before", msg, report.Imported)
+	}
+
+	return status.Error(code, msg)
+}
+
+func toImportReportV1(report *models.ImportReport) *itemv1.ImportItemsResponse {
+	resp := &itemv1.ImportItemsResponse{
+		Rows:     int32(report.Rows),
+		Imported: int32(report.Imported),
+		Failed:   int32(report.Failed),
+		DryRun:   report.DryRun,
+		Errors:   make([]*itemv1.ImportRowError, 0, len(report.Errors)),
+	}
+
+	for _, e := range report.Errors {
+		resp.Errors = append(resp.Errors, &itemv1.ImportRowError{
+			Row:     int32(e.Row),
+			Field:   e.Field,
+			Message: e.Message,
+		})
+	}
+
+	return resp
+}
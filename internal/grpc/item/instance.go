@@ -0,0 +1,123 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type ItemInstances interface {
+	CreateItemInstance(ctx context.Context, inst *models.ItemInstance) (instanceID uuid.UUID, err error)
+	GetItemInstance(ctx context.Context, instanceID uuid.UUID) (inst *models.ItemInstance, err error)
+	GetItemInstances(ctx context.Context, definitionID uuid.UUID) (instances []*models.ItemInstance, err error)
+}
+
+func (s *serverAPI) CreateItemInstance(ctx context.Context, req *itemv1.CreateItemInstanceRequest) (*itemv1.CreateItemInstanceResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	ownerID, err := parseOptionalUUID(req.GetOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse owner id")
+	}
+
+	attrs, err := fromAttributesV1(req.GetAttributes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	inst := &models.ItemInstance{
+		DefinitionId: definitionID,
+		OwnerId:      ownerID,
+		Quality:      req.GetQuality(),
+		Float:        req.GetFloat(),
+		PatternSeed:  int(req.GetPatternSeed()),
+		Attributes:   attrs,
+	}
+	if err := s.validator.Struct(inst); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	instanceID, err := s.item.CreateItemInstance(ctx, inst)
+	if err != nil {
+		switch {
+		case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+			return nil, status.Error(codes.NotFound, "item definition not found")
+		case errors.Is(err, itemsvc.ErrFloatOutOfRange):
+			return nil, status.Error(codes.InvalidArgument, "item float is outside of the definition's range")
+		case errors.Is(err, itemsvc.ErrInvalidAttributes):
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create item instance")
+	}
+
+	return &itemv1.CreateItemInstanceResponse{
+		InstanceId: instanceID.String(),
+	}, nil
+}
+
+func (s *serverAPI) GetItemInstance(ctx context.Context, req *itemv1.GetItemInstanceRequest) (*itemv1.GetItemInstanceResponse, error) {
+	instanceID, err := uuid.Parse(req.GetInstanceId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse instance id")
+	}
+
+	inst, err := s.item.GetItemInstance(ctx, instanceID)
+	if err != nil {
+		if errors.Is(err, itemsvc.ErrItemNotFound) {
+			return nil, status.Error(codes.NotFound, "item instance not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get item instance")
+	}
+
+	return &itemv1.GetItemInstanceResponse{
+		Instance: toItemInstanceV1(inst),
+	}, nil
+}
+
+func (s *serverAPI) ListItemInstances(ctx context.Context, req *itemv1.ListItemInstancesRequest) (*itemv1.ListItemInstancesResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	instances, err := s.item.GetItemInstances(ctx, definitionID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list item instances")
+	}
+
+	resp := &itemv1.ListItemInstancesResponse{
+		Instances: make([]*itemv1.ItemInstance, 0, len(instances)),
+	}
+	for _, inst := range instances {
+		resp.Instances = append(resp.Instances, toItemInstanceV1(inst))
+	}
+
+	return resp, nil
+}
+
+func toItemInstanceV1(inst *models.ItemInstance) *itemv1.ItemInstance {
+	return &itemv1.ItemInstance{
+		InstanceId:   inst.InstanceId.String(),
+		DefinitionId: inst.DefinitionId.String(),
+		OwnerId:      uuidString(inst.OwnerId),
+		Quality:      inst.Quality,
+		Float:        inst.Float,
+		PatternSeed:  int32(inst.PatternSeed),
+		Exterior:     string(inst.Exterior),
+		Attributes:   toAttributesV1(inst.Attributes),
+		Tags:         inst.Tags,
+		Status:       string(inst.Status),
+	}
+}
@@ -0,0 +1,145 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Inventory interface {
+	GetInventory(ctx context.Context, q models.InventoryQuery) (items []*models.Item, next uuid.UUID, err error)
+	TransferItem(ctx context.Context, itemID, from, to uuid.UUID) (transfer *models.OwnershipTransfer, err error)
+	GetOwnershipHistory(ctx context.Context, itemID uuid.UUID) (transfers []*models.OwnershipTransfer, err error)
+}
+
+func (s *serverAPI) GetInventory(ctx context.Context, req *itemv1.GetInventoryRequest) (*itemv1.GetInventoryResponse, error) {
+	ownerID, err := uuid.Parse(req.GetOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse owner id")
+	}
+
+	definitionID, err := parseOptionalUUID(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	// Page tokens are the ID of the last item of the previous page.
+	after, err := parseOptionalUUID(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+
+	items, next, err := s.item.GetInventory(ctx, models.InventoryQuery{
+		OwnerId:      ownerID,
+		DefinitionId: definitionID,
+		Rarity:       req.GetRarity(),
+		Quality:      req.GetQuality(),
+		Statuses:     fromStatusesV1(req.GetStatuses()),
+		PageSize:     int(req.GetPageSize()),
+		After:        after,
+	})
+	if err != nil {
+		if errors.Is(err, itemsvc.ErrInvalidOwner) {
+			return nil, status.Error(codes.InvalidArgument, "owner id is required")
+		}
+		if errors.Is(err, itemsvc.ErrInvalidStatus) {
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get inventory")
+	}
+
+	if err := s.item.LocalizeItems(ctx, requestLocale(ctx, req.GetLocale()), items...); err != nil {
+		return nil, translationStatusError(err, "failed to localize items")
+	}
+
+	resp := &itemv1.GetInventoryResponse{
+		Items:         make([]*itemv1.Item, 0, len(items)),
+		NextPageToken: uuidString(next),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toItemV1(item))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) TransferItem(ctx context.Context, req *itemv1.TransferItemRequest) (*itemv1.TransferItemResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	from, err := uuid.Parse(req.GetFromOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse from owner id")
+	}
+
+	to, err := uuid.Parse(req.GetToOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse to owner id")
+	}
+
+	transfer, err := s.item.TransferItem(ctx, itemID, from, to)
+	if err != nil {
+		var lockErr *itemsvc.ItemLockedError
+
+		switch {
+		case errors.As(err, &lockErr):
+			return nil, itemLockedStatusError(lockErr)
+		case errors.Is(err, itemsvc.ErrInvalidTransfer):
+			return nil, status.Error(codes.InvalidArgument, "invalid transfer")
+		case errors.Is(err, itemsvc.ErrItemNotFound):
+			return nil, status.Error(codes.NotFound, "item not found")
+		case errors.Is(err, itemsvc.ErrNotOwner):
+			return nil, status.Error(codes.PermissionDenied, "item is not owned by the sender")
+		case errors.Is(err, itemsvc.ErrItemNotTradable):
+			return nil, status.Error(codes.FailedPrecondition, "item is not published")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to transfer item")
+	}
+
+	return &itemv1.TransferItemResponse{
+		Transfer: toOwnershipTransferV1(transfer),
+	}, nil
+}
+
+func (s *serverAPI) GetOwnershipHistory(ctx context.Context, req *itemv1.GetOwnershipHistoryRequest) (*itemv1.GetOwnershipHistoryResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	transfers, err := s.item.GetOwnershipHistory(ctx, itemID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get ownership history")
+	}
+
+	resp := &itemv1.GetOwnershipHistoryResponse{
+		Transfers: make([]*itemv1.OwnershipTransfer, 0, len(transfers)),
+	}
+	for _, t := range transfers {
+		resp.Transfers = append(resp.Transfers, toOwnershipTransferV1(t))
+	}
+
+	return resp, nil
+}
+
+func toOwnershipTransferV1(t *models.OwnershipTransfer) *itemv1.OwnershipTransfer {
+	return &itemv1.OwnershipTransfer{
+		TransferId:    t.TransferId.String(),
+		ItemId:        t.ItemId.String(),
+		FromOwnerId:   uuidString(t.FromOwner),
+		ToOwnerId:     t.ToOwner.String(),
+		TransferredAt: timestamppb.New(t.TransferredAt),
+	}
+}
@@ -0,0 +1,136 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+// lockViolationType is the precondition failure type of locked items.
+const lockViolationType = "ITEM_LOCKED"
+
+type Locks interface {
+	LockItem(ctx context.Context, itemID uuid.UUID, reason string, ttl time.Duration) (lock *models.ItemLock, err error)
+	UnlockItem(ctx context.Context, lockID uuid.UUID) (err error)
+	GetItemLocks(ctx context.Context, itemID uuid.UUID) (locks []*models.ItemLock, err error)
+}
+
+func (s *serverAPI) LockItem(ctx context.Context, req *itemv1.LockItemRequest) (*itemv1.LockItemResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	lock, err := s.item.LockItem(ctx, itemID, req.GetReason(), req.GetExpiresIn().AsDuration())
+	if err != nil {
+		return nil, lockStatusError(err, "failed to lock item")
+	}
+
+	return &itemv1.LockItemResponse{
+		Lock: toItemLockV1(lock),
+	}, nil
+}
+
+func (s *serverAPI) UnlockItem(ctx context.Context, req *itemv1.UnlockItemRequest) (*itemv1.UnlockItemResponse, error) {
+	lockID, err := uuid.Parse(req.GetLockId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse lock id")
+	}
+
+	if err := s.item.UnlockItem(ctx, lockID); err != nil {
+		return nil, lockStatusError(err, "failed to unlock item")
+	}
+
+	return &itemv1.UnlockItemResponse{}, nil
+}
+
+func (s *serverAPI) ListItemLocks(ctx context.Context, req *itemv1.ListItemLocksRequest) (*itemv1.ListItemLocksResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	locks, err := s.item.GetItemLocks(ctx, itemID)
+	if err != nil {
+		return nil, lockStatusError(err, "failed to list item locks")
+	}
+
+	resp := &itemv1.ListItemLocksResponse{
+		Locks: make([]*itemv1.ItemLock, 0, len(locks)),
+	}
+	for _, lock := range locks {
+		resp.Locks = append(resp.Locks, toItemLockV1(lock))
+	}
+
+	return resp, nil
+}
+
+// lockStatusError maps service errors of the lock operations to gRPC status errors.
+func lockStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidLock):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrItemNotFound):
+		return status.Error(codes.NotFound, "item not found")
+	case errors.Is(err, itemsvc.ErrLockNotFound):
+		return status.Error(codes.NotFound, "item lock not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+// itemLockedStatusError returns a FailedPrecondition status error for items
+// blocked by locks, describing every lock as a precondition violation.
+func itemLockedStatusError(lockErr *itemsvc.ItemLockedError) error {
+	st := status.New(codes.FailedPrecondition, lockErr.Error())
+
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(lockErr.Locks))
+	for _, lock := range lockErr.Locks {
+		description := lock.Reason
+		if !lock.ExpiresAt.IsZero() {
+			description += " (until " + lock.ExpiresAt.UTC().Format(time.RFC3339) + ")"
+		}
+
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{
+			Type:        lockViolationType,
+			Subject:     lock.ItemId.String(),
+			Description: description,
+		})
+	}
+
+	if len(violations) == 0 {
+		return st.Err()
+	}
+
+	detailed, err := st.WithDetails(&errdetails.PreconditionFailure{Violations: violations})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+func toItemLockV1(lock *models.ItemLock) *itemv1.ItemLock {
+	v := &itemv1.ItemLock{
+		LockId:    lock.LockId.String(),
+		ItemId:    lock.ItemId.String(),
+		Reason:    lock.Reason,
+		CreatedAt: timestamppb.New(lock.CreatedAt),
+	}
+
+	if !lock.ExpiresAt.IsZero() {
+		v.ExpiresAt = timestamppb.New(lock.ExpiresAt)
+	}
+
+	return v
+}
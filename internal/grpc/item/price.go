@@ -0,0 +1,153 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Prices interface {
+	RecordPrices(ctx context.Context, obs []models.PriceObservation) (recorded int, err error)
+	GetPriceHistory(ctx context.Context, pq models.PriceHistoryQuery) (candles []*models.PriceCandle, err error)
+	ConvertMoney(m models.Money, currency string) (converted models.Money, err error)
+}
+
+func (s *serverAPI) RecordPrices(ctx context.Context, req *itemv1.RecordPricesRequest) (*itemv1.RecordPricesResponse, error) {
+	obs := make([]models.PriceObservation, 0, len(req.GetObservations()))
+	for i, o := range req.GetObservations() {
+		definitionID, err := uuid.Parse(o.GetDefinitionId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "observation %d: failed to parse definition id", i)
+		}
+
+		price, err := fromMoneyV1(o.GetPrice())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "observation %d: %s", i, err)
+		}
+
+		p := models.PriceObservation{
+			DefinitionId: definitionID,
+			Source:       o.GetSource(),
+			Price:        price,
+			Volume:       o.GetVolume(),
+			ObservedAt:   timeOf(o.GetObservedAt()),
+		}
+		if err := s.validator.Struct(p); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("observation %d: %s", i, err))
+		}
+
+		obs = append(obs, p)
+	}
+
+	recorded, err := s.item.RecordPrices(ctx, obs)
+	if err != nil {
+		return nil, priceStatusError(err, "failed to record prices")
+	}
+
+	return &itemv1.RecordPricesResponse{
+		Recorded: int32(recorded),
+	}, nil
+}
+
+func (s *serverAPI) GetPriceHistory(ctx context.Context, req *itemv1.GetPriceHistoryRequest) (*itemv1.GetPriceHistoryResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	candles, err := s.item.GetPriceHistory(ctx, models.PriceHistoryQuery{
+		DefinitionId: definitionID,
+		Currency:     req.GetCurrency(),
+		Source:       req.GetSource(),
+		Interval:     models.PriceInterval(req.GetInterval()),
+		From:         timeOf(req.GetFrom()),
+		To:           timeOf(req.GetTo()),
+	})
+	if err != nil {
+		return nil, priceStatusError(err, "failed to get price history")
+	}
+
+	resp := &itemv1.GetPriceHistoryResponse{
+		Candles: make([]*itemv1.PriceCandle, 0, len(candles)),
+	}
+	for _, c := range candles {
+		resp.Candles = append(resp.Candles, &itemv1.PriceCandle{
+			Start:        timestamppb.New(c.Start),
+			Open:         toMoneyV1(c.Open),
+			High:         toMoneyV1(c.High),
+			Low:          toMoneyV1(c.Low),
+			Close:        toMoneyV1(c.Close),
+			Volume:       c.Volume,
+			Observations: int64(c.Observations),
+		})
+	}
+
+	return resp, nil
+}
+
+// priceStatusError maps service errors of the price operations to gRPC status errors.
+func priceStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidPrices),
+		errors.Is(err, itemsvc.ErrInvalidPriceHistory),
+		errors.Is(err, itemsvc.ErrNoExchangeRate):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.NotFound, "item definition not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toValuationV1(v *models.Valuation) *itemv1.Valuation {
+	if v == nil {
+		return nil
+	}
+
+	return &itemv1.Valuation{
+		Price:        toMoneyV1(v.Price),
+		Volume:       v.Volume,
+		Observations: int64(v.Observations),
+		ObservedAt:   timestamppb.New(v.ObservedAt),
+	}
+}
+
+func toMoneyV1(m models.Money) *money.Money {
+	units, nanos := m.Units()
+
+	return &money.Money{
+		CurrencyCode: m.Currency,
+		Units:        units,
+		Nanos:        nanos,
+	}
+}
+
+// fromMoneyV1 returns the exact amount of m. It fails if m is missing or
+// has a fraction of the currency's minor unit.
+func fromMoneyV1(m *money.Money) (models.Money, error) {
+	if m == nil {
+		return models.Money{}, errors.New("price is required")
+	}
+
+	return models.MoneyFromUnits(m.GetUnits(), m.GetNanos(), m.GetCurrencyCode())
+}
+
+// timeOf returns the time of ts, or the zero time if ts is not set.
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
@@ -2,6 +2,7 @@ package item
 
 import (
 	"context"
+	"errors"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -11,13 +12,31 @@ import (
 	"google.golang.org/grpc/status"
 
 	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
 )
 
 type Item interface {
-	CreateItem(ctx context.Context, name, rarity, quality string) (itemID uuid.UUID, err error)
+	CreateItem(ctx context.Context, item *models.Item) (itemID uuid.UUID, err error)
 	GetItem(ctx context.Context, itemID uuid.UUID) (item *models.Item, err error)
-	GetAllItems(ctx context.Context) (items []*models.Item, err error)
+	GetAllItems(ctx context.Context, filter models.ItemFilter) (items []*models.Item, err error)
 	DeleteItem(ctx context.Context, itemID uuid.UUID) (err error)
+
+	ItemDefinitions
+	ItemInstances
+	Inventory
+	Trades
+	ItemAttributes
+	Collections
+	Cases
+	TradeUps
+	Prices
+	Images
+	Translations
+	Tags
+	Statuses
+	Locks
+	Imports
+	Exports
 }
 
 type serverAPI struct {
@@ -35,12 +54,27 @@ func Register(gRPC *grpc.Server, item Item) {
 }
 
 func (s *serverAPI) CreateItem(ctx context.Context, req *itemv1.CreateItemRequest) (*itemv1.CreateItemResponse, error) {
-	if err := s.validator.Struct(req); err != nil {
+	item := &models.Item{
+		Name:        req.GetName(),
+		Rarity:      req.GetRarity(),
+		Quality:     req.GetQuality(),
+		Float:       req.GetFloat(),
+		PatternSeed: int(req.GetPatternSeed()),
+		Status:      models.ItemStatus(req.GetStatus()),
+	}
+	if err := s.validator.Struct(item); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	itemID, err := s.item.CreateItem(ctx, req.GetName(), req.GetRarity(), req.GetQuality())
+	itemID, err := s.item.CreateItem(ctx, item)
 	if err != nil {
+		if errors.Is(err, itemsvc.ErrFloatOutOfRange) {
+			return nil, status.Error(codes.InvalidArgument, "item float is outside of the definition's range")
+		}
+		if errors.Is(err, itemsvc.ErrInvalidStatus) {
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		}
+
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
@@ -61,38 +95,69 @@ func (s *serverAPI) GetItem(ctx context.Context, req *itemv1.GetItemRequest) (*i
 
 	itemID, err := uuid.Parse(req.GetItemId())
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to parse item id")
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
 	}
 
 	item, err := s.item.GetItem(ctx, itemID)
 	if err != nil {
+		if errors.Is(err, itemsvc.ErrItemNotFound) {
+			return nil, status.Error(codes.NotFound, "item not found")
+		}
+
 		return nil, status.Error(codes.Internal, "failed to get item")
 	}
 
+	if currency := req.GetCurrency(); currency != "" && item.Valuation != nil {
+		if item.Valuation.Price, err = s.item.ConvertMoney(item.Valuation.Price, currency); err != nil {
+			return nil, priceStatusError(err, "failed to convert valuation")
+		}
+	}
+
+	if err := s.item.LocalizeItems(ctx, requestLocale(ctx, req.GetLocale()), item); err != nil {
+		return nil, translationStatusError(err, "failed to localize item")
+	}
+
 	return &itemv1.GetItemResponse{
-		Item: &itemv1.Item{
-			ItemId:  item.ItemId.String(),
-			Name:    item.Name,
-			Rarity:  item.Rarity,
-			Quality: item.Quality,
-		},
+		Item: toItemV1(item),
 	}, nil
 }
 
 func (s *serverAPI) GetAllItems(ctx context.Context, req *itemv1.GetAllItemsRequest) (*itemv1.GetAllItemsResponse, error) {
-	items, err := s.item.GetAllItems(ctx)
+	attrs, err := fromAttributesV1(req.GetAttributes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := models.ItemFilter{
+		MinFloat:   req.MinFloat,
+		MaxFloat:   req.MaxFloat,
+		Attributes: attrs,
+		StickerIds: req.GetStickerIds(),
+		Tags:       req.GetTags(),
+		TagMatch:   models.TagMatch(req.GetTagMatch()),
+		Statuses:   fromStatusesV1(req.GetStatuses()),
+		Sort:       models.ItemSort(req.GetSort()),
+	}
+	if filter.CollectionId, err = parseOptionalUUID(req.GetCollectionId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	items, err := s.item.GetAllItems(ctx, filter)
 	if err != nil {
+		if errors.Is(err, itemsvc.ErrInvalidFilter) {
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		}
+
 		return nil, status.Error(codes.Internal, "failed to get all items")
 	}
 
+	if err := s.item.LocalizeItems(ctx, requestLocale(ctx, req.GetLocale()), items...); err != nil {
+		return nil, translationStatusError(err, "failed to localize items")
+	}
+
 	var itemResponses []*itemv1.Item
 	for _, item := range items {
-		itemResponses = append(itemResponses, &itemv1.Item{
-			ItemId:  item.ItemId.String(),
-			Name:    item.Name,
-			Rarity:  item.Rarity,
-			Quality: item.Quality,
-		})
+		itemResponses = append(itemResponses, toItemV1(item))
 	}
 
 	response := &itemv1.GetAllItemsResponse{
@@ -113,12 +178,82 @@ func (s *serverAPI) DeleteItem(ctx context.Context, req *itemv1.DeleteItemReques
 
 	itemID, err := uuid.Parse(req.GetItemId())
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to parse item id")
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
 	}
 
 	if err := s.item.DeleteItem(ctx, itemID); err != nil {
+		var lockErr *itemsvc.ItemLockedError
+		if errors.As(err, &lockErr) {
+			return nil, itemLockedStatusError(lockErr)
+		}
+		if errors.Is(err, itemsvc.ErrItemNotFound) {
+			return nil, status.Error(codes.NotFound, "item not found")
+		}
+
 		return nil, status.Error(codes.Internal, "failed to delete item")
 	}
 
 	return &itemv1.DeleteItemResponse{}, nil
 }
+
+func toItemV1(item *models.Item) *itemv1.Item {
+	return &itemv1.Item{
+		ItemId:       item.ItemId.String(),
+		DefinitionId: item.DefinitionId.String(),
+		OwnerId:      uuidString(item.OwnerId),
+		Name:         item.Name,
+		Rarity:       item.Rarity,
+		Quality:      item.Quality,
+		Float:        item.Float,
+		PatternSeed:  int32(item.PatternSeed),
+		Exterior:     string(item.Exterior),
+		Attributes:   toAttributesV1(item.Attributes),
+		Valuation:    toValuationV1(item.Valuation),
+		Images:       toImageURLsV1(item.Images),
+		Description:  item.Description,
+		Locale:       item.Locale,
+		Tags:         item.Tags,
+		Status:       string(item.Status),
+	}
+}
+
+// parseOptionalUUID parses s, returning uuid.Nil for an empty string.
+func parseOptionalUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+
+	return uuid.Parse(s)
+}
+
+// uuidString returns the string form of id, or an empty string for uuid.Nil.
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func parseUUIDs(ss []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(ss))
+	for _, s := range ss {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	ss := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ss = append(ss, id.String())
+	}
+
+	return ss
+}
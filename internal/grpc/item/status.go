@@ -0,0 +1,122 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Statuses interface {
+	TransitionItem(ctx context.Context, itemID uuid.UUID, to models.ItemStatus, at time.Time, hold time.Duration) (lifecycle *models.ItemLifecycle, err error)
+	GetItemLifecycle(ctx context.Context, itemID uuid.UUID) (lifecycle *models.ItemLifecycle, err error)
+	CancelItemTransition(ctx context.Context, itemID uuid.UUID) (err error)
+}
+
+func (s *serverAPI) TransitionItem(ctx context.Context, req *itemv1.TransitionItemRequest) (*itemv1.TransitionItemResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	var at time.Time
+	if req.GetScheduledAt() != nil {
+		at = req.GetScheduledAt().AsTime()
+	}
+
+	lifecycle, err := s.item.TransitionItem(ctx, itemID, models.ItemStatus(req.GetStatus()), at, req.GetHold().AsDuration())
+	if err != nil {
+		return nil, statusStatusError(err, "failed to transition item")
+	}
+
+	return &itemv1.TransitionItemResponse{
+		Lifecycle: toItemLifecycleV1(lifecycle),
+	}, nil
+}
+
+func (s *serverAPI) GetItemLifecycle(ctx context.Context, req *itemv1.GetItemLifecycleRequest) (*itemv1.GetItemLifecycleResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	lifecycle, err := s.item.GetItemLifecycle(ctx, itemID)
+	if err != nil {
+		return nil, statusStatusError(err, "failed to get item lifecycle")
+	}
+
+	return &itemv1.GetItemLifecycleResponse{
+		Lifecycle: toItemLifecycleV1(lifecycle),
+	}, nil
+}
+
+func (s *serverAPI) CancelItemTransition(ctx context.Context, req *itemv1.CancelItemTransitionRequest) (*itemv1.CancelItemTransitionResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	if err := s.item.CancelItemTransition(ctx, itemID); err != nil {
+		return nil, statusStatusError(err, "failed to cancel item transition")
+	}
+
+	return &itemv1.CancelItemTransitionResponse{}, nil
+}
+
+// statusStatusError maps service errors of the status operations to gRPC status errors.
+func statusStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidStatus), errors.Is(err, itemsvc.ErrInvalidTransition):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrItemNotFound):
+		return status.Error(codes.NotFound, "item not found")
+	case errors.Is(err, itemsvc.ErrTransitionNotFound):
+		return status.Error(codes.NotFound, "scheduled transition not found")
+	case errors.Is(err, itemsvc.ErrIllegalTransition):
+		return status.Error(codes.FailedPrecondition, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrStatusConflict):
+		return status.Error(codes.Aborted, "item status was changed concurrently")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toItemLifecycleV1(l *models.ItemLifecycle) *itemv1.ItemLifecycle {
+	v := &itemv1.ItemLifecycle{
+		ItemId: l.ItemId.String(),
+		Status: string(l.Status),
+	}
+
+	if l.Scheduled != nil {
+		v.Scheduled = &itemv1.ScheduledTransition{
+			Status:    string(l.Scheduled.To),
+			DueAt:     timestamppb.New(l.Scheduled.DueAt),
+			CreatedAt: timestamppb.New(l.Scheduled.CreatedAt),
+		}
+	}
+
+	return v
+}
+
+// fromStatusesV1 converts the statuses of a listing filter; they are
+// validated by the service.
+func fromStatusesV1(ss []string) []models.ItemStatus {
+	if len(ss) == 0 {
+		return nil
+	}
+
+	statuses := make([]models.ItemStatus, 0, len(ss))
+	for _, s := range ss {
+		statuses = append(statuses, models.ItemStatus(s))
+	}
+
+	return statuses
+}
@@ -0,0 +1,114 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Tags interface {
+	AddTags(ctx context.Context, itemID uuid.UUID, tags []string) (saved []string, err error)
+	RemoveTags(ctx context.Context, itemID uuid.UUID, tags []string) (saved []string, err error)
+	GetFacets(ctx context.Context, filter models.ItemFilter) (facets *models.Facets, err error)
+}
+
+func (s *serverAPI) AddTags(ctx context.Context, req *itemv1.AddTagsRequest) (*itemv1.AddTagsResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	tags, err := s.item.AddTags(ctx, itemID, req.GetTags())
+	if err != nil {
+		return nil, tagStatusError(err, "failed to add tags")
+	}
+
+	return &itemv1.AddTagsResponse{
+		Tags: tags,
+	}, nil
+}
+
+func (s *serverAPI) RemoveTags(ctx context.Context, req *itemv1.RemoveTagsRequest) (*itemv1.RemoveTagsResponse, error) {
+	itemID, err := uuid.Parse(req.GetItemId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item id")
+	}
+
+	tags, err := s.item.RemoveTags(ctx, itemID, req.GetTags())
+	if err != nil {
+		return nil, tagStatusError(err, "failed to remove tags")
+	}
+
+	return &itemv1.RemoveTagsResponse{
+		Tags: tags,
+	}, nil
+}
+
+func (s *serverAPI) GetFacets(ctx context.Context, req *itemv1.GetFacetsRequest) (*itemv1.GetFacetsResponse, error) {
+	attrs, err := fromAttributesV1(req.GetAttributes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := models.ItemFilter{
+		MinFloat:   req.MinFloat,
+		MaxFloat:   req.MaxFloat,
+		Attributes: attrs,
+		StickerIds: req.GetStickerIds(),
+		Tags:       req.GetTags(),
+		TagMatch:   models.TagMatch(req.GetTagMatch()),
+		Statuses:   fromStatusesV1(req.GetStatuses()),
+	}
+	if filter.CollectionId, err = parseOptionalUUID(req.GetCollectionId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse collection id")
+	}
+
+	facets, err := s.item.GetFacets(ctx, filter)
+	if err != nil {
+		if errors.Is(err, itemsvc.ErrInvalidFilter) {
+			return nil, status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get facets")
+	}
+
+	return &itemv1.GetFacetsResponse{
+		Total:     facets.Total,
+		Rarities:  toFacetCountsV1(facets.Rarities),
+		Qualities: toFacetCountsV1(facets.Qualities),
+		Tags:      toFacetCountsV1(facets.Tags),
+	}, nil
+}
+
+// tagStatusError maps service errors of the tag operations to gRPC status errors.
+func tagStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidTags):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrTooManyTags):
+		return status.Error(codes.FailedPrecondition, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrItemNotFound):
+		return status.Error(codes.NotFound, "item not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toFacetCountsV1(counts []models.FacetCount) []*itemv1.FacetCount {
+	fcs := make([]*itemv1.FacetCount, 0, len(counts))
+	for _, fc := range counts {
+		fcs = append(fcs, &itemv1.FacetCount{
+			Value: fc.Value,
+			Count: fc.Count,
+		})
+	}
+
+	return fcs
+}
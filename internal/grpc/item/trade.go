@@ -0,0 +1,204 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Trades interface {
+	CreateTradeOffer(
+		ctx context.Context,
+		proposerID, recipientID uuid.UUID,
+		proposerItems, recipientItems []uuid.UUID,
+		ttl time.Duration,
+	) (offer *models.TradeOffer, err error)
+	AcceptTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (offer *models.TradeOffer, err error)
+	DeclineTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (offer *models.TradeOffer, err error)
+	CancelTradeOffer(ctx context.Context, tradeID, proposerID uuid.UUID) (offer *models.TradeOffer, err error)
+	GetTradeOffer(ctx context.Context, tradeID uuid.UUID) (offer *models.TradeOffer, err error)
+	GetTradeOffers(ctx context.Context, q models.TradeQuery) (offers []*models.TradeOffer, err error)
+}
+
+func (s *serverAPI) CreateTradeOffer(ctx context.Context, req *itemv1.CreateTradeOfferRequest) (*itemv1.CreateTradeOfferResponse, error) {
+	proposerID, err := uuid.Parse(req.GetProposerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse proposer id")
+	}
+
+	recipientID, err := uuid.Parse(req.GetRecipientId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse recipient id")
+	}
+
+	proposerItems, err := parseUUIDs(req.GetProposerItemIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse proposer item ids")
+	}
+
+	recipientItems, err := parseUUIDs(req.GetRecipientItemIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse recipient item ids")
+	}
+
+	offer, err := s.item.CreateTradeOffer(ctx, proposerID, recipientID, proposerItems, recipientItems, req.GetExpiresIn().AsDuration())
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to create trade offer")
+	}
+
+	return &itemv1.CreateTradeOfferResponse{
+		TradeOffer: toTradeOfferV1(offer),
+	}, nil
+}
+
+func (s *serverAPI) AcceptTradeOffer(ctx context.Context, req *itemv1.AcceptTradeOfferRequest) (*itemv1.AcceptTradeOfferResponse, error) {
+	tradeID, recipientID, err := parseTradeParticipant(req.GetTradeId(), req.GetRecipientId())
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.item.AcceptTradeOffer(ctx, tradeID, recipientID)
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to accept trade offer")
+	}
+
+	return &itemv1.AcceptTradeOfferResponse{
+		TradeOffer: toTradeOfferV1(offer),
+	}, nil
+}
+
+func (s *serverAPI) DeclineTradeOffer(ctx context.Context, req *itemv1.DeclineTradeOfferRequest) (*itemv1.DeclineTradeOfferResponse, error) {
+	tradeID, recipientID, err := parseTradeParticipant(req.GetTradeId(), req.GetRecipientId())
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.item.DeclineTradeOffer(ctx, tradeID, recipientID)
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to decline trade offer")
+	}
+
+	return &itemv1.DeclineTradeOfferResponse{
+		TradeOffer: toTradeOfferV1(offer),
+	}, nil
+}
+
+func (s *serverAPI) CancelTradeOffer(ctx context.Context, req *itemv1.CancelTradeOfferRequest) (*itemv1.CancelTradeOfferResponse, error) {
+	tradeID, proposerID, err := parseTradeParticipant(req.GetTradeId(), req.GetProposerId())
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.item.CancelTradeOffer(ctx, tradeID, proposerID)
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to cancel trade offer")
+	}
+
+	return &itemv1.CancelTradeOfferResponse{
+		TradeOffer: toTradeOfferV1(offer),
+	}, nil
+}
+
+func (s *serverAPI) GetTradeOffer(ctx context.Context, req *itemv1.GetTradeOfferRequest) (*itemv1.GetTradeOfferResponse, error) {
+	tradeID, err := uuid.Parse(req.GetTradeId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse trade id")
+	}
+
+	offer, err := s.item.GetTradeOffer(ctx, tradeID)
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to get trade offer")
+	}
+
+	return &itemv1.GetTradeOfferResponse{
+		TradeOffer: toTradeOfferV1(offer),
+	}, nil
+}
+
+func (s *serverAPI) ListTradeOffers(ctx context.Context, req *itemv1.ListTradeOffersRequest) (*itemv1.ListTradeOffersResponse, error) {
+	ownerID, err := uuid.Parse(req.GetOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse owner id")
+	}
+
+	offers, err := s.item.GetTradeOffers(ctx, models.TradeQuery{
+		OwnerId: ownerID,
+		Status:  models.TradeStatus(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, tradeStatusError(err, "failed to list trade offers")
+	}
+
+	resp := &itemv1.ListTradeOffersResponse{
+		TradeOffers: make([]*itemv1.TradeOffer, 0, len(offers)),
+	}
+	for _, offer := range offers {
+		resp.TradeOffers = append(resp.TradeOffers, toTradeOfferV1(offer))
+	}
+
+	return resp, nil
+}
+
+func parseTradeParticipant(tradeID, participantID string) (uuid.UUID, uuid.UUID, error) {
+	trade, err := uuid.Parse(tradeID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "failed to parse trade id")
+	}
+
+	participant, err := uuid.Parse(participantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "failed to parse participant id")
+	}
+
+	return trade, participant, nil
+}
+
+// tradeStatusError maps service errors of the trade operations to gRPC status errors.
+func tradeStatusError(err error, msg string) error {
+	var lockErr *itemsvc.ItemLockedError
+
+	switch {
+	case errors.As(err, &lockErr):
+		return itemLockedStatusError(lockErr)
+	case errors.Is(err, itemsvc.ErrInvalidTrade), errors.Is(err, itemsvc.ErrInvalidOwner):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, itemsvc.ErrTradeNotFound):
+		return status.Error(codes.NotFound, "trade offer not found")
+	case errors.Is(err, itemsvc.ErrNotTradeParticipant):
+		return status.Error(codes.PermissionDenied, "not a participant of the trade offer")
+	case errors.Is(err, itemsvc.ErrTradeNotPending),
+		errors.Is(err, itemsvc.ErrTradeExpired),
+		errors.Is(err, itemsvc.ErrTradeItemsUnavailable):
+		return status.Error(codes.FailedPrecondition, errors.Unwrap(err).Error())
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toTradeOfferV1(offer *models.TradeOffer) *itemv1.TradeOffer {
+	v := &itemv1.TradeOffer{
+		TradeId:          offer.TradeId.String(),
+		ProposerId:       offer.ProposerId.String(),
+		RecipientId:      offer.RecipientId.String(),
+		ProposerItemIds:  uuidStrings(offer.ProposerItems),
+		RecipientItemIds: uuidStrings(offer.RecipientItems),
+		Status:           string(offer.Status),
+		CreatedAt:        timestamppb.New(offer.CreatedAt),
+		ExpiresAt:        timestamppb.New(offer.ExpiresAt),
+	}
+
+	if !offer.ResolvedAt.IsZero() {
+		v.ResolvedAt = timestamppb.New(offer.ResolvedAt)
+	}
+
+	return v
+}
@@ -0,0 +1,112 @@
+package item
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type TradeUps interface {
+	TradeUp(ctx context.Context, ownerID uuid.UUID, itemIDs []uuid.UUID) (tu *models.TradeUp, err error)
+	GetTradeUp(ctx context.Context, tradeUpID uuid.UUID) (tu *models.TradeUp, err error)
+}
+
+func (s *serverAPI) TradeUp(ctx context.Context, req *itemv1.TradeUpRequest) (*itemv1.TradeUpResponse, error) {
+	ownerID, err := uuid.Parse(req.GetOwnerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse owner id")
+	}
+
+	itemIDs, err := parseUUIDs(req.GetItemIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse item ids")
+	}
+
+	tu, err := s.item.TradeUp(ctx, ownerID, itemIDs)
+	if err != nil {
+		return nil, tradeUpStatusError(err, "failed to trade up")
+	}
+
+	return &itemv1.TradeUpResponse{
+		TradeUp: toTradeUpV1(tu),
+	}, nil
+}
+
+func (s *serverAPI) GetTradeUp(ctx context.Context, req *itemv1.GetTradeUpRequest) (*itemv1.GetTradeUpResponse, error) {
+	tradeUpID, err := uuid.Parse(req.GetTradeUpId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse trade-up id")
+	}
+
+	tu, err := s.item.GetTradeUp(ctx, tradeUpID)
+	if err != nil {
+		return nil, tradeUpStatusError(err, "failed to get trade-up")
+	}
+
+	return &itemv1.GetTradeUpResponse{
+		TradeUp: toTradeUpV1(tu),
+	}, nil
+}
+
+// tradeUpStatusError maps service errors of the trade-up operations to gRPC status errors.
+func tradeUpStatusError(err error, msg string) error {
+	var lockErr *itemsvc.ItemLockedError
+
+	switch {
+	case errors.As(err, &lockErr):
+		return itemLockedStatusError(lockErr)
+	case errors.Is(err, itemsvc.ErrInvalidOwner):
+		return status.Error(codes.InvalidArgument, "invalid owner")
+	case errors.Is(err, itemsvc.ErrInvalidTradeUp):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrItemNotFound):
+		return status.Error(codes.NotFound, "item not found")
+	case errors.Is(err, itemsvc.ErrTradeUpNotFound):
+		return status.Error(codes.NotFound, "trade-up not found")
+	case errors.Is(err, itemsvc.ErrNotOwner):
+		return status.Error(codes.PermissionDenied, "item is not owned by the owner")
+	case errors.Is(err, itemsvc.ErrItemNotTradable):
+		return status.Error(codes.FailedPrecondition, "item is not published")
+	case errors.Is(err, itemsvc.ErrNoTradeUpOutcome):
+		return status.Error(codes.FailedPrecondition, "no items of the next rarity in the collections of the inputs")
+	case errors.Is(err, itemsvc.ErrTradeUpInputsUnavailable):
+		return status.Error(codes.Aborted, "trade-up inputs are no longer held by the owner")
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.Aborted, "output item definition was deleted")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toTradeUpV1(tu *models.TradeUp) *itemv1.TradeUp {
+	inputs := make([]*itemv1.TradeUpInput, 0, len(tu.Inputs))
+	for _, in := range tu.Inputs {
+		inputs = append(inputs, &itemv1.TradeUpInput{
+			ItemId:       in.ItemId.String(),
+			DefinitionId: in.DefinitionId.String(),
+			Float:        in.Float,
+		})
+	}
+
+	return &itemv1.TradeUp{
+		TradeUpId:          tu.TradeUpId.String(),
+		OwnerId:            tu.OwnerId.String(),
+		Inputs:             inputs,
+		OutputItemId:       tu.OutputItemId.String(),
+		OutputDefinitionId: tu.OutputDefinitionId.String(),
+		OutputRarity:       tu.OutputRarity,
+		OutputFloat:        tu.OutputFloat,
+		OutputPatternSeed:  int32(tu.OutputPatternSeed),
+		OutputExterior:     string(models.ExteriorOf(tu.OutputFloat)),
+		Seed:               tu.Seed,
+		CreatedAt:          timestamppb.New(tu.CreatedAt),
+	}
+}
@@ -0,0 +1,143 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"item-service/internal/domain/models"
+	itemsvc "item-service/internal/service"
+)
+
+type Translations interface {
+	SetTranslation(ctx context.Context, t *models.Translation) (saved *models.Translation, err error)
+	DeleteTranslation(ctx context.Context, definitionID uuid.UUID, locale string) (err error)
+	GetTranslations(ctx context.Context, definitionID uuid.UUID) (translations []*models.Translation, err error)
+	SearchItemDefinitions(ctx context.Context, query, accept string, limit int) (defs []*models.ItemDefinition, err error)
+	LocalizeItems(ctx context.Context, accept string, items ...*models.Item) (err error)
+	LocalizeDefinitions(ctx context.Context, accept string, defs ...*models.ItemDefinition) (err error)
+}
+
+func (s *serverAPI) SetItemTranslation(ctx context.Context, req *itemv1.SetItemTranslationRequest) (*itemv1.SetItemTranslationResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	t := &models.Translation{
+		DefinitionId: definitionID,
+		Locale:       req.GetLocale(),
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+	}
+	if err := s.validator.Struct(t); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	saved, err := s.item.SetTranslation(ctx, t)
+	if err != nil {
+		return nil, translationStatusError(err, "failed to set translation")
+	}
+
+	return &itemv1.SetItemTranslationResponse{
+		Translation: toTranslationV1(saved),
+	}, nil
+}
+
+func (s *serverAPI) DeleteItemTranslation(ctx context.Context, req *itemv1.DeleteItemTranslationRequest) (*itemv1.DeleteItemTranslationResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	if err := s.item.DeleteTranslation(ctx, definitionID, req.GetLocale()); err != nil {
+		return nil, translationStatusError(err, "failed to delete translation")
+	}
+
+	return &itemv1.DeleteItemTranslationResponse{}, nil
+}
+
+func (s *serverAPI) ListItemTranslations(ctx context.Context, req *itemv1.ListItemTranslationsRequest) (*itemv1.ListItemTranslationsResponse, error) {
+	definitionID, err := uuid.Parse(req.GetDefinitionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse definition id")
+	}
+
+	translations, err := s.item.GetTranslations(ctx, definitionID)
+	if err != nil {
+		return nil, translationStatusError(err, "failed to list translations")
+	}
+
+	resp := &itemv1.ListItemTranslationsResponse{
+		Translations: make([]*itemv1.Translation, 0, len(translations)),
+	}
+	for _, t := range translations {
+		resp.Translations = append(resp.Translations, toTranslationV1(t))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) SearchItemDefinitions(ctx context.Context, req *itemv1.SearchItemDefinitionsRequest) (*itemv1.SearchItemDefinitionsResponse, error) {
+	defs, err := s.item.SearchItemDefinitions(ctx, req.GetQuery(), requestLocale(ctx, req.GetLocale()), int(req.GetLimit()))
+	if err != nil {
+		return nil, translationStatusError(err, "failed to search item definitions")
+	}
+
+	resp := &itemv1.SearchItemDefinitionsResponse{
+		Definitions: make([]*itemv1.ItemDefinition, 0, len(defs)),
+	}
+	for _, def := range defs {
+		resp.Definitions = append(resp.Definitions, toItemDefinitionV1(def))
+	}
+
+	return resp, nil
+}
+
+// requestLocale returns the locales accepted by the caller: the locale
+// field of the request if set, else the accept-language metadata.
+func requestLocale(ctx context.Context, locale string) string {
+	if locale != "" {
+		return locale
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	return strings.Join(md.Get("accept-language"), ",")
+}
+
+// translationStatusError maps service errors of the translation operations to gRPC status errors.
+func translationStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrInvalidLocale),
+		errors.Is(err, itemsvc.ErrUnsupportedLocale),
+		errors.Is(err, itemsvc.ErrInvalidSearch):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, itemsvc.ErrDefinitionNotFound):
+		return status.Error(codes.NotFound, "item definition not found")
+	case errors.Is(err, itemsvc.ErrTranslationNotFound):
+		return status.Error(codes.NotFound, "translation not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func toTranslationV1(t *models.Translation) *itemv1.Translation {
+	return &itemv1.Translation{
+		DefinitionId: t.DefinitionId.String(),
+		Locale:       t.Locale,
+		Name:         t.Name,
+		Description:  t.Description,
+		UpdatedAt:    timestamppb.New(t.UpdatedAt),
+	}
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"item-service/internal/lib/logger/levels"
+)
+
+type logLevelAPI struct {
+	levels *levels.Registry
+}
+
+type setLevelRequest struct {
+	// Component is one of the registered components; empty sets the root level.
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	// TTL reverts the change after the given duration, e.g. "15m".
+	TTL string `json:"ttl"`
+}
+
+// Register registers the admin endpoints on mux.
+//
+//	GET    /loglevel                    current levels
+//	PUT    /loglevel                    {"component": "storage", "level": "debug", "ttl": "15m"}
+//	DELETE /loglevel?component=storage  drop a component override
+func Register(mux *http.ServeMux, lv *levels.Registry) {
+	api := &logLevelAPI{levels: lv}
+
+	mux.HandleFunc("/loglevel", api.handle)
+}
+
+func (a *logLevelAPI) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, a.levels.Snapshot())
+	case http.MethodPut, http.MethodPost:
+		a.set(w, r)
+	case http.MethodDelete:
+		a.reset(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (a *logLevelAPI) set(w http.ResponseWriter, r *http.Request) {
+	var req setLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid level: "+req.Level)
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl < 0 {
+			writeError(w, http.StatusBadRequest, "invalid ttl: "+req.TTL)
+			return
+		}
+	}
+
+	if err := a.levels.Set(req.Component, level, ttl); err != nil {
+		if errors.Is(err, levels.ErrUnknownComponent) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a.levels.Snapshot())
+}
+
+func (a *logLevelAPI) reset(w http.ResponseWriter, r *http.Request) {
+	if err := a.levels.Reset(r.URL.Query().Get("component")); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a.levels.Snapshot())
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/lib/snapshot"
+	"item-service/internal/storage"
+)
+
+type snapshotAPI struct {
+	log     *slog.Logger
+	storage storage.Snapshotter
+	token   string
+}
+
+type restoreResponse struct {
+	Header snapshot.Header `json:"header"`
+	Rows   map[string]int  `json:"rows"`
+}
+
+// RegisterSnapshot registers the snapshot endpoints on mux. They work with
+// every storage driver, including the in-memory one. Requests must carry
+// the token as "Authorization: Bearer <token>"; the token must not be
+// empty.
+//
+//	GET /snapshot  download a snapshot of the database
+//	PUT /snapshot  restore the snapshot in the body into the empty database
+func RegisterSnapshot(mux *http.ServeMux, log *slog.Logger, s storage.Snapshotter, token string) {
+	if token == "" {
+		panic("admin: snapshot endpoints without a token")
+	}
+
+	api := &snapshotAPI{log: log, storage: s, token: token}
+
+	mux.HandleFunc("/snapshot", api.handle)
+}
+
+func (a *snapshotAPI) handle(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		a.snapshot(w, r)
+	case http.MethodPut, http.MethodPost:
+		a.restore(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (a *snapshotAPI) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1
+}
+
+func (a *snapshotAPI) snapshot(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.snapshot"
+
+	log := a.log.With(slog.String("op", op))
+
+	name := fmt.Sprintf("item-snapshot-%s.jsonl.gz", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	rows, err := storage.WriteSnapshot(r.Context(), a.storage, w)
+	if err != nil {
+		log.Error("failed to write snapshot", sl.Err(err))
+
+		// The status is sent with the first row already; aborting the
+		// response leaves the client with a snapshot it cannot restore.
+		panic(http.ErrAbortHandler)
+	}
+
+	log.Info("snapshot written", slog.Any("rows", rows))
+}
+
+func (a *snapshotAPI) restore(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.restore"
+
+	log := a.log.With(slog.String("op", op))
+
+	h, rows, err := storage.RestoreSnapshot(r.Context(), a.storage, r.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, snapshot.ErrInvalid),
+			errors.Is(err, snapshot.ErrUnsupportedVersion),
+			errors.Is(err, snapshot.ErrTruncated):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, storage.ErrNotEmpty), errors.Is(err, storage.ErrSchemaMismatch):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.Error("failed to restore snapshot", sl.Err(err))
+			writeError(w, http.StatusInternalServerError, "internal error")
+		}
+
+		return
+	}
+
+	log.Info("snapshot restored", slog.Time("created_at", h.CreatedAt), slog.Any("rows", rows))
+
+	writeJSON(w, http.StatusOK, restoreResponse{Header: h, Rows: rows})
+}
@@ -0,0 +1,203 @@
+package itemctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultConcurrency = 4
+
+// batchResult is the outcome of one line of a batch.
+type batchResult struct {
+	line int
+	req  proto.Message
+	resp proto.Message
+	err  error
+	done chan struct{}
+}
+
+func (g *globals) batchCommand(rpcs map[string]*rpc) *cobra.Command {
+	var (
+		file        string
+		concurrency int
+		failFast    bool
+	)
+
+	names := make([]string, 0, len(rpcs))
+	for name, r := range rpcs {
+		if r.unary() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	cmd := &cobra.Command{
+		Use:   "batch <command> --file requests.jsonl",
+		Short: "Call a method once for every JSON line of a file",
+		Long: `batch reads one request per line as JSON, in the format of --data, and
+calls the method for each of them concurrently. Responses are printed in the
+order of the lines; failed lines are reported on stderr and make the batch
+fail. Empty lines and lines starting with # are skipped.`,
+		Example: `  itemctl batch delete-item --file deletes.jsonl
+  jq -c '.[] | {item_id: .id, reason: "fraud"}' flagged.json | itemctl batch lock-item -f - -c 16`,
+		GroupID:           groupTools,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cobra.FixedCompletions(names, cobra.ShellCompDirectiveNoFileComp),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, ok := rpcs[args[0]]
+			if !ok || !r.unary() {
+				return fmt.Errorf("unknown command %q, batches call one of the unary methods", args[0])
+			}
+
+			if concurrency < 1 {
+				return fmt.Errorf("the concurrency must be positive")
+			}
+
+			var rd io.Reader = cmd.InOrStdin()
+			if file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				rd = f
+			}
+
+			results, err := r.batchRequests(rd)
+			if err != nil {
+				return err
+			}
+
+			s, err := g.session(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.close()
+
+			return r.runBatch(cmd, s, results, concurrency, failFast)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "file of JSON requests, - for stdin")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", defaultConcurrency, "number of calls in flight")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "stop at the first failed call")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// batchRequests parses the lines of a batch. Lines that fail to parse are
+// failed results.
+func (r *rpc) batchRequests(rd io.Reader) ([]*batchResult, error) {
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+
+	var results []*batchResult
+	for line := 1; sc.Scan(); line++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+
+		res := &batchResult{line: line, done: make(chan struct{})}
+
+		req := newMessage(r.desc.Input())
+		if err := protojson.Unmarshal(text, req.Interface()); err != nil {
+			res.err = fmt.Errorf("parse request: %w", err)
+		} else {
+			res.req = req.Interface()
+		}
+
+		results = append(results, res)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *rpc) runBatch(cmd *cobra.Command, s *session, results []*batchResult, concurrency int, failFast bool) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	jobs := make(chan *batchResult)
+	go func() {
+		defer close(jobs)
+
+		for _, res := range results {
+			jobs <- res
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for res := range jobs {
+				if res.err == nil {
+					res.resp, res.err = r.batchCall(ctx, s, res.req)
+				}
+				if res.err != nil && failFast {
+					cancel()
+				}
+				close(res.done)
+			}
+		}()
+	}
+
+	failed := 0
+	for _, res := range results {
+		<-res.done
+
+		if res.err != nil {
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "line %d: %s\n", res.line, describeError(res.err))
+
+			continue
+		}
+
+		if err := s.printer.print(res.resp); err != nil {
+			return err
+		}
+	}
+
+	wg.Wait()
+
+	if err := s.printer.flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func (r *rpc) batchCall(ctx context.Context, s *session, req proto.Message) (proto.Message, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	resp := newMessage(r.desc.Output())
+	if err := s.conn.Invoke(ctx, r.path, req, resp.Interface()); err != nil {
+		return nil, err
+	}
+
+	return resp.Interface(), nil
+}
@@ -0,0 +1,368 @@
+package itemctl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configPathEnv = "ITEMCTL_CONFIG"
+	profileEnv    = "ITEMCTL_PROFILE"
+	tokenEnv      = "ITEMCTL_TOKEN"
+
+	defaultAddress = "localhost:44044"
+	defaultTimeout = 10 * time.Second
+)
+
+// Config is the itemctl configuration file. It holds named connection
+// profiles, one per environment, and the profile used by default.
+type Config struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile describes how to reach and authenticate to an item service.
+type Profile struct {
+	Address string `yaml:"address,omitempty"`
+
+	TLS                bool   `yaml:"tls,omitempty"`
+	CAFile             string `yaml:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+
+	// Token is sent as a bearer token; TokenFile is read when Token is empty.
+	Token     string `yaml:"token,omitempty"`
+	TokenFile string `yaml:"token_file,omitempty"`
+	// Headers are added to the metadata of every call.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	Output  string        `yaml:"output,omitempty"`
+	Locale  string        `yaml:"locale,omitempty"`
+}
+
+// defaultConfigPath returns the configuration file used without --config.
+func defaultConfigPath() string {
+	if path := os.Getenv(configPathEnv); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".itemctl.yaml"
+	}
+
+	return filepath.Join(dir, "itemctl", "config.yaml")
+}
+
+// loadConfig reads the configuration file. A missing file is an empty
+// configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]*Profile{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+
+	return cfg, nil
+}
+
+// save writes the configuration file, readable by the user only since
+// profiles may hold tokens.
+func (c *Config) save(path string) error {
+	data, err := marshalYAML(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// profile returns the named profile, or the current one if name is empty.
+// Without any profile the defaults are used.
+func (c *Config) profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.CurrentProfile
+	}
+
+	if name == "" {
+		return &Profile{}, nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in the config", name)
+	}
+
+	clone := *p
+
+	return &clone, nil
+}
+
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// token returns the bearer token of the profile.
+func (p *Profile) token() (string, error) {
+	if p.Token != "" || p.TokenFile == "" {
+		return p.Token, nil
+	}
+
+	data, err := os.ReadFile(p.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("read token file: %w", err)
+	}
+
+	return string(trimNewline(data)), nil
+}
+
+// absPaths makes the file paths of the profile absolute, so that it works
+// from any directory.
+func (p *Profile) absPaths() error {
+	for _, path := range []*string{&p.CAFile, &p.CertFile, &p.KeyFile, &p.TokenFile} {
+		if *path == "" {
+			continue
+		}
+
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			return err
+		}
+		*path = abs
+	}
+
+	return nil
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func trimNewline(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+
+	return b
+}
+
+// redactedValue replaces secrets in config view.
+const redactedValue = "REDACTED"
+
+func (g *globals) configCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manage connection profiles",
+		GroupID: groupTools,
+	}
+
+	view := &cobra.Command{
+		Use:   "view",
+		Short: "Print the config file with secrets redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := loadConfig(g.config)
+			if err != nil {
+				return err
+			}
+
+			data, err := marshalYAML(cfg.redacted())
+			if err != nil {
+				return err
+			}
+
+			_, err = cmd.OutOrStdout().Write(data)
+
+			return err
+		},
+	}
+
+	profiles := &cobra.Command{
+		Use:     "profiles",
+		Aliases: []string{"list"},
+		Short:   "List the profiles, marking the current one",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := loadConfig(g.config)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CURRENT\tNAME\tADDRESS\tTLS")
+			for _, name := range cfg.profileNames() {
+				current := ""
+				if name == cfg.CurrentProfile {
+					current = "*"
+				}
+
+				p := cfg.Profiles[name]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", current, name, p.Address, p.TLS)
+			}
+
+			return w.Flush()
+		},
+	}
+
+	use := &cobra.Command{
+		Use:               "use <profile>",
+		Short:             "Make the profile the current one",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: g.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(g.config)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found in the config", args[0])
+			}
+			cfg.CurrentProfile = args[0]
+
+			return cfg.save(g.config)
+		},
+	}
+
+	setProfile := &cobra.Command{
+		Use:   "set-profile <profile>",
+		Short: "Create or update a profile from the connection flags",
+		Long: `set-profile saves the connection flags given on the command line to the
+profile, creating it if needed. The first profile becomes the current one.`,
+		Example:           `  itemctl config set-profile prod --address items.prod:44044 --tls --ca-file ca.pem --token-file ~/.prod-token -H x-team=ops`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: g.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(g.config)
+			if err != nil {
+				return err
+			}
+
+			p, ok := cfg.Profiles[args[0]]
+			if !ok {
+				p = &Profile{}
+				cfg.Profiles[args[0]] = p
+			}
+
+			if err := g.override(cmd.Flags(), p); err != nil {
+				return err
+			}
+
+			if err := p.absPaths(); err != nil {
+				return err
+			}
+
+			if cfg.CurrentProfile == "" {
+				cfg.CurrentProfile = args[0]
+			}
+
+			return cfg.save(g.config)
+		},
+	}
+
+	deleteProfile := &cobra.Command{
+		Use:               "delete-profile <profile>",
+		Short:             "Delete a profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: g.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(g.config)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found in the config", args[0])
+			}
+			delete(cfg.Profiles, args[0])
+
+			if cfg.CurrentProfile == args[0] {
+				cfg.CurrentProfile = ""
+			}
+
+			return cfg.save(g.config)
+		},
+	}
+
+	cmd.AddCommand(view, profiles, use, setProfile, deleteProfile)
+
+	return cmd
+}
+
+// redacted returns a copy of the config without tokens and authorization
+// headers.
+func (c *Config) redacted() *Config {
+	out := &Config{
+		CurrentProfile: c.CurrentProfile,
+		Profiles:       make(map[string]*Profile, len(c.Profiles)),
+	}
+
+	for name, p := range c.Profiles {
+		clone := *p
+		if clone.Token != "" {
+			clone.Token = redactedValue
+		}
+
+		if len(p.Headers) > 0 {
+			clone.Headers = make(map[string]string, len(p.Headers))
+			for k, v := range p.Headers {
+				if strings.EqualFold(k, "authorization") {
+					v = redactedValue
+				}
+				clone.Headers[k] = v
+			}
+		}
+
+		out.Profiles[name] = &clone
+	}
+
+	return out
+}
@@ -0,0 +1,178 @@
+package itemctl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sessionHeader identifies the itemctl invocation to the service, so that
+// reads after writes of one invocation are served by the primary.
+const sessionHeader = "x-session-id"
+
+// dial connects to the item service described by the profile.
+func dial(p *Profile) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials(p)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(p.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", p.Address, err)
+	}
+
+	return conn, nil
+}
+
+func transportCredentials(p *Profile) (credentials.TransportCredentials, error) {
+	if !p.TLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         p.ServerName,
+		InsecureSkipVerify: p.InsecureSkipVerify, //nolint:gosec // opted into per profile
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if p.CAFile != "" {
+		pem, err := os.ReadFile(p.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", p.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if p.CertFile != "" || p.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// outgoingMetadata returns the metadata sent with every call of the
+// invocation.
+func outgoingMetadata(p *Profile) (metadata.MD, error) {
+	md := metadata.MD{}
+
+	for k, v := range p.Headers {
+		md.Append(strings.ToLower(k), v)
+	}
+
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		md.Set("authorization", "Bearer "+token)
+	}
+
+	if p.Locale != "" {
+		md.Set("accept-language", p.Locale)
+	}
+
+	if len(md.Get(sessionHeader)) == 0 {
+		md.Set(sessionHeader, "itemctl-"+uuid.NewString())
+	}
+
+	return md, nil
+}
+
+// session is the connection and settings of one itemctl invocation.
+type session struct {
+	profile *Profile
+	md      metadata.MD
+	conn    *grpc.ClientConn
+	printer *printer
+}
+
+func newSession(p *Profile, out io.Writer) (*session, error) {
+	pr, err := newPrinter(p.Output, out)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := outgoingMetadata(p)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		profile: p,
+		md:      md,
+		conn:    conn,
+		printer: pr,
+	}, nil
+}
+
+func (s *session) close() {
+	s.conn.Close()
+}
+
+// outgoing attaches the metadata of the invocation to ctx.
+func (s *session) outgoing(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, s.md)
+}
+
+// callContext returns the context of a unary call.
+func (s *session) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = s.outgoing(ctx)
+
+	if s.profile.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.profile.Timeout)
+}
+
+// describeError formats gRPC status errors with their code and the
+// precondition violations attached by the service.
+func describeError(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", st.Code(), st.Message())
+
+	for _, detail := range st.Details() {
+		failure, ok := detail.(*errdetails.PreconditionFailure)
+		if !ok {
+			continue
+		}
+
+		for _, v := range failure.GetViolations() {
+			fmt.Fprintf(&b, "\n  %s %s: %s", v.GetType(), v.GetSubject(), v.GetDescription())
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,211 @@
+package itemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"item-service/internal/lib/itemio"
+)
+
+// exportedItemsTrailer is the trailer of ExportItems carrying the number of
+// exported items.
+const exportedItemsTrailer = "x-exported-items"
+
+func (g *globals) exportCommand(r *rpc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export items to a CSV, JSON Lines or Parquet file",
+		Long: `export streams the items matching the filter flags from ExportItems into
+a file, or to stdout if no file or - is given. The service writes the file, so
+it is ready for analytics tools as it is.
+
+The format and compression are taken from the file name unless given, e.g.
+items.csv.gz is gzipped CSV and items.parquet is Parquet. Parquet files are
+compressed page by page with snappy unless another compression is given.
+
+The file is written to a temporary file next to it and renamed when the
+export is complete, so an interrupted export leaves no partial file behind.`,
+		Example: `  itemctl export items.parquet --compression zstd
+  itemctl export items.csv.gz --statuses published --min-float 0.07
+  itemctl export --format jsonl | jq .name`,
+		GroupID: groupTools,
+		Args:    cobra.MaximumNArgs(1),
+	}
+
+	rf := r.addFlags(cmd.Flags())
+	for _, ff := range rf.fields {
+		if ff.fd.Kind() == protoreflect.EnumKind {
+			_ = cmd.RegisterFlagCompletionFunc(flagName(ff.fd), cobra.FixedCompletions(enumNames(ff.fd.Enum()), cobra.ShellCompDirectiveNoFileComp))
+		}
+	}
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{string(itemio.FormatCSV), string(itemio.FormatJSONL), string(itemio.FormatParquet)}, cobra.ShellCompDirectiveNoFileComp,
+	))
+	_ = cmd.RegisterFlagCompletionFunc("compression", cobra.FixedCompletions(
+		[]string{"none", string(itemio.CompressionGzip), string(itemio.CompressionZstd)}, cobra.ShellCompDirectiveNoFileComp,
+	))
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		path := "-"
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		next, err := r.requests(cmd, rf, nil)
+		if err != nil {
+			return err
+		}
+
+		req, err := next()
+		if err != nil {
+			return err
+		}
+
+		if path != "-" {
+			m := req.ProtoReflect()
+			fields := m.Descriptor().Fields()
+
+			if fd := fields.ByName("format"); fd != nil && !m.Has(fd) {
+				m.Set(fd, protoreflect.ValueOfString(string(itemio.FormatOf(path))))
+			}
+			if fd := fields.ByName("compression"); fd != nil && !m.Has(fd) {
+				m.Set(fd, protoreflect.ValueOfString(string(itemio.CompressionOf(path))))
+			}
+		}
+
+		s, err := g.session(cmd)
+		if err != nil {
+			return err
+		}
+		defer s.close()
+
+		out, err := createExport(cmd, path)
+		if err != nil {
+			return err
+		}
+
+		n, err := r.export(cmd.Context(), s, req, out)
+		if err != nil {
+			out.abort()
+
+			return err
+		}
+
+		if err := out.commit(); err != nil {
+			return err
+		}
+
+		if path != "-" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %s items to %s\n", n, path)
+		}
+
+		return nil
+	}
+
+	return cmd
+}
+
+// export calls ExportItems, writes the chunks of the file to w and returns
+// the number of items exported, as reported by the service.
+func (r *rpc) export(ctx context.Context, s *session, req any, w io.Writer) (string, error) {
+	// Exports may run for long, so the timeout does not apply to them.
+	ctx, cancel := context.WithCancel(s.outgoing(ctx))
+	defer cancel()
+
+	stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(r.desc.Name()),
+		ServerStreams: true,
+	}, r.path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := stream.SendMsg(req); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return "", err
+	}
+
+	for {
+		resp := &itemv1.ExportItemsResponse{}
+		err := stream.RecvMsg(resp)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := w.Write(resp.GetChunk()); err != nil {
+			return "", err
+		}
+	}
+
+	n := "all"
+	if v := stream.Trailer().Get(exportedItemsTrailer); len(v) > 0 {
+		n = v[0]
+	}
+
+	return n, nil
+}
+
+// exportFile is the destination of an export: stdout, or a temporary file
+// renamed to its path once the export is complete.
+type exportFile struct {
+	io.Writer
+	tmp  *os.File
+	path string
+}
+
+func createExport(cmd *cobra.Command, path string) (*exportFile, error) {
+	if path == "-" {
+		return &exportFile{Writer: cmd.OutOrStdout()}, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportFile{Writer: tmp, tmp: tmp, path: path}, nil
+}
+
+func (f *exportFile) commit() error {
+	if f.tmp == nil {
+		return nil
+	}
+
+	if err := f.tmp.Close(); err != nil {
+		os.Remove(f.tmp.Name())
+
+		return err
+	}
+
+	// CreateTemp makes the file private; exports are ordinary files.
+	if err := os.Chmod(f.tmp.Name(), 0o644); err != nil {
+		os.Remove(f.tmp.Name())
+
+		return err
+	}
+
+	return os.Rename(f.tmp.Name(), f.path)
+}
+
+func (f *exportFile) abort() {
+	if f.tmp == nil {
+		return
+	}
+
+	f.tmp.Close()
+	os.Remove(f.tmp.Name())
+}
@@ -0,0 +1,292 @@
+package itemctl
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldFlag sets a field of the request message from the command line.
+// Repeated fields take comma separated values and may be repeated.
+type fieldFlag struct {
+	fd     protoreflect.FieldDescriptor
+	raw    []string
+	values []any
+}
+
+// flagSupported reports whether the field can be set by a fieldFlag. Other
+// fields are set with --data.
+func flagSupported(fd protoreflect.FieldDescriptor) bool {
+	switch {
+	case fd.IsMap(), fd.Kind() == protoreflect.GroupKind:
+		return false
+	case fd.Kind() == protoreflect.MessageKind:
+		return !fd.IsList() && isCellMessage(fd.Message())
+	}
+
+	return true
+}
+
+func (f *fieldFlag) String() string {
+	return strings.Join(f.raw, ",")
+}
+
+func (f *fieldFlag) Set(s string) error {
+	parts := []string{s}
+	if f.fd.IsList() {
+		parts = strings.Split(s, ",")
+	} else {
+		f.raw, f.values = nil, nil
+	}
+
+	for _, part := range parts {
+		v, err := parseField(f.fd, part)
+		if err != nil {
+			return err
+		}
+
+		f.raw = append(f.raw, part)
+		f.values = append(f.values, v)
+	}
+
+	return nil
+}
+
+func (f *fieldFlag) Type() string {
+	var t string
+
+	switch f.fd.Kind() {
+	case protoreflect.BoolKind:
+		t = "bool"
+	case protoreflect.StringKind:
+		t = "string"
+	case protoreflect.BytesKind:
+		t = "bytes"
+	case protoreflect.EnumKind:
+		t = "enum"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		t = "float"
+	case protoreflect.MessageKind:
+		if f.fd.Message().FullName() == timestampName {
+			t = "time"
+		} else {
+			t = "duration"
+		}
+	default:
+		t = "int"
+	}
+
+	if f.fd.IsList() {
+		t += "s"
+	}
+
+	return t
+}
+
+func (f *fieldFlag) usage() string {
+	switch f.fd.Kind() {
+	case protoreflect.EnumKind:
+		return "one of " + strings.Join(enumNames(f.fd.Enum()), ", ")
+	case protoreflect.BytesKind:
+		return "value, or @file to read it from a file"
+	case protoreflect.MessageKind:
+		if f.fd.Message().FullName() == timestampName {
+			return "RFC 3339 time"
+		}
+
+		return "duration such as 90s or 24h"
+	}
+
+	return ""
+}
+
+// apply sets the field of m, replacing the value from --data.
+func (f *fieldFlag) apply(m protoreflect.Message) {
+	if !f.fd.IsList() {
+		m.Set(f.fd, f.value(m, f.values[len(f.values)-1]))
+
+		return
+	}
+
+	m.Clear(f.fd)
+
+	list := m.Mutable(f.fd).List()
+	for _, v := range f.values {
+		list.Append(f.value(m, v))
+	}
+}
+
+func (f *fieldFlag) value(m protoreflect.Message, v any) protoreflect.Value {
+	var seconds, nanos int64
+
+	switch v := v.(type) {
+	case time.Time:
+		seconds, nanos = v.Unix(), int64(v.Nanosecond())
+	case time.Duration:
+		seconds, nanos = int64(v/time.Second), int64(v%time.Second)
+	default:
+		return v.(protoreflect.Value)
+	}
+
+	// Well-known types are created through the parent so that generated and
+	// dynamic messages both get a message of their own kind.
+	field := m.NewField(f.fd)
+	msg := field.Message()
+	fields := msg.Descriptor().Fields()
+	msg.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(seconds))
+	msg.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(nanos)))
+
+	return field
+}
+
+// parseField parses a flag value of the field. Timestamps and durations
+// are returned as time.Time and time.Duration, other values as
+// protoreflect.Value.
+func parseField(fd protoreflect.FieldDescriptor, s string) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		if path, ok := strings.CutPrefix(s, "@"); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			return protoreflect.ValueOfBytes(data), nil
+		}
+
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.EnumKind:
+		n, err := parseEnum(fd.Enum(), s)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfEnum(n), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		n, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfFloat32(float32(n)), nil
+	case protoreflect.DoubleKind:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return protoreflect.ValueOfFloat64(n), nil
+	case protoreflect.MessageKind:
+		if fd.Message().FullName() == timestampName {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+
+		return time.ParseDuration(s)
+	}
+
+	return nil, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
+
+// parseEnum accepts the value name, with or without the prefix of the enum
+// type, in any case, or the value number.
+func parseEnum(ed protoreflect.EnumDescriptor, s string) (protoreflect.EnumNumber, error) {
+	prefix := enumPrefix(ed)
+
+	values := ed.Values()
+	for i := 0; i < values.Len(); i++ {
+		name := string(values.Get(i).Name())
+		if strings.EqualFold(name, s) || strings.EqualFold(name, prefix+s) {
+			return values.Get(i).Number(), nil
+		}
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+		return protoreflect.EnumNumber(n), nil
+	}
+
+	return 0, fmt.Errorf("expected one of %s", strings.Join(enumNames(ed), ", "))
+}
+
+func enumNames(ed protoreflect.EnumDescriptor) []string {
+	values := ed.Values()
+
+	names := make([]string, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		names = append(names, string(values.Get(i).Name()))
+	}
+
+	return names
+}
+
+// enumPrefix returns the conventional prefix of the value names of the
+// enum: TradeStatus values start with TRADE_STATUS_.
+func enumPrefix(ed protoreflect.EnumDescriptor) string {
+	return strings.ToUpper(strings.ReplaceAll(kebab(string(ed.Name())), "-", "_")) + "_"
+}
+
+// kebab converts a CamelCase name to kebab-case: GetItemDefinition becomes
+// get-item-definition.
+func kebab(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// flagName returns the flag of a field: item_id becomes --item-id.
+func flagName(fd protoreflect.FieldDescriptor) string {
+	return strings.ReplaceAll(string(fd.Name()), "_", "-")
+}
@@ -0,0 +1,223 @@
+package itemctl
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+
+	"item-service/internal/lib/itemio"
+)
+
+func (g *globals) importCommand() *cobra.Command {
+	var (
+		format  string
+		mapping string
+		dryRun  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import items from a CSV or JSON Lines file",
+		Long: `import streams a CSV or JSON Lines file to ImportItems. Every row is
+validated like create-item; invalid rows are reported and skipped, the others
+are written in batches. With --dry-run nothing is written.
+
+The format is taken from the file extension unless given; .gz files are
+decompressed. A mapping file relates the columns of the file to item fields
+(` + strings.Join(itemio.Fields, ", ") + `), e.g.
+
+  columns:
+    name: Skin
+    float: Wear
+  defaults:
+    quality: Normal
+  tag_separator: ";"
+
+The command fails if any row failed.`,
+		Example: `  itemctl import skins.csv --mapping skins-mapping.yaml --dry-run
+  gunzip -c dump.jsonl.gz | itemctl import - --format jsonl -o json`,
+		GroupID: groupTools,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := importFormat(args[0], format)
+			if err != nil {
+				return err
+			}
+
+			var spec []byte
+			if mapping != "" {
+				if spec, err = os.ReadFile(mapping); err != nil {
+					return err
+				}
+
+				// Fail early on mistakes the service would reject.
+				if _, err := itemio.ParseMapping(spec); err != nil {
+					return err
+				}
+			}
+
+			rd, err := openImport(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer rd.Close()
+
+			s, err := g.session(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.close()
+
+			stream, err := itemv1.NewItemServiceClient(s.conn).ImportItems(s.outgoing(cmd.Context()))
+			if err != nil {
+				return err
+			}
+
+			if err := stream.Send(&itemv1.ImportItemsRequest{
+				Data: &itemv1.ImportItemsRequest_Options{Options: &itemv1.ImportOptions{
+					Format:  string(f),
+					Mapping: string(spec),
+					DryRun:  dryRun,
+				}},
+			}); err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			buf := make([]byte, chunkSize)
+			for {
+				n, err := rd.Read(buf)
+				if n > 0 {
+					chunk := append([]byte(nil), buf[:n]...)
+					if err := stream.Send(&itemv1.ImportItemsRequest{
+						Data: &itemv1.ImportItemsRequest_Chunk{Chunk: chunk},
+					}); err != nil {
+						// The service ended the call; CloseAndRecv returns why.
+						if errors.Is(err, io.EOF) {
+							break
+						}
+
+						return err
+					}
+				}
+
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			report, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+
+			if err := printImportReport(cmd, s, report); err != nil {
+				return err
+			}
+
+			if report.GetFailed() > 0 {
+				return fmt.Errorf("%d of %d rows failed", report.GetFailed(), report.GetRows())
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "format of the file: csv or jsonl")
+	cmd.Flags().StringVar(&mapping, "mapping", "", "YAML or JSON file mapping columns to item fields")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate the file without importing anything")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{string(itemio.FormatCSV), string(itemio.FormatJSONL)}, cobra.ShellCompDirectiveNoFileComp,
+	))
+	_ = cmd.MarkFlagFilename("mapping", "yaml", "yml", "json")
+
+	return cmd
+}
+
+func importFormat(path, format string) (itemio.Format, error) {
+	if format != "" {
+		return itemio.ParseFormat(format)
+	}
+
+	if f := itemio.FormatOf(path); f != "" {
+		return f, nil
+	}
+
+	return "", fmt.Errorf("cannot tell the format of %s, use --format", path)
+}
+
+// openImport opens the file to import, decompressing .gz files.
+func openImport(cmd *cobra.Command, path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(cmd.InOrStdin()), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return f, nil
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{zr, f}, nil
+}
+
+// printImportReport prints the counts of the import followed by its row
+// errors. JSON and YAML print the response as it is.
+func printImportReport(cmd *cobra.Command, s *session, report *itemv1.ImportItemsResponse) error {
+	if s.printer.format != outputTable {
+		if err := s.printer.print(report); err != nil {
+			return err
+		}
+
+		return s.printer.flush()
+	}
+
+	out := cmd.OutOrStdout()
+
+	imported := "imported"
+	if report.GetDryRun() {
+		imported = "would be imported (dry run)"
+	}
+	fmt.Fprintf(out, "%d rows, %d %s, %d failed\n", report.GetRows(), report.GetImported(), imported, report.GetFailed())
+
+	if len(report.GetErrors()) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tFIELD\tERROR")
+	for _, e := range report.GetErrors() {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", e.GetRow(), e.GetField(), e.GetMessage())
+	}
+
+	if more := int(report.GetFailed()) - len(report.GetErrors()); more > 0 {
+		fmt.Fprintf(w, "...\t\t%d more rows failed\n", more)
+	}
+
+	return w.Flush()
+}
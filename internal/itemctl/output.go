@@ -0,0 +1,261 @@
+package itemctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+const (
+	timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// printer writes responses in one of the output formats. Table output is
+// buffered until flush so that columns line up across streamed messages.
+type printer struct {
+	format string
+	w      io.Writer
+
+	table   *tabwriter.Writer
+	columns protoreflect.Descriptor
+	printed int
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(outputFormats, ", "))
+	}
+
+	return &printer{format: format, w: w}, nil
+}
+
+func (p *printer) print(msg protoreflect.ProtoMessage) error {
+	defer func() { p.printed++ }()
+
+	switch p.format {
+	case outputJSON:
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+
+		return err
+	case outputYAML:
+		return p.printYAML(msg)
+	}
+
+	return p.printTable(msg.ProtoReflect())
+}
+
+func (p *printer) flush() error {
+	if p.table == nil {
+		return nil
+	}
+
+	return p.table.Flush()
+}
+
+func (p *printer) printYAML(msg protoreflect.ProtoMessage) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if p.printed > 0 {
+		if _, err := io.WriteString(p.w, "---\n"); err != nil {
+			return err
+		}
+	}
+
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// printTable prints the rows of the response: the elements of its only
+// repeated message field, else its only message field, else the response
+// itself. Columns are the fields that fit in a cell.
+func (p *printer) printTable(m protoreflect.Message) error {
+	rows := tableRows(m)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	desc := rows[0].Descriptor()
+	columns := tableColumns(desc)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	if p.table == nil {
+		p.table = tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	}
+
+	if p.columns != desc {
+		if p.columns != nil {
+			if err := p.table.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(p.w)
+		}
+
+		headers := make([]string, 0, len(columns))
+		for _, fd := range columns {
+			headers = append(headers, strings.ToUpper(string(fd.Name())))
+		}
+		fmt.Fprintln(p.table, strings.Join(headers, "\t"))
+
+		p.columns = desc
+	}
+
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, fd := range columns {
+			cells = append(cells, cell(row, fd))
+		}
+		fmt.Fprintln(p.table, strings.Join(cells, "\t"))
+	}
+
+	return nil
+}
+
+func tableRows(m protoreflect.Message) []protoreflect.Message {
+	var lists, singles []protoreflect.FieldDescriptor
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || isCellMessage(fd.Message()) {
+			continue
+		}
+
+		if fd.IsList() {
+			lists = append(lists, fd)
+		} else {
+			singles = append(singles, fd)
+		}
+	}
+
+	switch {
+	case len(lists) == 1:
+		list := m.Get(lists[0]).List()
+
+		rows := make([]protoreflect.Message, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			rows = append(rows, list.Get(i).Message())
+		}
+
+		return rows
+	case len(lists) == 0 && len(singles) == 1:
+		if !m.Has(singles[0]) {
+			return nil
+		}
+
+		return []protoreflect.Message{m.Get(singles[0]).Message()}
+	}
+
+	return []protoreflect.Message{m}
+}
+
+func tableColumns(desc protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	var columns []protoreflect.FieldDescriptor
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsMap() || fd.Kind() == protoreflect.GroupKind {
+			continue
+		}
+
+		if fd.Kind() == protoreflect.MessageKind && (fd.IsList() || !isCellMessage(fd.Message())) {
+			continue
+		}
+
+		columns = append(columns, fd)
+	}
+
+	return columns
+}
+
+// isCellMessage reports whether messages of the type are printed as a
+// single table cell.
+func isCellMessage(desc protoreflect.MessageDescriptor) bool {
+	return desc.FullName() == timestampName || desc.FullName() == durationName
+}
+
+func cell(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if fd.IsList() {
+		list := m.Get(fd).List()
+
+		values := make([]string, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, formatValue(fd, list.Get(i)))
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	if fd.Kind() == protoreflect.MessageKind && !m.Has(fd) {
+		return ""
+	}
+
+	return formatValue(fd, m.Get(fd))
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("<%d bytes>", len(v.Bytes()))
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.MessageKind:
+		msg := v.Message()
+		fields := msg.Descriptor().Fields()
+		seconds := msg.Get(fields.ByName("seconds")).Int()
+		nanos := msg.Get(fields.ByName("nanos")).Int()
+
+		if msg.Descriptor().FullName() == timestampName {
+			return time.Unix(seconds, nanos).UTC().Format(time.RFC3339)
+		}
+
+		return (time.Duration(seconds)*time.Second + time.Duration(nanos)).String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
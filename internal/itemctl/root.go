@@ -0,0 +1,297 @@
+// Package itemctl implements itemctl, the command line client of the item
+// service. Every method of the service is a command whose flags are the
+// fields of the request.
+package itemctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	itemv1 "github.com/tolseone/protos/gen/go/item"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	groupRPC   = "rpc"
+	groupTools = "tools"
+)
+
+// globals are the flags shared by all commands. Connection flags override
+// the values of the profile.
+type globals struct {
+	config  string
+	profile string
+	headers []string
+	conn    Profile
+}
+
+// Execute runs itemctl with the arguments of the process and returns its
+// exit code.
+func Execute(ctx context.Context) int {
+	svc, err := itemService()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+
+		return 1
+	}
+
+	cmd := NewCommand(svc)
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Error:", describeError(err))
+
+		return 1
+	}
+
+	return 0
+}
+
+func itemService() (protoreflect.ServiceDescriptor, error) {
+	name := protoreflect.FullName(itemv1.ItemService_ServiceDesc.ServiceName)
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("find %s: %w", name, err)
+	}
+
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+
+	return svc, nil
+}
+
+// NewCommand returns the root command of itemctl with a command for every
+// method of the service.
+func NewCommand(svc protoreflect.ServiceDescriptor) *cobra.Command {
+	g := &globals{}
+
+	root := &cobra.Command{
+		Use:   "itemctl",
+		Short: "Command line client of the item service",
+		Long: `itemctl calls the methods of the item service. Request fields are set with
+flags or as JSON with --data; fields without a flag, such as maps and nested
+messages, can only be set with --data. Flags override --data.
+
+Connection settings are read from the profile selected with --profile,
+$` + profileEnv + ` or the current profile of the config file.`,
+		Example: `  itemctl config set-profile staging --address items.staging:44044 --tls --token-file ~/.staging-token
+  itemctl -p staging get-item 0b6b9a58-3f0c-4bde-9d3f-61c3b7a6a0d4 -o yaml
+  itemctl get-all-items --statuses published,locked --page-size 50
+  itemctl create-item --data @item.json
+  itemctl upload-item-image --definition-id 7c1e... --chunk-file knife.png
+  itemctl batch delete-item --file deletes.jsonl --concurrency 8
+  itemctl export items.parquet --statuses published`,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	root.AddGroup(
+		&cobra.Group{ID: groupRPC, Title: "Service methods:"},
+		&cobra.Group{ID: groupTools, Title: "Other commands:"},
+	)
+	root.SetHelpCommandGroupID(groupTools)
+	root.SetCompletionCommandGroupID(groupTools)
+
+	g.addFlags(root)
+
+	methods := svc.Methods()
+	rpcs := make(map[string]*rpc, methods.Len())
+	for i := 0; i < methods.Len(); i++ {
+		r := newRPC(methods.Get(i))
+		rpcs[kebab(string(r.desc.Name()))] = r
+
+		root.AddCommand(g.rpcCommand(r))
+	}
+
+	root.AddCommand(g.configCommand(), g.batchCommand(rpcs), g.importCommand())
+	if r, ok := rpcs["export-items"]; ok {
+		root.AddCommand(g.exportCommand(r))
+	}
+
+	return root
+}
+
+func (g *globals) addFlags(root *cobra.Command) {
+	fs := root.PersistentFlags()
+
+	fs.StringVar(&g.config, "config", defaultConfigPath(), "config file, $"+configPathEnv)
+	fs.StringVarP(&g.profile, "profile", "p", "", "profile of the config file to use, $"+profileEnv)
+
+	fs.StringVarP(&g.conn.Output, "output", "o", outputTable, "output format: "+strings.Join(outputFormats, ", "))
+	fs.StringVarP(&g.conn.Address, "address", "a", defaultAddress, "address of the item service")
+	fs.BoolVar(&g.conn.TLS, "tls", false, "connect with TLS")
+	fs.StringVar(&g.conn.CAFile, "ca-file", "", "CA certificates to verify the server with")
+	fs.StringVar(&g.conn.CertFile, "cert-file", "", "client certificate for mutual TLS")
+	fs.StringVar(&g.conn.KeyFile, "key-file", "", "key of the client certificate")
+	fs.StringVar(&g.conn.ServerName, "server-name", "", "server name to verify instead of the address host")
+	fs.BoolVar(&g.conn.InsecureSkipVerify, "insecure-skip-verify", false, "do not verify the server certificate")
+	fs.StringVar(&g.conn.Token, "token", "", "bearer token, $"+tokenEnv)
+	fs.StringVar(&g.conn.TokenFile, "token-file", "", "file with the bearer token")
+	fs.StringArrayVarP(&g.headers, "header", "H", nil, "metadata sent with every call as key=value, may be repeated")
+	fs.DurationVar(&g.conn.Timeout, "timeout", defaultTimeout, "timeout of unary calls, 0 for none")
+	fs.StringVar(&g.conn.Locale, "locale", "", "preferred languages of localized names, sent as accept-language")
+
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("profile", g.completeProfiles)
+}
+
+// resolve returns the settings of the invocation: the profile, then the
+// environment, then the flags.
+func (g *globals) resolve(fs *pflag.FlagSet) (*Profile, error) {
+	cfg, err := loadConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+
+	name := g.profile
+	if name == "" {
+		name = os.Getenv(profileEnv)
+	}
+
+	p, err := cfg.profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := os.Getenv(tokenEnv); token != "" {
+		p.Token, p.TokenFile = token, ""
+	}
+
+	if err := g.override(fs, p); err != nil {
+		return nil, err
+	}
+
+	if p.Address == "" {
+		p.Address = defaultAddress
+	}
+	if p.Output == "" {
+		p.Output = outputTable
+	}
+	if p.Timeout == 0 && !fs.Changed("timeout") {
+		p.Timeout = defaultTimeout
+	}
+
+	return p, nil
+}
+
+// override copies the connection flags given on the command line to p.
+func (g *globals) override(fs *pflag.FlagSet, p *Profile) error {
+	set := map[string]func(){
+		"output":               func() { p.Output = g.conn.Output },
+		"address":              func() { p.Address = g.conn.Address },
+		"tls":                  func() { p.TLS = g.conn.TLS },
+		"ca-file":              func() { p.CAFile = g.conn.CAFile },
+		"cert-file":            func() { p.CertFile = g.conn.CertFile },
+		"key-file":             func() { p.KeyFile = g.conn.KeyFile },
+		"server-name":          func() { p.ServerName = g.conn.ServerName },
+		"insecure-skip-verify": func() { p.InsecureSkipVerify = g.conn.InsecureSkipVerify },
+		"token":                func() { p.Token, p.TokenFile = g.conn.Token, "" },
+		"token-file":           func() { p.Token, p.TokenFile = "", g.conn.TokenFile },
+		"timeout":              func() { p.Timeout = g.conn.Timeout },
+		"locale":               func() { p.Locale = g.conn.Locale },
+	}
+
+	for name, apply := range set {
+		// A request field of the same name shadows the global flag.
+		flag := fs.Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+
+		if _, ok := flag.Value.(*fieldFlag); !ok {
+			apply()
+		}
+	}
+
+	if len(g.headers) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(p.Headers)+len(g.headers))
+	for k, v := range p.Headers {
+		headers[k] = v
+	}
+
+	for _, h := range g.headers {
+		k, v, ok := strings.Cut(h, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("invalid header %q, expected key=value", h)
+		}
+		headers[strings.TrimSpace(k)] = v
+	}
+	p.Headers = headers
+
+	return nil
+}
+
+// session connects with the settings of the invocation.
+func (g *globals) session(cmd *cobra.Command) (*session, error) {
+	p, err := g.resolve(cmd.Flags())
+	if err != nil {
+		return nil, err
+	}
+
+	return newSession(p, cmd.OutOrStdout())
+}
+
+func (g *globals) rpcCommand(r *rpc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     kebab(string(r.desc.Name())),
+		Short:   fmt.Sprintf("Call %s", r.desc.FullName()),
+		GroupID: groupRPC,
+		Args:    cobra.NoArgs,
+	}
+
+	if fd := r.idField(); fd != nil {
+		cmd.Use += " [" + flagName(fd) + "]"
+		cmd.Args = cobra.MaximumNArgs(1)
+	}
+
+	switch {
+	case r.desc.IsStreamingClient() && r.desc.IsStreamingServer():
+		cmd.Short += " (bidirectional stream)"
+	case r.desc.IsStreamingClient():
+		cmd.Short += " (client stream)"
+	case r.desc.IsStreamingServer():
+		cmd.Short += " (server stream)"
+	}
+
+	rf := r.addFlags(cmd.Flags())
+	for _, ff := range rf.fields {
+		if ff.fd.Kind() == protoreflect.EnumKind {
+			_ = cmd.RegisterFlagCompletionFunc(flagName(ff.fd), cobra.FixedCompletions(enumNames(ff.fd.Enum()), cobra.ShellCompDirectiveNoFileComp))
+		}
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		next, err := r.requests(cmd, rf, args)
+		if err != nil {
+			return err
+		}
+
+		s, err := g.session(cmd)
+		if err != nil {
+			return err
+		}
+		defer s.close()
+
+		return r.call(cmd.Context(), s, next)
+	}
+
+	return cmd
+}
+
+func (g *globals) completeProfiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadConfig(g.config)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return cfg.profileNames(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,386 @@
+package itemctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// chunkSize is the size of the file chunks sent by client streams.
+const chunkSize = 64 << 10
+
+// maxLine bounds the length of a JSON line read by client streams and
+// batches.
+const maxLine = 16 << 20
+
+// rpc is a method of the service exposed as a command.
+type rpc struct {
+	desc protoreflect.MethodDescriptor
+	path string
+}
+
+func newRPC(desc protoreflect.MethodDescriptor) *rpc {
+	return &rpc{
+		desc: desc,
+		path: fmt.Sprintf("/%s/%s", desc.Parent().FullName(), desc.Name()),
+	}
+}
+
+func (r *rpc) unary() bool {
+	return !r.desc.IsStreamingClient() && !r.desc.IsStreamingServer()
+}
+
+// idField returns the field that may be given as the positional argument:
+// the first field of the request if it is an id.
+func (r *rpc) idField() protoreflect.FieldDescriptor {
+	fields := r.desc.Input().Fields()
+	if fields.Len() == 0 || r.desc.IsStreamingClient() {
+		return nil
+	}
+
+	fd := fields.Get(0)
+	if fd.Kind() != protoreflect.StringKind || fd.IsList() || !strings.HasSuffix(string(fd.Name()), "_id") {
+		return nil
+	}
+
+	return fd
+}
+
+// requestFlags are the flags of an RPC command that build its requests.
+type requestFlags struct {
+	data   string
+	fields []*fieldFlag
+	// files stream a file into a bytes field of a client stream.
+	files map[protoreflect.FieldDescriptor]*string
+}
+
+func (r *rpc) addFlags(fs *pflag.FlagSet) *requestFlags {
+	rf := &requestFlags{files: map[protoreflect.FieldDescriptor]*string{}}
+
+	dataUsage := "request as JSON, @file or @- to read it from a file or stdin"
+	if r.desc.IsStreamingClient() {
+		dataUsage = "requests as JSON lines, @file or @- to read them from a file or stdin"
+	}
+	fs.StringVarP(&rf.data, "data", "d", "", dataUsage)
+
+	fields := r.desc.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !flagSupported(fd) || fs.Lookup(flagName(fd)) != nil {
+			continue
+		}
+
+		ff := &fieldFlag{fd: fd}
+		flag := fs.VarPF(ff, flagName(fd), "", ff.usage())
+		if fd.Kind() == protoreflect.BoolKind && !fd.IsList() {
+			flag.NoOptDefVal = "true"
+		}
+		rf.fields = append(rf.fields, ff)
+
+		if r.desc.IsStreamingClient() && fd.Kind() == protoreflect.BytesKind && !fd.IsList() {
+			path := new(string)
+			fs.StringVar(path, flagName(fd)+"-file", "", fmt.Sprintf("stream the file as %s chunks after the other requests", fd.Name()))
+			rf.files[fd] = path
+		}
+	}
+
+	return rf
+}
+
+// requestSource returns the requests of a call one by one and io.EOF after
+// the last one.
+type requestSource func() (proto.Message, error)
+
+// requests builds the requests of the call from --data, the field flags
+// and the positional argument.
+func (r *rpc) requests(cmd *cobra.Command, rf *requestFlags, args []string) (requestSource, error) {
+	if r.desc.IsStreamingClient() {
+		return r.streamRequests(cmd, rf)
+	}
+
+	req := newMessage(r.desc.Input())
+
+	if rf.data != "" {
+		data, err := readData(cmd, rf.data)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := protojson.Unmarshal(data, req.Interface()); err != nil {
+			return nil, fmt.Errorf("parse --data: %w", err)
+		}
+	}
+
+	if len(args) > 0 {
+		req.Set(r.idField(), protoreflect.ValueOfString(args[0]))
+	}
+
+	rf.apply(cmd, req)
+
+	sent := false
+
+	return func() (proto.Message, error) {
+		if sent {
+			return nil, io.EOF
+		}
+		sent = true
+
+		return req.Interface(), nil
+	}, nil
+}
+
+// streamRequests sends the JSON lines of --data, or the request built from
+// the flags, followed by the chunks of the files.
+func (r *rpc) streamRequests(cmd *cobra.Command, rf *requestFlags) (requestSource, error) {
+	var sources []requestSource
+
+	switch {
+	case rf.data != "":
+		rd, err := openData(cmd, rf.data)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, r.lineRequests(rd))
+	case rf.changed(cmd):
+		req := newMessage(r.desc.Input())
+		rf.apply(cmd, req)
+		sources = append(sources, single(req.Interface()))
+	}
+
+	for fd, path := range rf.files {
+		if *path == "" {
+			continue
+		}
+
+		f, err := os.Open(*path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, r.chunkRequests(fd, f))
+	}
+
+	return func() (proto.Message, error) {
+		for len(sources) > 0 {
+			msg, err := sources[0]()
+			if !errors.Is(err, io.EOF) {
+				return msg, err
+			}
+			sources = sources[1:]
+		}
+
+		return nil, io.EOF
+	}, nil
+}
+
+func (r *rpc) lineRequests(rd io.ReadCloser) requestSource {
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+
+	line := 0
+
+	return func() (proto.Message, error) {
+		for sc.Scan() {
+			line++
+
+			text := bytes.TrimSpace(sc.Bytes())
+			if len(text) == 0 || text[0] == '#' {
+				continue
+			}
+
+			req := newMessage(r.desc.Input())
+			if err := protojson.Unmarshal(text, req.Interface()); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			return req.Interface(), nil
+		}
+
+		rd.Close()
+
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, io.EOF
+	}
+}
+
+func (r *rpc) chunkRequests(fd protoreflect.FieldDescriptor, f *os.File) requestSource {
+	buf := make([]byte, chunkSize)
+
+	return func() (proto.Message, error) {
+		n, err := io.ReadFull(f, buf)
+		if n == 0 {
+			f.Close()
+
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+
+			return nil, err
+		}
+
+		req := newMessage(r.desc.Input())
+		req.Set(fd, protoreflect.ValueOfBytes(append([]byte(nil), buf[:n]...)))
+
+		return req.Interface(), nil
+	}
+}
+
+func single(msg proto.Message) requestSource {
+	sent := false
+
+	return func() (proto.Message, error) {
+		if sent {
+			return nil, io.EOF
+		}
+		sent = true
+
+		return msg, nil
+	}
+}
+
+// changed reports whether any field flag was given.
+func (rf *requestFlags) changed(cmd *cobra.Command) bool {
+	for _, ff := range rf.fields {
+		if cmd.Flags().Changed(flagName(ff.fd)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apply sets the fields given as flags on the request.
+func (rf *requestFlags) apply(cmd *cobra.Command, req protoreflect.Message) {
+	for _, ff := range rf.fields {
+		if cmd.Flags().Changed(flagName(ff.fd)) {
+			ff.apply(req)
+		}
+	}
+}
+
+// call sends the requests and prints every response.
+func (r *rpc) call(ctx context.Context, s *session, next requestSource) error {
+	if r.unary() {
+		req, err := next()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := s.callContext(ctx)
+		defer cancel()
+
+		resp := newMessage(r.desc.Output())
+		if err := s.conn.Invoke(ctx, r.path, req, resp.Interface()); err != nil {
+			return err
+		}
+
+		if err := s.printer.print(resp.Interface()); err != nil {
+			return err
+		}
+
+		return s.printer.flush()
+	}
+
+	// Streams may run for long, so the timeout does not apply to them.
+	ctx, cancel := context.WithCancel(s.outgoing(ctx))
+	defer cancel()
+
+	stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(r.desc.Name()),
+		ServerStreams: r.desc.IsStreamingServer(),
+		ClientStreams: r.desc.IsStreamingClient(),
+	}, r.path)
+	if err != nil {
+		return err
+	}
+
+	for {
+		req, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// On io.EOF the server ended the call; its status is returned by
+		// RecvMsg.
+		if err := stream.SendMsg(req); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		resp := newMessage(r.desc.Output())
+		if err := stream.RecvMsg(resp.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		if err := s.printer.print(resp.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return s.printer.flush()
+}
+
+// newMessage returns a message of the generated type if it is linked in,
+// else a dynamic message.
+func newMessage(desc protoreflect.MessageDescriptor) protoreflect.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName()); err == nil {
+		return mt.New()
+	}
+
+	return dynamicpb.NewMessage(desc)
+}
+
+// readData returns the value of a --data flag.
+func readData(cmd *cobra.Command, value string) ([]byte, error) {
+	rd, err := openData(cmd, value)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return io.ReadAll(rd)
+}
+
+func openData(cmd *cobra.Command, value string) (io.ReadCloser, error) {
+	path, ok := strings.CutPrefix(value, "@")
+	switch {
+	case !ok:
+		return io.NopCloser(strings.NewReader(value)), nil
+	case path == "-":
+		return io.NopCloser(cmd.InOrStdin()), nil
+	}
+
+	return os.Open(path)
+}
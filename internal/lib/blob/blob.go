@@ -0,0 +1,20 @@
+// Package blob defines the store of binary objects such as item images.
+// Objects are addressed by slash-separated keys and are publicly readable
+// under the store's URL.
+package blob
+
+import (
+	"context"
+	"io"
+)
+
+// Store stores binary objects by key.
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any object
+	// stored there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Exists reports whether an object is stored under key.
+	Exists(ctx context.Context, key string) (bool, error)
+	// URL returns the public URL of the object stored under key.
+	URL(key string) string
+}
@@ -0,0 +1,137 @@
+// Package local implements blob.Store on the local filesystem.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps objects as files below a root directory.
+type Store struct {
+	dir     string
+	baseURL string
+}
+
+// New returns the store rooted at dir, creating the directory if needed.
+// baseURL is the public URL under which dir is served.
+func New(dir, baseURL string) (*Store, error) {
+	const op = "local.New"
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Store{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place, so
+// readers never see a partial object.
+func (s *Store) Put(_ context.Context, key string, r io.Reader, size int64, _ string) error {
+	const op = "local.Put"
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n != size {
+		return fmt.Errorf("%s: wrote %d bytes, expected %d", op, n, size)
+	}
+
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Exists(_ context.Context, key string) (bool, error) {
+	const op = "local.Exists"
+
+	_, err := os.Stat(s.path(key))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%s: %w", op, err)
+}
+
+func (s *Store) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler serves the stored objects over HTTP. Only objects are served:
+// directories and the temporary files of uploads are not found.
+func (s *Store) Handler() http.Handler {
+	return http.FileServer(objectFS{http.Dir(s.dir)})
+}
+
+// objectFS opens the files of objects only.
+type objectFS struct {
+	dir http.Dir
+}
+
+func (o objectFS) Open(name string) (http.File, error) {
+	for _, elem := range strings.Split(name, "/") {
+		if strings.HasPrefix(elem, ".") {
+			return nil, fs.ErrNotExist
+		}
+	}
+
+	f, err := o.dir.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	if info.IsDir() {
+		f.Close()
+
+		return nil, fs.ErrNotExist
+	}
+
+	return f, nil
+}
+
+// path returns the file of the key. Keys are cleaned so that they cannot
+// point outside of the root directory.
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(filepath.Clean("/"+key)))
+}
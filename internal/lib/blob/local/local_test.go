@@ -0,0 +1,59 @@
+package local_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"item-service/internal/lib/blob/local"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := local.New(dir, "http://localhost/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const object = "a png"
+	if err := store.Put(context.Background(), "images/ab/icon.png", strings.NewReader(object), int64(len(object)), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An upload in flight.
+	if err := os.WriteFile(filepath.Join(dir, "images", "ab", ".upload-123"), []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/images/ab/icon.png", http.StatusOK},
+		{"/", http.StatusNotFound},
+		{"/images/", http.StatusNotFound},
+		{"/images/ab", http.StatusNotFound},
+		{"/images/ab/.upload-123", http.StatusNotFound},
+		{"/images/ab/missing.png", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			store.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+
+			if tt.want == http.StatusOK && rec.Body.String() != object {
+				t.Errorf("body = %q, want %q", rec.Body.String(), object)
+			}
+		})
+	}
+}
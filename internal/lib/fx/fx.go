@@ -0,0 +1,159 @@
+// Package fx converts money between currencies. Exchange rates are exact
+// rationals, so a conversion rounds only once, to the minor unit of the
+// target currency.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"item-service/internal/domain/models"
+)
+
+// ErrNoRate is returned when there is no exchange rate for a currency.
+var ErrNoRate = errors.New("no exchange rate")
+
+// Rates is an exchange-rate table relative to a base currency. The nil
+// table converts only between equal currencies.
+type Rates struct {
+	base string
+	// rates holds the price of one base unit in each currency.
+	rates map[string]*big.Rat
+}
+
+// file is the layout of an exchange-rate file, e.g.
+//
+//	base: USD
+//	rates:
+//	  EUR: "0.9213"
+//	  JPY: "151.37"
+//
+// Rates are decimal strings so that they are read exactly.
+type file struct {
+	Base  string            `yaml:"base"`
+	Rates map[string]string `yaml:"rates"`
+}
+
+// New returns the table of rates given as decimal strings, each the price
+// of one unit of base in that currency.
+func New(base string, rates map[string]string) (*Rates, error) {
+	r := &Rates{
+		base:  base,
+		rates: map[string]*big.Rat{base: big.NewRat(1, 1)},
+	}
+
+	for currency, s := range rates {
+		rate, ok := new(big.Rat).SetString(s)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("rate of %s: must be a positive decimal, got %q", currency, s)
+		}
+
+		r.rates[currency] = rate
+	}
+
+	return r, nil
+}
+
+// Load reads the exchange-rate table from a YAML or JSON file.
+func Load(path string) (*Rates, error) {
+	const op = "fx.Load"
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if f.Base == "" {
+		return nil, fmt.Errorf("%s: base currency is required", op)
+	}
+
+	r, err := New(f.Base, f.Rates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return r, nil
+}
+
+// Base returns the base currency of the table.
+func (r *Rates) Base() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.base
+}
+
+// Convert converts m into the currency, rounding half away from zero to
+// the minor unit of the currency.
+func (r *Rates) Convert(m models.Money, currency string) (models.Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+
+	from, err := r.rate(m.Currency)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	to, err := r.rate(currency)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	// amount / 10^fromDigits / from * to * 10^toDigits
+	x := new(big.Rat).SetInt64(m.Amount)
+	x.Mul(x, to)
+	x.Quo(x, from)
+	x.Mul(x, new(big.Rat).SetFrac(pow10(models.MinorUnitDigits(currency)), pow10(models.MinorUnitDigits(m.Currency))))
+
+	amount, err := round(x)
+	if err != nil {
+		return models.Money{}, fmt.Errorf("converting %s to %s: %w", m, currency, err)
+	}
+
+	return models.Money{Amount: amount, Currency: currency}, nil
+}
+
+func (r *Rates) rate(currency string) (*big.Rat, error) {
+	if r != nil {
+		if rate, ok := r.rates[currency]; ok {
+			return rate, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w for %s", ErrNoRate, currency)
+}
+
+// round rounds x half away from zero to an int64.
+func round(x *big.Rat) (int64, error) {
+	num, den := new(big.Int).Abs(x.Num()), x.Denom()
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+
+	if x.Sign() < 0 {
+		q.Neg(q)
+	}
+
+	if !q.IsInt64() {
+		return 0, errors.New("amount out of range")
+	}
+
+	return q.Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,73 @@
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/fx"
+)
+
+func TestConvert(t *testing.T) {
+	rates, err := fx.New("USD", map[string]string{
+		"EUR": "0.5",
+		"JPY": "150",
+		"KWD": "0.3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		m        models.Money
+		currency string
+		want     models.Money
+		wantErr  error
+	}{
+		{"same currency", models.Money{Amount: 1999, Currency: "USD"}, "USD", models.Money{Amount: 1999, Currency: "USD"}, nil},
+		{"base to other", models.Money{Amount: 1000, Currency: "USD"}, "EUR", models.Money{Amount: 500, Currency: "EUR"}, nil},
+		{"other to base", models.Money{Amount: 500, Currency: "EUR"}, "USD", models.Money{Amount: 1000, Currency: "USD"}, nil},
+		{"half rounds up", models.Money{Amount: 1, Currency: "USD"}, "EUR", models.Money{Amount: 1, Currency: "EUR"}, nil},
+		{"to three digit minor unit", models.Money{Amount: 1, Currency: "USD"}, "KWD", models.Money{Amount: 3, Currency: "KWD"}, nil},
+		{"negative half rounds away from zero", models.Money{Amount: -1, Currency: "USD"}, "EUR", models.Money{Amount: -1, Currency: "EUR"}, nil},
+		{"to no minor unit", models.Money{Amount: 199, Currency: "USD"}, "JPY", models.Money{Amount: 299, Currency: "JPY"}, nil},
+		{"from no minor unit", models.Money{Amount: 1, Currency: "JPY"}, "USD", models.Money{Amount: 1, Currency: "USD"}, nil},
+		{"between non-base currencies", models.Money{Amount: 300, Currency: "EUR"}, "JPY", models.Money{Amount: 900, Currency: "JPY"}, nil},
+		{"below half rounds down", models.Money{Amount: 1000, Currency: "KWD"}, "USD", models.Money{Amount: 333, Currency: "USD"}, nil},
+		{"unknown source", models.Money{Amount: 100, Currency: "GBP"}, "USD", models.Money{}, fx.ErrNoRate},
+		{"unknown target", models.Money{Amount: 100, Currency: "USD"}, "GBP", models.Money{}, fx.ErrNoRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.m, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert(%s, %s) error = %v, want %v", tt.m, tt.currency, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%s, %s) = %s, want %s", tt.m, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertNilRates(t *testing.T) {
+	var rates *fx.Rates
+
+	m := models.Money{Amount: 100, Currency: "USD"}
+	if got, err := rates.Convert(m, "USD"); err != nil || got != m {
+		t.Errorf("Convert to the same currency = %s, %v, want %s", got, err, m)
+	}
+	if _, err := rates.Convert(m, "EUR"); !errors.Is(err, fx.ErrNoRate) {
+		t.Errorf("Convert to another currency error = %v, want %v", err, fx.ErrNoRate)
+	}
+}
+
+func TestNewInvalidRate(t *testing.T) {
+	for _, rate := range []string{"", "abc", "0", "-1"} {
+		if _, err := fx.New("USD", map[string]string{"EUR": rate}); err == nil {
+			t.Errorf("New with rate %q succeeded", rate)
+		}
+	}
+}
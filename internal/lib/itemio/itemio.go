@@ -0,0 +1,202 @@
+// Package itemio reads items from, and writes them to, the tabular files
+// used to move catalogues in and out of the service: CSV, JSON Lines and,
+// for exports only, Parquet.
+// A Mapping relates the columns of a file to the fields of models.Item.
+package itemio
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the layout of an item file.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+)
+
+// Item fields that can be mapped to columns.
+const (
+	FieldName        = "name"
+	FieldRarity      = "rarity"
+	FieldQuality     = "quality"
+	FieldFloat       = "float"
+	FieldPatternSeed = "pattern_seed"
+	FieldStatus      = "status"
+	FieldTags        = "tags"
+	FieldAttributes  = "attributes"
+)
+
+// Fields lists the item fields in their column order.
+var Fields = []string{
+	FieldName,
+	FieldRarity,
+	FieldQuality,
+	FieldFloat,
+	FieldPatternSeed,
+	FieldStatus,
+	FieldTags,
+	FieldAttributes,
+}
+
+const defaultTagSeparator = ","
+
+var (
+	ErrUnknownFormat      = errors.New("unknown item file format")
+	ErrUnknownCompression = errors.New("unknown compression")
+	ErrInvalidMapping     = errors.New("invalid column mapping")
+)
+
+// ParseFormat returns the format of its name; an empty name is CSV.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return f, nil
+	case "ndjson", "json":
+		return FormatJSONL, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+}
+
+// FormatOf returns the format of a file by its extension, ignoring
+// compression extensions, or an empty format if it is not known.
+func FormatOf(path string) Format {
+	ext := strings.ToLower(filepath.Ext(path))
+	if _, ok := compressionExts[ext]; ok {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+
+	f, err := ParseFormat(strings.TrimPrefix(ext, "."))
+	if err != nil || ext == "" {
+		return ""
+	}
+
+	return f
+}
+
+// Mapping relates the columns of a file to item fields. The zero mapping
+// reads every field from the column of the same name. Mappings are
+// written in YAML or JSON, e.g.
+//
+//	columns:
+//	  name: Skin
+//	  rarity: Grade
+//	  float: Wear
+//	  tags: Labels
+//	defaults:
+//	  quality: Normal
+//	  status: draft
+//	tag_separator: ";"
+//	delimiter: ";"
+type Mapping struct {
+	// Columns maps item fields to column names: the CSV header or the JSON
+	// key. Unmapped fields are read from the column named like the field.
+	Columns map[string]string `yaml:"columns" json:"columns"`
+	// Defaults are the values of fields whose column is missing or empty.
+	Defaults map[string]string `yaml:"defaults" json:"defaults"`
+	// TagSeparator splits tag cells; a comma by default.
+	TagSeparator string `yaml:"tag_separator" json:"tag_separator"`
+	// Delimiter separates CSV fields; a comma by default.
+	Delimiter string `yaml:"delimiter" json:"delimiter"`
+}
+
+// ParseMapping parses a mapping written in YAML or JSON. Empty input is
+// the zero mapping.
+func ParseMapping(data []byte) (Mapping, error) {
+	var m Mapping
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return m, nil
+	}
+
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return Mapping{}, fmt.Errorf("%w: %v", ErrInvalidMapping, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return Mapping{}, err
+	}
+
+	return m, nil
+}
+
+// Validate checks that the mapping names known fields only.
+func (m Mapping) Validate() error {
+	for _, fields := range []map[string]string{m.Columns, m.Defaults} {
+		for field := range fields {
+			if !knownField(field) {
+				return fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidMapping, field, strings.Join(Fields, ", "))
+			}
+		}
+	}
+
+	if m.Delimiter != "" {
+		r, size := utf8.DecodeRuneInString(m.Delimiter)
+		if size != len(m.Delimiter) || r == '"' || r == '\r' || r == '\n' {
+			return fmt.Errorf("%w: the delimiter must be a single character other than a quote or newline", ErrInvalidMapping)
+		}
+	}
+
+	return nil
+}
+
+// column returns the column of the field.
+func (m Mapping) column(field string) string {
+	if col, ok := m.Columns[field]; ok && col != "" {
+		return col
+	}
+
+	return field
+}
+
+func (m Mapping) tagSeparator() string {
+	if m.TagSeparator == "" {
+		return defaultTagSeparator
+	}
+
+	return m.TagSeparator
+}
+
+func (m Mapping) delimiter() rune {
+	if m.Delimiter == "" {
+		return ','
+	}
+
+	r, _ := utf8.DecodeRuneInString(m.Delimiter)
+
+	return r
+}
+
+// unmappedColumns returns the mapped columns missing from the header.
+func (m Mapping) unmappedColumns(header map[string]int) []string {
+	var missing []string
+	for field, col := range m.Columns {
+		if _, ok := header[col]; !ok && col != "" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", col, field))
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+func knownField(field string) bool {
+	for _, f := range Fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
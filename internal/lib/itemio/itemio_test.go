@@ -0,0 +1,216 @@
+package itemio_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/itemio"
+)
+
+// items are exported and imported again; they cover the escaping of CSV
+// cells and items without tags or attributes.
+var items = []*models.Item{
+	{
+		ItemId:       uuid.New(),
+		DefinitionId: uuid.New(),
+		OwnerId:      uuid.New(),
+		Name:         `AK-47 | "Redline", field-tested`,
+		Rarity:       "Classified",
+		Quality:      "StatTrak",
+		Float:        0.1534,
+		PatternSeed:  661,
+		Status:       models.ItemStatusPublished,
+		Tags:         []string{"rifle", "red"},
+		Attributes: models.Attributes{
+			models.AttrStatTrakKills: int64(1337),
+			models.AttrNameTag:       "line\nbreak",
+			models.AttrSouvenir:      false,
+		},
+	},
+	{
+		ItemId:       uuid.New(),
+		DefinitionId: uuid.New(),
+		Name:         "P250 | Sand Dune",
+		Rarity:       "Consumer Grade",
+		Quality:      "Normal",
+		Float:        0.999,
+		Status:       models.ItemStatusDraft,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		format      itemio.Format
+		compression itemio.Compression
+	}{
+		{itemio.FormatCSV, itemio.CompressionNone},
+		{itemio.FormatCSV, itemio.CompressionGzip},
+		{itemio.FormatJSONL, itemio.CompressionNone},
+		{itemio.FormatJSONL, itemio.CompressionGzip},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+string(tt.compression), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := itemio.NewWriter(&buf, tt.format, tt.compression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range items {
+				if err := w.Write(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			var r io.Reader = &buf
+			if tt.compression == itemio.CompressionGzip {
+				if r, err = gzip.NewReader(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := readAll(t, r, tt.format, itemio.Mapping{})
+			if len(got) != len(items) {
+				t.Fatalf("read %d items, want %d", len(got), len(items))
+			}
+
+			for i, item := range items {
+				if want := imported(item); !reflect.DeepEqual(got[i], want) {
+					t.Errorf("item %d = %+v, want %+v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadMapping(t *testing.T) {
+	const file = "Skin;Grade;Wear;Labels\n" +
+		"M4A4 | Howl;Contraband;0.07;a|b\n" +
+		"Glock-18 | Fade;Restricted;;\n"
+
+	m, err := itemio.ParseMapping([]byte(`
+columns:
+  name: Skin
+  rarity: Grade
+  float: Wear
+  tags: Labels
+defaults:
+  quality: Normal
+  float: "0.5"
+tag_separator: "|"
+delimiter: ";"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := readAll(t, strings.NewReader(file), itemio.FormatCSV, m)
+	want := []*models.Item{
+		{Name: "M4A4 | Howl", Rarity: "Contraband", Quality: "Normal", Float: 0.07, Tags: []string{"a", "b"}},
+		{Name: "Glock-18 | Fade", Rarity: "Restricted", Quality: "Normal", Float: 0.5},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadRowError(t *testing.T) {
+	const file = "name,rarity,float\n" +
+		"AWP | Asiimov,Covert,0.2\n" +
+		"AWP | Dragon Lore,Covert,worn\n" +
+		"AWP | Medusa,Covert,0.1\n"
+
+	r, err := itemio.NewReader(strings.NewReader(file), itemio.FormatCSV, itemio.Mapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for {
+		item, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *itemio.RowError
+		if errors.As(err, &rowErr) {
+			if rowErr.Row != 3 || rowErr.Field != itemio.FieldFloat {
+				t.Errorf("row error = %v, want one for the float of row 3", rowErr)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, item.Name)
+	}
+
+	if want := []string{"AWP | Asiimov", "AWP | Medusa"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("read %v, want %v", names, want)
+	}
+}
+
+func TestParseMappingUnknownField(t *testing.T) {
+	if _, err := itemio.ParseMapping([]byte("columns:\n  colour: Color\n")); !errors.Is(err, itemio.ErrInvalidMapping) {
+		t.Errorf("ParseMapping error = %v, want %v", err, itemio.ErrInvalidMapping)
+	}
+}
+
+func readAll(t *testing.T, r io.Reader, format itemio.Format, m itemio.Mapping) []*models.Item {
+	t.Helper()
+
+	rd, err := itemio.NewReader(r, format, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*models.Item
+	for {
+		item, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			return got
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Empty tags and attributes are the same as none.
+		if len(item.Tags) == 0 {
+			item.Tags = nil
+		}
+		if len(item.Attributes) == 0 {
+			item.Attributes = nil
+		}
+
+		got = append(got, item)
+	}
+}
+
+// imported returns the fields of item an import reads.
+func imported(item *models.Item) *models.Item {
+	return &models.Item{
+		Name:        item.Name,
+		Rarity:      item.Rarity,
+		Quality:     item.Quality,
+		Float:       item.Float,
+		PatternSeed: item.PatternSeed,
+		Status:      item.Status,
+		Tags:        item.Tags,
+		Attributes:  item.Attributes,
+	}
+}
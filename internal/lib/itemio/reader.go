@@ -0,0 +1,295 @@
+package itemio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"item-service/internal/domain/models"
+)
+
+// maxLine bounds the length of a JSON line.
+const maxLine = 1 << 20
+
+// RowError is returned by Reader.Read for a row that cannot be converted
+// to an item. Reading can continue with the next row.
+type RowError struct {
+	// Row is the line of the row in the file, starting at 1.
+	Row   int
+	Field string
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+
+	return fmt.Sprintf("row %d: %s: %v", e.Row, e.Field, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads items from a CSV or JSON Lines file.
+type Reader struct {
+	mapping Mapping
+	next    func() (record, int, error)
+	row     int
+}
+
+// record returns the raw value of a column and whether it is set.
+type record interface {
+	get(col string) (string, bool, error)
+}
+
+// NewReader returns a reader of items in the format. CSV files must start
+// with a header naming the columns.
+func NewReader(r io.Reader, format Format, m Mapping) (*Reader, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	rd := &Reader{mapping: m}
+
+	switch format {
+	case FormatCSV, "":
+		next, err := csvRecords(r, m)
+		if err != nil {
+			return nil, err
+		}
+		rd.next = next
+	case FormatJSONL:
+		rd.next = jsonRecords(r)
+	case FormatParquet:
+		return nil, fmt.Errorf("%w: parquet files can only be written", ErrUnknownFormat)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	return rd, nil
+}
+
+// Read returns the next item, a *RowError for a row that cannot be read,
+// or io.EOF after the last row. Other errors end the file.
+func (r *Reader) Read() (*models.Item, error) {
+	rec, row, err := r.next()
+	if err != nil {
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			r.row = rowErr.Row
+		}
+
+		return nil, err
+	}
+	r.row = row
+
+	item := &models.Item{}
+	for _, field := range Fields {
+		value, ok, err := rec.get(r.mapping.column(field))
+		if err != nil {
+			return nil, &RowError{Row: row, Field: field, Err: err}
+		}
+
+		if !ok || strings.TrimSpace(value) == "" {
+			if value, ok = r.mapping.Defaults[field]; !ok {
+				continue
+			}
+		}
+
+		if err := r.set(item, field, value); err != nil {
+			return nil, &RowError{Row: row, Field: field, Err: err}
+		}
+	}
+
+	return item, nil
+}
+
+// Row returns the line of the row last read.
+func (r *Reader) Row() int {
+	return r.row
+}
+
+func (r *Reader) set(item *models.Item, field, value string) error {
+	switch field {
+	case FieldName:
+		item.Name = strings.TrimSpace(value)
+	case FieldRarity:
+		item.Rarity = strings.TrimSpace(value)
+	case FieldQuality:
+		item.Quality = strings.TrimSpace(value)
+	case FieldStatus:
+		item.Status = models.ItemStatus(strings.ToLower(strings.TrimSpace(value)))
+	case FieldFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("not a number: %q", value)
+		}
+		item.Float = f
+	case FieldPatternSeed:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+		item.PatternSeed = n
+	case FieldTags:
+		tags, err := r.tags(value)
+		if err != nil {
+			return err
+		}
+		item.Tags = tags
+	case FieldAttributes:
+		var attrs models.Attributes
+		if err := json.Unmarshal([]byte(value), &attrs); err != nil {
+			return fmt.Errorf("not a JSON object of attributes: %v", err)
+		}
+		item.Attributes = attrs
+	}
+
+	return nil
+}
+
+// tags splits a tag cell. JSON arrays are accepted as well.
+func (r *Reader) tags(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "[") {
+		var tags []string
+		if err := json.Unmarshal([]byte(value), &tags); err != nil {
+			return nil, fmt.Errorf("not a list of tags: %v", err)
+		}
+
+		return tags, nil
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(value, r.mapping.tagSeparator()) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+type csvRecord struct {
+	header map[string]int
+	fields []string
+}
+
+func (c csvRecord) get(col string) (string, bool, error) {
+	i, ok := c.header[col]
+	if !ok || i >= len(c.fields) {
+		return "", false, nil
+	}
+
+	return c.fields[i], true, nil
+}
+
+func csvRecords(r io.Reader, m Mapping) (func() (record, int, error), error) {
+	cr := csv.NewReader(r)
+	cr.Comma = m.delimiter()
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	names, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the file is empty, a header is required")
+		}
+
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	header := make(map[string]int, len(names))
+	for i, name := range names {
+		// Spreadsheets may start UTF-8 files with a byte order mark.
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		header[strings.TrimSpace(name)] = i
+	}
+
+	if missing := m.unmappedColumns(header); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: columns not in the header: %s", ErrInvalidMapping, strings.Join(missing, ", "))
+	}
+
+	return func() (record, int, error) {
+		fields, err := cr.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, 0, &RowError{Row: parseErr.StartLine, Err: parseErr.Err}
+			}
+
+			return nil, 0, err
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		return csvRecord{header: header, fields: fields}, line, nil
+	}, nil
+}
+
+type jsonRecord map[string]json.RawMessage
+
+// get returns strings unquoted and other JSON values as written.
+func (j jsonRecord) get(col string) (string, bool, error) {
+	raw, ok := j[col]
+	if !ok || bytes.Equal(raw, []byte("null")) {
+		return "", false, nil
+	}
+
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", false, err
+		}
+
+		return s, true, nil
+	}
+
+	return string(raw), true, nil
+}
+
+func jsonRecords(r io.Reader) func() (record, int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+
+	line := 0
+
+	return func() (record, int, error) {
+		for sc.Scan() {
+			line++
+
+			text := bytes.TrimSpace(sc.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+
+			var rec jsonRecord
+			if err := json.Unmarshal(text, &rec); err != nil {
+				return nil, 0, &RowError{Row: line, Err: fmt.Errorf("not a JSON object: %v", err)}
+			}
+
+			return rec, line, nil
+		}
+
+		if err := sc.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				return nil, 0, fmt.Errorf("line %d is longer than %d bytes", line+1, maxLine)
+			}
+
+			return nil, 0, err
+		}
+
+		return nil, 0, io.EOF
+	}
+}
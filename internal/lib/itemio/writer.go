@@ -0,0 +1,344 @@
+package itemio
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"item-service/internal/domain/models"
+)
+
+// Compression is the compression of an item file.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// compressionExts are the file extensions of the compressions.
+var compressionExts = map[string]Compression{
+	".gz":  CompressionGzip,
+	".zst": CompressionZstd,
+}
+
+// ExportColumns are the columns of exported files. Their names are the
+// fields read by imports, so an export can be imported again.
+var ExportColumns = []string{
+	"item_id",
+	"definition_id",
+	"owner_id",
+	FieldName,
+	FieldRarity,
+	FieldQuality,
+	FieldFloat,
+	"exterior",
+	FieldPatternSeed,
+	FieldStatus,
+	FieldTags,
+	FieldAttributes,
+}
+
+// parquetRowGroupSize bounds the rows a Parquet writer buffers before it
+// writes them out.
+const parquetRowGroupSize = 64 << 20
+
+// ParseCompression returns the compression of its name; an empty name or
+// "none" is no compression.
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(strings.ToLower(strings.TrimSpace(name))); c {
+	case "", "none":
+		return CompressionNone, nil
+	case CompressionGzip, "gz":
+		return CompressionGzip, nil
+	case CompressionZstd, "zst":
+		return CompressionZstd, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownCompression, name)
+}
+
+// CompressionOf returns the compression of a file by its extension.
+func CompressionOf(path string) Compression {
+	for ext, c := range compressionExts {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return c
+		}
+	}
+
+	return CompressionNone
+}
+
+// Writer writes items to a file in one of the formats, compressing it if
+// asked to. Parquet files are compressed page by page instead of as a
+// whole, so they stay readable by Parquet tools.
+type Writer struct {
+	write func(item *models.Item) error
+	close func() error
+}
+
+// NewWriter returns a writer of items in the format. CSV files start with
+// a header of ExportColumns.
+func NewWriter(w io.Writer, format Format, c Compression) (*Writer, error) {
+	if format == FormatParquet {
+		return parquetWriter(w, c)
+	}
+
+	zw, err := compress(w, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var wr *Writer
+	switch format {
+	case FormatCSV, "":
+		wr, err = csvWriter(zw)
+	case FormatJSONL:
+		wr = jsonWriter(zw)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	closeFile := wr.close
+	wr.close = func() error {
+		if err := closeFile(); err != nil {
+			return err
+		}
+
+		return zw.Close()
+	}
+
+	return wr, nil
+}
+
+// Write writes the item.
+func (w *Writer) Write(item *models.Item) error {
+	return w.write(item)
+}
+
+// Close writes out everything buffered and ends the file. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	return w.close()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func compress(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCompression, c)
+}
+
+func csvWriter(w io.Writer) (*Writer, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ExportColumns); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(ExportColumns))
+
+	return &Writer{
+		write: func(item *models.Item) error {
+			attrs, err := attributesJSON(item.Attributes)
+			if err != nil {
+				return err
+			}
+
+			record = append(record[:0],
+				item.ItemId.String(),
+				item.DefinitionId.String(),
+				optionalUUID(item.OwnerId),
+				item.Name,
+				item.Rarity,
+				item.Quality,
+				strconv.FormatFloat(item.Float, 'g', -1, 64),
+				string(item.Exterior),
+				strconv.Itoa(item.PatternSeed),
+				string(item.Status),
+				strings.Join(item.Tags, defaultTagSeparator),
+				attrs,
+			)
+
+			return cw.Write(record)
+		},
+		close: func() error {
+			cw.Flush()
+
+			return cw.Error()
+		},
+	}, nil
+}
+
+// jsonItem is an exported item in JSON Lines.
+type jsonItem struct {
+	ItemId       uuid.UUID         `json:"item_id"`
+	DefinitionId uuid.UUID         `json:"definition_id"`
+	OwnerId      *uuid.UUID        `json:"owner_id"`
+	Name         string            `json:"name"`
+	Rarity       string            `json:"rarity"`
+	Quality      string            `json:"quality"`
+	Float        float64           `json:"float"`
+	Exterior     models.Exterior   `json:"exterior"`
+	PatternSeed  int               `json:"pattern_seed"`
+	Status       models.ItemStatus `json:"status"`
+	Tags         []string          `json:"tags"`
+	Attributes   models.Attributes `json:"attributes"`
+}
+
+func jsonWriter(w io.Writer) *Writer {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return &Writer{
+		write: func(item *models.Item) error {
+			rec := jsonItem{
+				ItemId:       item.ItemId,
+				DefinitionId: item.DefinitionId,
+				Name:         item.Name,
+				Rarity:       item.Rarity,
+				Quality:      item.Quality,
+				Float:        item.Float,
+				Exterior:     item.Exterior,
+				PatternSeed:  item.PatternSeed,
+				Status:       item.Status,
+				Tags:         item.Tags,
+				Attributes:   item.Attributes,
+			}
+			if item.OwnerId != uuid.Nil {
+				rec.OwnerId = &item.OwnerId
+			}
+			if rec.Tags == nil {
+				rec.Tags = []string{}
+			}
+			if rec.Attributes == nil {
+				rec.Attributes = models.Attributes{}
+			}
+
+			// Encode ends every item with a newline.
+			return enc.Encode(rec)
+		},
+		close: func() error {
+			return nil
+		},
+	}
+}
+
+// parquetItem is an exported item in Parquet. Attributes are a JSON
+// object, as their values have different types.
+type parquetItem struct {
+	ItemId       string   `parquet:"name=item_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DefinitionId string   `parquet:"name=definition_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	OwnerId      *string  `parquet:"name=owner_id, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Name         string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Rarity       string   `parquet:"name=rarity, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Quality      string   `parquet:"name=quality, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Float        float64  `parquet:"name=float, type=DOUBLE"`
+	Exterior     string   `parquet:"name=exterior, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	PatternSeed  int32    `parquet:"name=pattern_seed, type=INT32"`
+	Status       string   `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Tags         []string `parquet:"name=tags, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Attributes   string   `parquet:"name=attributes, type=BYTE_ARRAY, convertedtype=JSON"`
+}
+
+var parquetCodecs = map[Compression]parquet.CompressionCodec{
+	CompressionNone: parquet.CompressionCodec_SNAPPY,
+	CompressionGzip: parquet.CompressionCodec_GZIP,
+	CompressionZstd: parquet.CompressionCodec_ZSTD,
+}
+
+func parquetWriter(w io.Writer, c Compression) (*Writer, error) {
+	codec, ok := parquetCodecs[c]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCompression, c)
+	}
+
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetItem), 1)
+	if err != nil {
+		return nil, fmt.Errorf("create parquet writer: %w", err)
+	}
+	pw.CompressionType = codec
+	pw.RowGroupSize = parquetRowGroupSize
+
+	return &Writer{
+		write: func(item *models.Item) error {
+			attrs, err := attributesJSON(item.Attributes)
+			if err != nil {
+				return err
+			}
+
+			rec := parquetItem{
+				ItemId:       item.ItemId.String(),
+				DefinitionId: item.DefinitionId.String(),
+				Name:         item.Name,
+				Rarity:       item.Rarity,
+				Quality:      item.Quality,
+				Float:        item.Float,
+				Exterior:     string(item.Exterior),
+				PatternSeed:  int32(item.PatternSeed),
+				Status:       string(item.Status),
+				Tags:         item.Tags,
+				Attributes:   attrs,
+			}
+			if item.OwnerId != uuid.Nil {
+				owner := item.OwnerId.String()
+				rec.OwnerId = &owner
+			}
+			if rec.Attributes == "" {
+				rec.Attributes = "{}"
+			}
+
+			return pw.Write(rec)
+		},
+		close: pw.WriteStop,
+	}, nil
+}
+
+// attributesJSON returns the attributes as a JSON object, or an empty
+// string if there are none.
+func attributesJSON(attrs models.Attributes) (string, error) {
+	if len(attrs) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		return "", fmt.Errorf("encode attributes: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func optionalUUID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
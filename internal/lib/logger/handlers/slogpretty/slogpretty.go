@@ -8,7 +8,6 @@ import (
 	"log/slog"
 
 	"github.com/fatih/color"
-
 )
 
 type PrettyHandlerOptions struct {
@@ -26,6 +25,7 @@ func (opts PrettyHandlerOptions) NewPrettyHandler(
 	out io.Writer,
 ) *PrettyHandler {
 	h := &PrettyHandler{
+		opts:    opts,
 		Handler: slog.NewJSONHandler(out, opts.SlogOpts),
 		l:       stdLog.New(out, "", 0),
 	}
@@ -50,13 +50,13 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 	fields := make(map[string]interface{}, r.NumAttrs())
 
 	r.Attrs(func(a slog.Attr) bool {
-		fields[a.Key] = a.Value.Any()
+		h.addField(fields, nil, a)
 
 		return true
 	})
 
 	for _, a := range h.attrs {
-		fields[a.Key] = a.Value.Any()
+		h.addField(fields, nil, a)
 	}
 
 	var b []byte
@@ -82,18 +82,55 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 	return nil
 }
 
+// addField resolves LogValuers and applies the ReplaceAttr option before
+// adding a to fields, so redaction works the same way as in the JSON handler.
+func (h *PrettyHandler) addField(fields map[string]interface{}, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+
+		group := fields
+		if a.Key != "" {
+			group = make(map[string]interface{}, len(attrs))
+			fields[a.Key] = group
+			groups = append(groups, a.Key)
+		}
+
+		for _, ga := range attrs {
+			h.addField(group, groups, ga)
+		}
+
+		return
+	}
+
+	if h.opts.SlogOpts != nil && h.opts.SlogOpts.ReplaceAttr != nil {
+		a = h.opts.SlogOpts.ReplaceAttr(groups, a)
+		if a.Key == "" {
+			return
+		}
+	}
+
+	fields[a.Key] = a.Value.Any()
+}
+
 func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &PrettyHandler{
+		opts:    h.opts,
 		Handler: h.Handler,
 		l:       h.l,
-		attrs:   attrs,
+		attrs:   append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
 	}
 }
 
 func (h *PrettyHandler) WithGroup(name string) slog.Handler {
 	// TODO: implement
 	return &PrettyHandler{
+		opts:    h.opts,
 		Handler: h.Handler.WithGroup(name),
 		l:       h.l,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,272 @@
+package levels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry holds the runtime-adjustable log levels of the application:
+// a root level and optional per-component overrides.
+type Registry struct {
+	root *slog.LevelVar
+
+	mu sync.Mutex
+	// rootBase is the root level set without a timeout, which temporary
+	// levels revert to.
+	rootBase   slog.Level
+	rootRevert revert
+	components map[string]*component
+}
+
+type component struct {
+	level    slog.LevelVar
+	override atomic.Bool
+	// base and baseOverride are the state set without a timeout, which
+	// temporary levels revert to.
+	base         slog.Level
+	baseOverride bool
+	revert       revert
+}
+
+// revert reverts a temporary level once its timeout elapses. Every change
+// of the level bumps gen, so that a timer firing for an older change, even
+// one already waiting for the registry lock, does nothing.
+type revert struct {
+	timer  *time.Timer
+	expiry time.Time
+	gen    uint64
+}
+
+// cancel drops the pending revert, if any. The caller holds the registry lock.
+func (v *revert) cancel() {
+	if v.timer != nil {
+		v.timer.Stop()
+	}
+	v.timer, v.expiry = nil, time.Time{}
+	v.gen++
+}
+
+// schedule calls fn with the registry lock held once ttl elapses, unless
+// the revert is cancelled or scheduled again before. The caller holds the
+// registry lock.
+func (v *revert) schedule(mu *sync.Mutex, ttl time.Duration, fn func()) {
+	v.cancel()
+
+	gen := v.gen
+	v.expiry = time.Now().Add(ttl)
+	v.timer = time.AfterFunc(ttl, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if v.gen != gen {
+			return
+		}
+
+		fn()
+		v.timer, v.expiry = nil, time.Time{}
+		v.gen++
+	})
+}
+
+// State describes a level and when it reverts, if it was set with a timeout.
+type State struct {
+	Level     string     `json:"level"`
+	Override  bool       `json:"override"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Snapshot is the current state of all levels.
+type Snapshot struct {
+	Level      State            `json:"level"`
+	Components map[string]State `json:"components"`
+}
+
+var ErrUnknownComponent = errors.New("unknown log component")
+
+// New returns a new registry with the given root level.
+func New(level slog.Level) *Registry {
+	r := &Registry{
+		root:       &slog.LevelVar{},
+		components: make(map[string]*component),
+	}
+	r.root.Set(level)
+	r.rootBase = level
+
+	return r
+}
+
+// Handler wraps next so that records are filtered by the root level.
+// next should accept every level the registry may be set to.
+func (r *Registry) Handler(next slog.Handler) slog.Handler {
+	return &handler{next: next, level: r.root}
+}
+
+// Component returns a logger for the named component. Its records are
+// filtered by the component override if one is set and by the root level otherwise.
+func (r *Registry) Component(log *slog.Logger, name string) *slog.Logger {
+	r.mu.Lock()
+	c, ok := r.components[name]
+	if !ok {
+		c = &component{}
+		r.components[name] = c
+	}
+	r.mu.Unlock()
+
+	next := log.Handler()
+	if h, ok := next.(*handler); ok {
+		next = h.next
+	}
+
+	return slog.New(&handler{
+		next:  next,
+		level: componentLeveler{root: r.root, c: c},
+	}).With(slog.String("component", name))
+}
+
+// Set sets the level of the named component, or the root level if name is empty.
+// A positive ttl reverts the change once it elapses, to the level last set
+// without a timeout; setting the level again replaces a pending revert.
+func (r *Registry) Set(name string, level slog.Level, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name == "" {
+		r.root.Set(level)
+
+		if ttl <= 0 {
+			r.rootBase = level
+			r.rootRevert.cancel()
+
+			return nil
+		}
+
+		r.rootRevert.schedule(&r.mu, ttl, func() {
+			r.root.Set(r.rootBase)
+		})
+
+		return nil
+	}
+
+	c, ok := r.components[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownComponent, name)
+	}
+
+	c.level.Set(level)
+	c.override.Store(true)
+
+	if ttl <= 0 {
+		c.base, c.baseOverride = level, true
+		c.revert.cancel()
+
+		return nil
+	}
+
+	c.revert.schedule(&r.mu, ttl, func() {
+		c.level.Set(c.base)
+		c.override.Store(c.baseOverride)
+	})
+
+	return nil
+}
+
+// Reset removes the override of the named component so it follows the root level again.
+func (r *Registry) Reset(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.components[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownComponent, name)
+	}
+
+	c.revert.cancel()
+	c.baseOverride = false
+	c.override.Store(false)
+
+	return nil
+}
+
+// Snapshot returns the current levels.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := Snapshot{
+		Level:      newState(r.root.Level(), true, r.rootRevert.expiry),
+		Components: make(map[string]State, len(r.components)),
+	}
+
+	for name, c := range r.components {
+		if c.override.Load() {
+			s.Components[name] = newState(c.level.Level(), true, c.revert.expiry)
+		} else {
+			s.Components[name] = newState(r.root.Level(), false, time.Time{})
+		}
+	}
+
+	return s
+}
+
+// Components returns the names of the registered components.
+func (r *Registry) Components() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.components))
+	for name := range r.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func newState(level slog.Level, override bool, expiry time.Time) State {
+	s := State{Level: level.String(), Override: override}
+	if !expiry.IsZero() {
+		s.ExpiresAt = &expiry
+	}
+
+	return s
+}
+
+type componentLeveler struct {
+	root *slog.LevelVar
+	c    *component
+}
+
+func (l componentLeveler) Level() slog.Level {
+	if l.c.override.Load() {
+		return l.c.level.Level()
+	}
+
+	return l.root.Level()
+}
+
+type handler struct {
+	next  slog.Handler
+	level slog.Leveler
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{next: h.next.WithAttrs(attrs), level: h.level}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,130 @@
+package levels_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"item-service/internal/lib/logger/levels"
+)
+
+const ttl = 20 * time.Millisecond
+
+type step struct {
+	component string
+	level     slog.Level
+	ttl       time.Duration
+	reset     bool
+}
+
+func TestRegistryRevert(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+		// want are the root and storage levels once every timeout elapsed.
+		wantRoot    slog.Level
+		wantStorage slog.Level
+		// wantOverride is whether storage has an override in the end.
+		wantOverride bool
+	}{
+		{
+			name:         "root reverts",
+			steps:        []step{{level: slog.LevelDebug, ttl: ttl}},
+			wantRoot:     slog.LevelInfo,
+			wantStorage:  slog.LevelInfo,
+			wantOverride: false,
+		},
+		{
+			name: "second temporary root level reverts to the base",
+			steps: []step{
+				{level: slog.LevelDebug, ttl: ttl},
+				{level: slog.LevelWarn, ttl: 2 * ttl},
+			},
+			wantRoot:    slog.LevelInfo,
+			wantStorage: slog.LevelInfo,
+		},
+		{
+			name: "permanent root level becomes the base",
+			steps: []step{
+				{level: slog.LevelDebug, ttl: ttl},
+				{level: slog.LevelError},
+			},
+			wantRoot:    slog.LevelError,
+			wantStorage: slog.LevelError,
+		},
+		{
+			name: "temporary override reverts to no override",
+			steps: []step{
+				{component: "storage", level: slog.LevelDebug, ttl: ttl},
+				{component: "storage", level: slog.LevelWarn, ttl: 2 * ttl},
+			},
+			wantRoot:     slog.LevelInfo,
+			wantStorage:  slog.LevelInfo,
+			wantOverride: false,
+		},
+		{
+			name: "temporary override reverts to the permanent one",
+			steps: []step{
+				{component: "storage", level: slog.LevelError},
+				{component: "storage", level: slog.LevelDebug, ttl: ttl},
+				{component: "storage", level: slog.LevelWarn, ttl: ttl},
+			},
+			wantRoot:     slog.LevelInfo,
+			wantStorage:  slog.LevelError,
+			wantOverride: true,
+		},
+		{
+			name: "reset cancels the revert",
+			steps: []step{
+				{component: "storage", level: slog.LevelDebug, ttl: ttl},
+				{component: "storage", reset: true},
+				{level: slog.LevelWarn},
+			},
+			wantRoot:     slog.LevelWarn,
+			wantStorage:  slog.LevelWarn,
+			wantOverride: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := levels.New(slog.LevelInfo)
+			r.Component(slog.Default(), "storage")
+
+			for _, s := range tt.steps {
+				var err error
+				if s.reset {
+					err = r.Reset(s.component)
+				} else {
+					err = r.Set(s.component, s.level, s.ttl)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			time.Sleep(5 * ttl)
+
+			got := r.Snapshot()
+			if got.Level.Level != tt.wantRoot.String() || got.Level.ExpiresAt != nil {
+				t.Errorf("root = %+v, want %s without expiry", got.Level, tt.wantRoot)
+			}
+
+			storage := got.Components["storage"]
+			if storage.Level != tt.wantStorage.String() || storage.Override != tt.wantOverride || storage.ExpiresAt != nil {
+				t.Errorf("storage = %+v, want %s with override %t without expiry", storage, tt.wantStorage, tt.wantOverride)
+			}
+		})
+	}
+}
+
+func TestRegistryUnknownComponent(t *testing.T) {
+	r := levels.New(slog.LevelInfo)
+
+	if err := r.Set("nope", slog.LevelDebug, 0); err == nil {
+		t.Error("Set of an unknown component succeeded")
+	}
+	if err := r.Reset("nope"); err == nil {
+		t.Error("Reset of an unknown component succeeded")
+	}
+}
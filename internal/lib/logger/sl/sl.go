@@ -2,12 +2,32 @@ package sl
 
 import (
 	"log/slog"
-
+	"strings"
 )
 
+// Redacted replaces the value of sensitive attributes.
+const Redacted = "[REDACTED]"
+
 func Err(err error) slog.Attr {
 	return slog.Attr{
 		Key:   "error",
 		Value: slog.StringValue(err.Error()),
 	}
-}
\ No newline at end of file
+}
+
+// Redact returns a slog.HandlerOptions.ReplaceAttr function that masks
+// the values of attributes whose keys are in the denylist (case-insensitive).
+func Redact(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	deny := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		deny[strings.ToLower(strings.TrimSpace(k))] = struct{}{}
+	}
+
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if _, ok := deny[strings.ToLower(a.Key)]; ok && a.Value.Kind() != slog.KindGroup {
+			return slog.String(a.Key, Redacted)
+		}
+
+		return a
+	}
+}
@@ -0,0 +1,193 @@
+// Package loot picks the drops of case openings. A drop is a pure function
+// of the drop table, the drop pool and a seed, so every opening can be
+// reproduced from its stored seed.
+package loot
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"sort"
+
+	"item-service/internal/domain/models"
+)
+
+// patternSeeds is the number of distinct pattern seeds.
+const patternSeeds = 1000
+
+// ErrEmptyPool is returned when no item of the pool has a rarity with a positive weight.
+var ErrEmptyPool = errors.New("no droppable items in the pool")
+
+// DropTable maps rarities to their relative drop weights.
+type DropTable map[string]float64
+
+// Drop is the outcome of a case opening.
+type Drop struct {
+	Definition  *models.ItemDefinition
+	Float       float64
+	PatternSeed int
+}
+
+type tier struct {
+	rarity string
+	weight float64
+	defs   []*models.ItemDefinition
+}
+
+// tiers groups the pool by rarity. Rarities without a positive weight are
+// left out. Tiers are ordered by rarity and definitions by ID, so that the
+// outcome does not depend on the order of the pool.
+func (t DropTable) tiers(pool []*models.ItemDefinition) []tier {
+	byRarity := make(map[string]*tier)
+
+	for _, def := range pool {
+		weight := t[def.Rarity]
+		if weight <= 0 {
+			continue
+		}
+
+		tr, ok := byRarity[def.Rarity]
+		if !ok {
+			tr = &tier{rarity: def.Rarity, weight: weight}
+			byRarity[def.Rarity] = tr
+		}
+
+		tr.defs = append(tr.defs, def)
+	}
+
+	tiers := make([]tier, 0, len(byRarity))
+	for _, tr := range byRarity {
+		sort.Slice(tr.defs, func(i, j int) bool {
+			return bytes.Compare(tr.defs[i].DefinitionId[:], tr.defs[j].DefinitionId[:]) < 0
+		})
+
+		tiers = append(tiers, *tr)
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].rarity < tiers[j].rarity })
+
+	return tiers
+}
+
+// Probabilities returns the drop probability of every rarity present in the pool.
+func (t DropTable) Probabilities(pool []*models.ItemDefinition) map[string]float64 {
+	tiers := t.tiers(pool)
+
+	var total float64
+	for _, tr := range tiers {
+		total += tr.weight
+	}
+
+	probs := make(map[string]float64, len(tiers))
+	for _, tr := range tiers {
+		probs[tr.rarity] = tr.weight / total
+	}
+
+	return probs
+}
+
+// Open picks a drop from the pool: a rarity by its weight, a definition of
+// that rarity uniformly, then a float within the definition's range and a
+// pattern seed.
+func Open(table DropTable, pool []*models.ItemDefinition, seed int64) (Drop, error) {
+	tiers := table.tiers(pool)
+	if len(tiers) == 0 {
+		return Drop{}, ErrEmptyPool
+	}
+
+	return roll(rand.New(rand.NewSource(seed)), tiers), nil
+}
+
+func roll(rng *rand.Rand, tiers []tier) Drop {
+	var total float64
+	for _, tr := range tiers {
+		total += tr.weight
+	}
+
+	// The last tier also takes what floating point rounding leaves over.
+	picked := tiers[len(tiers)-1]
+	x := rng.Float64() * total
+	for _, tr := range tiers {
+		if x < tr.weight {
+			picked = tr
+			break
+		}
+
+		x -= tr.weight
+	}
+
+	def := picked.defs[rng.Intn(len(picked.defs))]
+
+	return Drop{
+		Definition:  def,
+		Float:       def.MinFloat + rng.Float64()*(def.MaxFloat-def.MinFloat),
+		PatternSeed: rng.Intn(patternSeeds),
+	}
+}
+
+// TradeUpInput is an input item of a trade-up contract together with the
+// definitions it can turn into: those of the next rarity in its collections.
+type TradeUpInput struct {
+	Float      float64
+	Candidates []*models.ItemDefinition
+}
+
+// TradeUp picks the output of a trade-up contract. Every input contributes
+// an equal share of the odds, split evenly among its candidates. The output
+// float is the average input float mapped into the float range of the
+// output definition.
+func TradeUp(inputs []TradeUpInput, seed int64) (Drop, error) {
+	odds := make(map[*models.ItemDefinition]float64)
+	byID := make(map[[16]byte]*models.ItemDefinition)
+
+	var sum float64
+	for _, in := range inputs {
+		sum += in.Float
+
+		for _, def := range in.Candidates {
+			if seen, ok := byID[def.DefinitionId]; ok {
+				def = seen
+			}
+			byID[def.DefinitionId] = def
+			odds[def] += 1 / float64(len(in.Candidates))
+		}
+	}
+
+	if len(odds) == 0 {
+		return Drop{}, ErrEmptyPool
+	}
+
+	defs := make([]*models.ItemDefinition, 0, len(odds))
+	for def := range odds {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return bytes.Compare(defs[i].DefinitionId[:], defs[j].DefinitionId[:]) < 0
+	})
+
+	var total float64
+	for _, def := range defs {
+		total += odds[def]
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	picked := defs[len(defs)-1]
+	x := rng.Float64() * total
+	for _, def := range defs {
+		if x < odds[def] {
+			picked = def
+			break
+		}
+
+		x -= odds[def]
+	}
+
+	avg := sum / float64(len(inputs))
+
+	return Drop{
+		Definition:  picked,
+		Float:       picked.MinFloat + avg*(picked.MaxFloat-picked.MinFloat),
+		PatternSeed: rng.Intn(patternSeeds),
+	}, nil
+}
@@ -0,0 +1,203 @@
+package loot_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/loot"
+)
+
+var table = loot.DropTable{
+	"Mil-Spec":     79.92,
+	"Restricted":   15.98,
+	"Classified":   3.2,
+	"Covert":       0.64,
+	"Rare Special": 0.26,
+}
+
+// pool returns two definitions of every rarity of the table, with IDs
+// derived from their rarity so that drops depend on the seed only.
+func pool() []*models.ItemDefinition {
+	var defs []*models.ItemDefinition
+	for rarity := range table {
+		for i := 0; i < 2; i++ {
+			defs = append(defs, &models.ItemDefinition{
+				DefinitionId: uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%d", rarity, i))),
+				Rarity:       rarity,
+				MinFloat:     0.1,
+				MaxFloat:     0.6,
+			})
+		}
+	}
+
+	return defs
+}
+
+func TestProbabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    loot.DropTable
+		rarities []string
+		want     map[string]float64
+	}{
+		{
+			name:     "weights are normalized",
+			table:    loot.DropTable{"a": 3, "b": 1},
+			rarities: []string{"a", "b"},
+			want:     map[string]float64{"a": 0.75, "b": 0.25},
+		},
+		{
+			name:     "rarities missing from the pool are left out",
+			table:    loot.DropTable{"a": 3, "b": 1, "c": 4},
+			rarities: []string{"a", "c"},
+			want:     map[string]float64{"a": 3.0 / 7, "c": 4.0 / 7},
+		},
+		{
+			name:     "rarities without a positive weight are left out",
+			table:    loot.DropTable{"a": 1, "b": 0},
+			rarities: []string{"a", "b", "unknown"},
+			want:     map[string]float64{"a": 1},
+		},
+		{
+			name:     "empty pool",
+			table:    loot.DropTable{"a": 1},
+			rarities: nil,
+			want:     map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defs []*models.ItemDefinition
+			for _, rarity := range tt.rarities {
+				defs = append(defs, &models.ItemDefinition{DefinitionId: uuid.New(), Rarity: rarity})
+			}
+
+			got := tt.table.Probabilities(defs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Probabilities = %v, want %v", got, tt.want)
+			}
+			for rarity, p := range tt.want {
+				if math.Abs(got[rarity]-p) > 1e-12 {
+					t.Errorf("Probabilities[%s] = %v, want %v", rarity, got[rarity], p)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenMatchesWeights opens many cases, each with its own seed like
+// OpenCase, and checks the drops per rarity against the table with
+// Pearson's chi-square test.
+func TestOpenMatchesWeights(t *testing.T) {
+	const openings = 50_000
+
+	// The 0.999 quantile of the chi-square distribution with 4 degrees of
+	// freedom, one less than the rarities of the table. The seed is fixed,
+	// so the test does not flake; a wrong weighting exceeds it by far.
+	const bound = 18.467
+
+	defs := pool()
+	probs := table.Probabilities(defs)
+
+	rng := rand.New(rand.NewSource(1))
+	observed := make(map[string]int, len(probs))
+	for i := 0; i < openings; i++ {
+		drop, err := loot.Open(table, defs, rng.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		observed[drop.Definition.Rarity]++
+	}
+
+	var chi float64
+	for rarity, p := range probs {
+		expected := p * openings
+		d := float64(observed[rarity]) - expected
+		chi += d * d / expected
+	}
+
+	t.Logf("chi-square = %.2f; observed %v", chi, observed)
+
+	if chi > bound {
+		t.Errorf("chi-square = %.2f, want at most %.2f; observed %v", chi, bound, observed)
+	}
+}
+
+func TestOpenDeterministic(t *testing.T) {
+	defs := pool()
+
+	reversed := make([]*models.ItemDefinition, len(defs))
+	for i, def := range defs {
+		reversed[len(defs)-1-i] = def
+	}
+
+	for seed := int64(0); seed < 100; seed++ {
+		drop, err := loot.Open(table, defs, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		again, err := loot.Open(table, reversed, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if drop != again {
+			t.Fatalf("seed %d: drop %+v from the reversed pool, want %+v", seed, again, drop)
+		}
+
+		def := drop.Definition
+		if drop.Float < def.MinFloat || drop.Float >= def.MaxFloat {
+			t.Errorf("seed %d: float %v outside [%v, %v)", seed, drop.Float, def.MinFloat, def.MaxFloat)
+		}
+		if drop.PatternSeed < 0 || drop.PatternSeed >= 1000 {
+			t.Errorf("seed %d: pattern seed %d outside [0, 1000)", seed, drop.PatternSeed)
+		}
+	}
+}
+
+func TestOpenEmptyPool(t *testing.T) {
+	defs := []*models.ItemDefinition{{DefinitionId: uuid.New(), Rarity: "Consumer Grade"}}
+
+	if _, err := loot.Open(table, defs, 1); !errors.Is(err, loot.ErrEmptyPool) {
+		t.Errorf("Open error = %v, want %v", err, loot.ErrEmptyPool)
+	}
+}
+
+func TestTradeUpFloat(t *testing.T) {
+	out := &models.ItemDefinition{DefinitionId: uuid.New(), Rarity: "Covert", MinFloat: 0.2, MaxFloat: 0.6}
+
+	tests := []struct {
+		floats []float64
+		want   float64
+	}{
+		{[]float64{0, 0}, 0.2},
+		{[]float64{1, 1}, 0.6},
+		{[]float64{0.25, 0.75}, 0.4},
+		{[]float64{0.1, 0.2, 0.3, 0.4}, 0.3},
+	}
+
+	for _, tt := range tests {
+		inputs := make([]loot.TradeUpInput, 0, len(tt.floats))
+		for _, f := range tt.floats {
+			inputs = append(inputs, loot.TradeUpInput{Float: f, Candidates: []*models.ItemDefinition{out}})
+		}
+
+		drop, err := loot.TradeUp(inputs, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if drop.Definition != out || math.Abs(drop.Float-tt.want) > 1e-12 {
+			t.Errorf("TradeUp(%v) = %s %v, want %s %v", tt.floats, drop.Definition.Rarity, drop.Float, out.Rarity, tt.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Package seed generates fake but realistic item data for development and
+// load tests. The data follows from the options alone: the same options
+// give the same definitions, items, owners, IDs and times on every run.
+//
+// The data is written as a snapshot, so it is loaded with the restore of
+// package snapshot and keeps its IDs.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/snapshot"
+)
+
+const (
+	DefaultItems       = 10000
+	DefaultDefinitions = 200
+	DefaultOwners      = 100
+)
+
+// Epoch is when the generated data begins; items are created within the
+// year after it.
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// rareSpecial is the rarity of knives and gloves in the default drop table.
+const rareSpecial = "Rare Special"
+
+var (
+	weapons = []string{
+		"AK-47", "M4A4", "M4A1-S", "AWP", "Desert Eagle", "USP-S", "Glock-18",
+		"P250", "Five-SeveN", "Tec-9", "CZ75-Auto", "P2000", "Dual Berettas",
+		"R8 Revolver", "FAMAS", "Galil AR", "SG 553", "AUG", "SSG 08", "SCAR-20",
+		"G3SG1", "MAC-10", "MP9", "MP7", "MP5-SD", "UMP-45", "P90", "PP-Bizon",
+		"Nova", "XM1014", "MAG-7", "Sawed-Off", "M249", "Negev",
+	}
+	specials = []string{
+		"★ Karambit", "★ Butterfly Knife", "★ M9 Bayonet", "★ Bayonet",
+		"★ Flip Knife", "★ Gut Knife", "★ Huntsman Knife", "★ Falchion Knife",
+		"★ Talon Knife", "★ Skeleton Knife", "★ Sport Gloves", "★ Driver Gloves",
+	}
+	finishes = []string{
+		"Redline", "Asiimov", "Hyper Beast", "Vulcan", "Fire Serpent",
+		"Neo-Noir", "Printstream", "Bloodsport", "Neon Rider", "Fade",
+		"Doppler", "Tiger Tooth", "Marble Fade", "Slaughter", "Crimson Web",
+		"Case Hardened", "Blue Steel", "Night", "Safari Mesh", "Boreal Forest",
+		"Urban Masked", "Forest DDPAT", "Sand Dune", "Groundwater", "Bone Mask",
+		"Jungle Tiger", "Desert Storm", "Contractor", "Army Sheen", "Candy Apple",
+		"Emerald", "Ultraviolet", "Damascus Steel", "Rust Coat", "Scorched",
+		"Stained", "Cyrex", "Wasteland Rebel", "Phantom Disruptor", "Elite Build",
+	}
+
+	// floatRanges are the wear ranges of definitions.
+	floatRanges = [][2]float64{
+		{0, 1}, {0, 1}, {0, 0.8}, {0.06, 0.8}, {0, 0.7}, {0.1, 1}, {0, 0.5}, {0, 0.08},
+	}
+
+	// qualities are the qualities of items and their weights: a tenth of
+	// the items count kills and few are souvenirs.
+	qualities = []weighted{
+		{"Normal", 89},
+		{"StatTrak™", 10},
+		{"Souvenir", 1},
+	}
+)
+
+var ErrInvalidOptions = errors.New("invalid seed options")
+
+// Options configure the generated data.
+type Options struct {
+	// Seed seeds the random numbers; the same seed gives the same data.
+	Seed int64
+	// Items is the number of items generated.
+	Items int
+	// Definitions is the number of definitions the items are copies of.
+	Definitions int
+	// Owners is the number of owners the items are spread over. With no
+	// owners the items are in no inventory.
+	Owners int
+	// Rarities maps item rarities to their relative weights, like the loot
+	// drop table. Definitions and items get rarities in proportion to them.
+	Rarities map[string]float64
+}
+
+func (o Options) validate() error {
+	switch {
+	case o.Items < 0:
+		return fmt.Errorf("%w: negative number of items", ErrInvalidOptions)
+	case o.Owners < 0:
+		return fmt.Errorf("%w: negative number of owners", ErrInvalidOptions)
+	case o.Definitions < 1:
+		return fmt.Errorf("%w: at least one definition is needed", ErrInvalidOptions)
+	case o.Definitions > len(weapons)*len(finishes):
+		return fmt.Errorf("%w: at most %d definitions can be named", ErrInvalidOptions, len(weapons)*len(finishes))
+	}
+
+	var total float64
+	for rarity, w := range o.Rarities {
+		if w < 0 {
+			return fmt.Errorf("%w: rarity %s has a negative weight", ErrInvalidOptions, rarity)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: no rarity has a weight", ErrInvalidOptions)
+	}
+
+	return nil
+}
+
+// Write writes the generated definitions, items and the transfers of the
+// owned items to w. Items are generated one at a time, so any number of
+// them fits in memory.
+func Write(w *snapshot.Writer, opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	g := newGenerator(opts)
+	defs := g.definitions()
+
+	for _, def := range defs {
+		if err := w.Write("item_definitions", definitionRow{
+			Id:        def.DefinitionId,
+			Name:      def.Name,
+			Rarity:    def.Rarity,
+			MinFloat:  def.MinFloat,
+			MaxFloat:  def.MaxFloat,
+			CreatedAt: def.CreatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	err := g.items(defs, func(inst *models.ItemInstance) error {
+		return w.Write("items", itemRow{
+			Id:           inst.InstanceId,
+			DefinitionId: inst.DefinitionId,
+			OwnerId:      optionalUUID(inst.OwnerId),
+			Quality:      inst.Quality,
+			Float:        inst.Float,
+			PatternSeed:  inst.PatternSeed,
+			Attributes:   models.Attributes{},
+			Tags:         []string{},
+			Status:       models.ItemStatusPublished,
+			CreatedAt:    inst.CreatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// The transfers follow all items, so the items are generated again.
+	// Transfer IDs come from a random source of their own to leave the
+	// items as they were.
+	g = newGenerator(opts)
+	ids := rand.New(rand.NewSource(opts.Seed + 1))
+
+	return g.items(g.definitions(), func(inst *models.ItemInstance) error {
+		if inst.OwnerId == uuid.Nil {
+			return nil
+		}
+
+		return w.Write("ownership_history", transferRow{
+			Id:            newUUID(ids),
+			ItemId:        inst.InstanceId,
+			ToOwner:       inst.OwnerId,
+			TransferredAt: inst.CreatedAt,
+		})
+	})
+}
+
+type weighted struct {
+	value  string
+	weight float64
+}
+
+type generator struct {
+	opts     Options
+	rng      *rand.Rand
+	rarities []weighted
+	owners   []uuid.UUID
+	// specialNames counts the names given to knives and gloves.
+	specialNames int
+}
+
+func newGenerator(opts Options) *generator {
+	g := &generator{
+		opts: opts,
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+	}
+
+	// Maps are iterated in random order; the rarities must not be.
+	for rarity, w := range opts.Rarities {
+		if w > 0 {
+			g.rarities = append(g.rarities, weighted{rarity, w})
+		}
+	}
+	sort.Slice(g.rarities, func(i, j int) bool { return g.rarities[i].value < g.rarities[j].value })
+
+	for i := 0; i < opts.Owners; i++ {
+		g.owners = append(g.owners, newUUID(g.rng))
+	}
+
+	return g
+}
+
+// definitions returns the definitions, with every rarity having a share of
+// them in proportion to its weight but at least one.
+func (g *generator) definitions() []*models.ItemDefinition {
+	var total float64
+	for _, r := range g.rarities {
+		total += r.weight
+	}
+
+	counts := make([]int, len(g.rarities))
+	n := 0
+	for i, r := range g.rarities {
+		counts[i] = max(1, int(math.Round(float64(g.opts.Definitions)*r.weight/total)))
+		n += counts[i]
+	}
+
+	// Rounding may leave too many or too few; the most common rarity
+	// makes up for it.
+	common := 0
+	for i, r := range g.rarities {
+		if r.weight > g.rarities[common].weight {
+			common = i
+		}
+	}
+	counts[common] = max(1, counts[common]+g.opts.Definitions-n)
+
+	names := make(map[string]bool)
+	var defs []*models.ItemDefinition
+	for i, r := range g.rarities {
+		for j := 0; j < counts[i]; j++ {
+			name := g.name(r.value, names)
+			fr := floatRanges[g.rng.Intn(len(floatRanges))]
+
+			defs = append(defs, &models.ItemDefinition{
+				DefinitionId: newUUID(g.rng),
+				Name:         name,
+				Rarity:       r.value,
+				MinFloat:     fr[0],
+				MaxFloat:     fr[1],
+				CreatedAt:    Epoch,
+			})
+		}
+	}
+
+	return defs
+}
+
+// name returns a name not in names yet and adds it. Names only need to be
+// unique per rarity, but unique names are easier to tell apart.
+func (g *generator) name(rarity string, names map[string]bool) string {
+	kinds := weapons
+	// Knives and gloves may run out of names; weapons make up for them.
+	if rarity == rareSpecial && g.specialNames < len(specials)*len(finishes) {
+		kinds = specials
+		g.specialNames++
+	}
+
+	for {
+		name := kinds[g.rng.Intn(len(kinds))] + " | " + finishes[g.rng.Intn(len(finishes))]
+		if !names[name] {
+			names[name] = true
+
+			return name
+		}
+	}
+}
+
+// items generates the items and passes them to fn. An item has a rarity
+// drawn by weight and a definition of that rarity drawn uniformly.
+func (g *generator) items(defs []*models.ItemDefinition, fn func(*models.ItemInstance) error) error {
+	byRarity := make(map[string][]*models.ItemDefinition)
+	for _, def := range defs {
+		byRarity[def.Rarity] = append(byRarity[def.Rarity], def)
+	}
+
+	year := 365 * 24 * time.Hour
+
+	for i := 0; i < g.opts.Items; i++ {
+		rarityDefs := byRarity[g.pick(g.rarities)]
+		def := rarityDefs[g.rng.Intn(len(rarityDefs))]
+
+		inst := &models.ItemInstance{
+			InstanceId:   newUUID(g.rng),
+			DefinitionId: def.DefinitionId,
+			Quality:      g.pick(qualities),
+			Float:        def.MinFloat + g.rng.Float64()*(def.MaxFloat-def.MinFloat),
+			PatternSeed:  g.rng.Intn(1000),
+			CreatedAt:    Epoch.Add(time.Duration(g.rng.Int63n(int64(year)))).Truncate(time.Microsecond),
+		}
+		if len(g.owners) > 0 {
+			inst.OwnerId = g.owners[g.rng.Intn(len(g.owners))]
+		}
+
+		if err := fn(inst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pick draws a value by weight.
+func (g *generator) pick(values []weighted) string {
+	var total float64
+	for _, v := range values {
+		total += v.weight
+	}
+
+	x := g.rng.Float64() * total
+	for _, v := range values {
+		if x < v.weight {
+			return v.value
+		}
+		x -= v.weight
+	}
+
+	return values[len(values)-1].value
+}
+
+// newUUID returns a version 4 UUID drawn from rng.
+func newUUID(rng *rand.Rand) uuid.UUID {
+	id, err := uuid.NewRandomFromReader(rng)
+	if err != nil {
+		// Reading from a *rand.Rand never fails.
+		panic(err)
+	}
+
+	return id
+}
+
+func optionalUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	return &id
+}
+
+// The rows of the tables written, keyed by column name like in snapshots.
+type (
+	definitionRow struct {
+		Id        uuid.UUID `json:"id"`
+		Name      string    `json:"name"`
+		Rarity    string    `json:"rarity"`
+		MinFloat  float64   `json:"min_float"`
+		MaxFloat  float64   `json:"max_float"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	itemRow struct {
+		Id           uuid.UUID         `json:"id"`
+		DefinitionId uuid.UUID         `json:"definition_id"`
+		OwnerId      *uuid.UUID        `json:"owner_id"`
+		Quality      string            `json:"quality"`
+		Float        float64           `json:"float_value"`
+		PatternSeed  int               `json:"pattern_seed"`
+		Attributes   models.Attributes `json:"attributes"`
+		Tags         []string          `json:"tags"`
+		Status       models.ItemStatus `json:"status"`
+		CreatedAt    time.Time         `json:"created_at"`
+	}
+
+	transferRow struct {
+		Id            uuid.UUID  `json:"id"`
+		ItemId        uuid.UUID  `json:"item_id"`
+		FromOwner     *uuid.UUID `json:"from_owner"`
+		ToOwner       uuid.UUID  `json:"to_owner"`
+		TransferredAt time.Time  `json:"transferred_at"`
+	}
+)
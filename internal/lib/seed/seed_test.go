@@ -0,0 +1,171 @@
+package seed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"item-service/internal/lib/seed"
+	"item-service/internal/lib/snapshot"
+)
+
+var opts = seed.Options{
+	Seed:        42,
+	Items:       500,
+	Definitions: 30,
+	Owners:      20,
+	Rarities:    map[string]float64{"Mil-Spec": 80, "Restricted": 16, "Covert": 4},
+}
+
+// generate returns the rows of the generated data per table.
+func generate(t *testing.T, opts seed.Options) map[string][]json.RawMessage {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := snapshot.NewWriter(&buf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := seed.Write(w, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := snapshot.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	rows := make(map[string][]json.RawMessage)
+	for {
+		table, row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows[table] = append(rows[table], row)
+	}
+}
+
+func TestWriteDeterministic(t *testing.T) {
+	first := generate(t, opts)
+
+	if got := generate(t, opts); !reflect.DeepEqual(got, first) {
+		t.Error("the same options gave different data")
+	}
+
+	other := opts
+	other.Seed++
+	if got := generate(t, other); reflect.DeepEqual(got, first) {
+		t.Error("another seed gave the same data")
+	}
+}
+
+func TestWriteCounts(t *testing.T) {
+	unowned := opts
+	unowned.Owners = 0
+
+	tests := []struct {
+		name      string
+		opts      seed.Options
+		transfers int
+	}{
+		{"owned", opts, opts.Items},
+		{"unowned", unowned, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := generate(t, tt.opts)
+
+			if got := len(rows["item_definitions"]); got != tt.opts.Definitions {
+				t.Errorf("%d definitions, want %d", got, tt.opts.Definitions)
+			}
+			if got := len(rows["items"]); got != tt.opts.Items {
+				t.Errorf("%d items, want %d", got, tt.opts.Items)
+			}
+			if got := len(rows["ownership_history"]); got != tt.transfers {
+				t.Errorf("%d transfers, want %d", got, tt.transfers)
+			}
+
+			owners := make(map[string]bool)
+			for _, raw := range rows["items"] {
+				var item struct {
+					OwnerId *string `json:"owner_id"`
+				}
+				if err := json.Unmarshal(raw, &item); err != nil {
+					t.Fatal(err)
+				}
+				if item.OwnerId != nil {
+					owners[*item.OwnerId] = true
+				}
+			}
+			if len(owners) > tt.opts.Owners {
+				t.Errorf("items have %d owners, want at most %d", len(owners), tt.opts.Owners)
+			}
+		})
+	}
+}
+
+func TestWriteRarities(t *testing.T) {
+	rows := generate(t, opts)
+
+	for _, raw := range rows["item_definitions"] {
+		var def struct {
+			Rarity string `json:"rarity"`
+		}
+		if err := json.Unmarshal(raw, &def); err != nil {
+			t.Fatal(err)
+		}
+
+		if opts.Rarities[def.Rarity] <= 0 {
+			t.Errorf("definition of rarity %q, which has no weight", def.Rarity)
+		}
+	}
+}
+
+func TestWriteInvalidOptions(t *testing.T) {
+	valid := func(modify func(*seed.Options)) seed.Options {
+		o := opts
+		o.Rarities = map[string]float64{"Mil-Spec": 1}
+		modify(&o)
+
+		return o
+	}
+
+	tests := []struct {
+		name string
+		opts seed.Options
+	}{
+		{"negative items", valid(func(o *seed.Options) { o.Items = -1 })},
+		{"negative owners", valid(func(o *seed.Options) { o.Owners = -1 })},
+		{"no definitions", valid(func(o *seed.Options) { o.Definitions = 0 })},
+		{"too many definitions", valid(func(o *seed.Options) { o.Definitions = 1 << 20 })},
+		{"negative weight", valid(func(o *seed.Options) { o.Rarities["Covert"] = -1 })},
+		{"no rarities", valid(func(o *seed.Options) { o.Rarities = nil })},
+		{"zero weights", valid(func(o *seed.Options) { o.Rarities = map[string]float64{"Covert": 0} })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := snapshot.NewWriter(io.Discard, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := seed.Write(w, tt.opts); !errors.Is(err, seed.ErrInvalidOptions) {
+				t.Errorf("error = %v, want %v", err, seed.ErrInvalidOptions)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+// Package snapshot reads and writes snapshots of the item database: every
+// row of every table, in a format independent of the storage it was taken
+// from. A snapshot is a gzipped JSON Lines file of envelopes:
+//
+//	{"header": {"format": "item-service-snapshot", "version": 1, "schema_version": 16, "created_at": "..."}}
+//	{"table": "item_definitions", "row": {"id": "...", "name": "...", ...}}
+//	...
+//	{"end": {"rows": {"item_definitions": 120, "items": 5000, ...}}}
+//
+// Rows are JSON objects keyed by column name. Tables follow each other in
+// the order of Tables, so that rows are restored after the rows they
+// reference. The end envelope lets readers tell a complete snapshot from a
+// truncated one.
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// Format identifies snapshot files.
+	Format = "item-service-snapshot"
+	// Version is the version of the file format written by this package.
+	// The layout of the rows is versioned by the schema version instead.
+	Version = 1
+)
+
+// maxLine bounds the length of an envelope.
+const maxLine = 16 << 20
+
+// Tables are the tables of a snapshot in the order they are written and
+// restored: referenced tables come first.
+var Tables = []string{
+	"item_definitions",
+	"items",
+	"ownership_history",
+	"trade_offers",
+	"trade_offer_items",
+	"collections",
+	"collection_items",
+	"case_openings",
+	"trade_ups",
+	"trade_up_inputs",
+	"price_observations",
+	"item_images",
+	"item_definition_translations",
+	"item_status_schedules",
+	"item_locks",
+}
+
+var (
+	ErrInvalid            = errors.New("not a valid snapshot")
+	ErrUnsupportedVersion = errors.New("unsupported snapshot format version")
+	ErrTruncated          = errors.New("snapshot is truncated")
+)
+
+// Header describes a snapshot.
+type Header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// SchemaVersion is the version of the database schema the rows follow.
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type trailer struct {
+	Rows map[string]int `json:"rows"`
+}
+
+type envelope struct {
+	Header *Header         `json:"header,omitempty"`
+	Table  string          `json:"table,omitempty"`
+	Row    json.RawMessage `json:"row,omitempty"`
+	End    *trailer        `json:"end,omitempty"`
+}
+
+// tableIndex returns the position of the table in Tables, or -1.
+func tableIndex(table string) int {
+	for i, t := range Tables {
+		if t == table {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Writer writes a snapshot.
+type Writer struct {
+	zw    *gzip.Writer
+	bw    *bufio.Writer
+	enc   *json.Encoder
+	table int
+	rows  map[string]int
+}
+
+// NewWriter writes the header of a snapshot of the given schema version
+// to w and returns a writer of its rows.
+func NewWriter(w io.Writer, schemaVersion int) (*Writer, error) {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	sw := &Writer{
+		zw:   zw,
+		bw:   bw,
+		enc:  json.NewEncoder(bw),
+		rows: make(map[string]int, len(Tables)),
+	}
+	sw.enc.SetEscapeHTML(false)
+
+	if err := sw.enc.Encode(envelope{Header: &Header{
+		Format:        Format,
+		Version:       Version,
+		SchemaVersion: schemaVersion,
+		CreatedAt:     time.Now().UTC(),
+	}}); err != nil {
+		return nil, err
+	}
+
+	return sw, nil
+}
+
+// Write writes a row of the table. The row is marshalled to JSON unless it
+// is a json.RawMessage already. Tables must be written in the order of
+// Tables.
+func (w *Writer) Write(table string, row any) error {
+	i := tableIndex(table)
+	if i < 0 {
+		return fmt.Errorf("unknown table %q", table)
+	}
+	if i < w.table {
+		return fmt.Errorf("table %s is written after %s", table, Tables[w.table])
+	}
+	w.table = i
+
+	raw, ok := row.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(row); err != nil {
+			return fmt.Errorf("encode %s row: %w", table, err)
+		}
+	}
+
+	if err := w.enc.Encode(envelope{Table: table, Row: raw}); err != nil {
+		return err
+	}
+	w.rows[table]++
+
+	return nil
+}
+
+// Rows returns the number of rows written per table.
+func (w *Writer) Rows() map[string]int {
+	return w.rows
+}
+
+// Close ends the snapshot. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.enc.Encode(envelope{End: &trailer{Rows: w.rows}}); err != nil {
+		return err
+	}
+
+	if err := w.bw.Flush(); err != nil {
+		return err
+	}
+
+	return w.zw.Close()
+}
+
+// Abort ends a snapshot that failed without its end envelope, so that
+// readers report it as truncated instead of taking it for complete. It
+// does not close the underlying writer.
+func (w *Writer) Abort() error {
+	if err := w.bw.Flush(); err != nil {
+		return err
+	}
+
+	return w.zw.Close()
+}
+
+// Reader reads a snapshot.
+type Reader struct {
+	zr     *gzip.Reader
+	sc     *bufio.Scanner
+	header Header
+	line   int
+	table  int
+	rows   map[string]int
+	done   bool
+}
+
+// NewReader reads the header of the snapshot in r and checks that its
+// format is supported.
+func NewReader(r io.Reader) (*Reader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	sc := bufio.NewScanner(zr)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+	sc.Split(scanLines)
+
+	sr := &Reader{
+		zr:   zr,
+		sc:   sc,
+		rows: make(map[string]int, len(Tables)),
+	}
+
+	env, err := sr.next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalid)
+		}
+
+		return nil, err
+	}
+
+	if env.Header == nil || env.Header.Format != Format {
+		return nil, fmt.Errorf("%w: the header is missing", ErrInvalid)
+	}
+
+	if env.Header.Version != Version {
+		return nil, fmt.Errorf("%w: %d, expected %d", ErrUnsupportedVersion, env.Header.Version, Version)
+	}
+	sr.header = *env.Header
+
+	return sr, nil
+}
+
+// Header returns the header of the snapshot.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next returns the next row and its table. It returns io.EOF after the
+// last row, once the snapshot was checked to be complete.
+func (r *Reader) Next() (string, json.RawMessage, error) {
+	if r.done {
+		return "", nil, io.EOF
+	}
+
+	env, err := r.next()
+	if errors.Is(err, io.EOF) {
+		return "", nil, ErrTruncated
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch {
+	case env.End != nil:
+		return "", nil, r.end(env.End)
+	case env.Table == "" || len(env.Row) == 0:
+		return "", nil, fmt.Errorf("%w: line %d is neither a row nor the end", ErrInvalid, r.line)
+	}
+
+	i := tableIndex(env.Table)
+	if i < 0 {
+		return "", nil, fmt.Errorf("%w: line %d: unknown table %q", ErrInvalid, r.line, env.Table)
+	}
+	if i < r.table {
+		return "", nil, fmt.Errorf("%w: line %d: table %s follows %s", ErrInvalid, r.line, env.Table, Tables[r.table])
+	}
+	r.table = i
+	r.rows[env.Table]++
+
+	return env.Table, env.Row, nil
+}
+
+// Rows returns the number of rows read per table.
+func (r *Reader) Rows() map[string]int {
+	return r.rows
+}
+
+// Close closes the decompressor. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.zr.Close()
+}
+
+// end checks the row counts of the trailer against the rows read.
+func (r *Reader) end(t *trailer) error {
+	for _, table := range Tables {
+		if t.Rows[table] != r.rows[table] {
+			return fmt.Errorf("%w: %d %s rows read, the snapshot has %d", ErrTruncated, r.rows[table], table, t.Rows[table])
+		}
+	}
+	r.done = true
+
+	return io.EOF
+}
+
+func (r *Reader) next() (*envelope, error) {
+	if !r.sc.Scan() {
+		if err := r.sc.Err(); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, ErrTruncated) {
+				return nil, ErrTruncated
+			}
+
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+
+		return nil, io.EOF
+	}
+	r.line++
+
+	var env envelope
+	if err := json.Unmarshal(r.sc.Bytes(), &env); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %v", ErrInvalid, r.line, err)
+	}
+
+	return &env, nil
+}
+
+// scanLines splits envelopes at newlines. Every envelope is written with
+// one, so data left without a newline at the end was cut off.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF && len(data) > 0 {
+		return 0, nil, ErrTruncated
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,204 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"item-service/internal/lib/snapshot"
+)
+
+type row struct {
+	table string
+	row   string
+}
+
+var rows = []row{
+	{"item_definitions", `{"id":"d1","name":"AK-47 | Redline"}`},
+	{"item_definitions", `{"id":"d2","name":"AWP | Asiimov"}`},
+	{"items", `{"id":"i1","definition_id":"d1"}`},
+	{"item_locks", `{"id":"l1","item_id":"i1","reason":"trade hold"}`},
+}
+
+// write returns a snapshot of rows; abort ends it without the end envelope.
+func write(t *testing.T, rows []row, abort bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := snapshot.NewWriter(&buf, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range rows {
+		if err := w.Write(r.table, json.RawMessage(r.row)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if abort {
+		err = w.Abort()
+	} else {
+		err = w.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// read returns the rows of the snapshot and the error that ended it.
+func read(t *testing.T, data []byte) ([]row, error) {
+	t.Helper()
+
+	r, err := snapshot.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var got []row
+	for {
+		table, raw, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return got, nil
+			}
+
+			return got, err
+		}
+
+		got = append(got, row{table, string(raw)})
+	}
+}
+
+// gzipLines returns the lines gzipped, as a snapshot written by hand.
+func gzipLines(t *testing.T, lines ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	for _, line := range lines {
+		if _, err := io.WriteString(zw, line+"\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := write(t, rows, false)
+
+	r, err := snapshot.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := r.Header()
+	if h.Format != snapshot.Format || h.Version != snapshot.Version || h.SchemaVersion != 16 || h.CreatedAt.IsZero() {
+		t.Errorf("header = %+v", h)
+	}
+
+	got, err := read(t, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("rows = %v, want %v", got, rows)
+	}
+}
+
+func TestWriteOrder(t *testing.T) {
+	w, err := snapshot.NewWriter(io.Discard, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Write("items", json.RawMessage(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write("item_definitions", json.RawMessage(`{}`)); err == nil {
+		t.Error("Write of a table before the one written last succeeded")
+	}
+	if err := w.Write("users", json.RawMessage(`{}`)); err == nil {
+		t.Error("Write of an unknown table succeeded")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	const header = `{"header":{"format":"item-service-snapshot","version":1,"schema_version":16}}`
+
+	complete := write(t, rows, false)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{
+			name: "not gzipped",
+			data: []byte(header),
+			want: snapshot.ErrInvalid,
+		},
+		{
+			name: "empty",
+			data: gzipLines(t),
+			want: snapshot.ErrInvalid,
+		},
+		{
+			name: "missing header",
+			data: gzipLines(t, `{"table":"items","row":{}}`),
+			want: snapshot.ErrInvalid,
+		},
+		{
+			name: "unsupported version",
+			data: gzipLines(t, `{"header":{"format":"item-service-snapshot","version":2,"schema_version":16}}`),
+			want: snapshot.ErrUnsupportedVersion,
+		},
+		{
+			name: "aborted",
+			data: write(t, rows, true),
+			want: snapshot.ErrTruncated,
+		},
+		{
+			name: "cut off",
+			data: complete[:len(complete)/2],
+			want: snapshot.ErrTruncated,
+		},
+		{
+			name: "rows missing from the end",
+			data: gzipLines(t, header, `{"end":{"rows":{"items":2}}}`),
+			want: snapshot.ErrTruncated,
+		},
+		{
+			name: "tables out of order",
+			data: gzipLines(t, header, `{"table":"items","row":{}}`, `{"table":"item_definitions","row":{}}`),
+			want: snapshot.ErrInvalid,
+		},
+		{
+			name: "unknown table",
+			data: gzipLines(t, header, `{"table":"users","row":{}}`),
+			want: snapshot.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := read(t, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type RepositoryAttribute interface {
+	SetItemAttributes(ctx context.Context, itemID uuid.UUID, set models.Attributes, remove []string) (attrs models.Attributes, err error)
+}
+
+var ErrInvalidAttributes = errors.New("invalid item attributes")
+
+// SetItemAttributes sets and removes attributes of the item and returns the
+// resulting attributes. The attributes are validated against the registry.
+func (itm *Item) SetItemAttributes(ctx context.Context, itemID uuid.UUID, set models.Attributes, remove []string) (models.Attributes, error) {
+	const op = "Item.SetItemAttributes"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.Any("remove", remove),
+	)
+
+	log.Info("attempting to set item attributes")
+
+	if err := validateAttributes(set); err != nil {
+		log.Warn("invalid item attributes", sl.Err(err))
+
+		return models.Attributes{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attrs, err := itm.repo.SetItemAttributes(ctx, itemID, set, remove)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return models.Attributes{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to set item attributes", sl.Err(err))
+
+		return models.Attributes{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item attributes successfully set")
+
+	return attrs, nil
+}
+
+func validateAttributes(attrs models.Attributes) error {
+	if err := models.DefaultAttributes.Validate(attrs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttributes, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package item
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/lib/loot"
+	"item-service/internal/storage"
+)
+
+// dropQuality is the quality of items dropped from cases and produced by
+// trade-up contracts.
+const dropQuality = "Normal"
+
+type RepositoryCases interface {
+	SaveCaseOpening(ctx context.Context, o *models.CaseOpening, quality string) (openingID uuid.UUID, err error)
+	GetCaseOpening(ctx context.Context, openingID uuid.UUID) (o *models.CaseOpening, err error)
+}
+
+var (
+	ErrNotACase            = errors.New("collection is not a case")
+	ErrEmptyDropPool       = errors.New("case has no droppable items")
+	ErrCaseOpeningNotFound = errors.New("case opening not found")
+)
+
+// OpenCase opens the case for the owner: it picks a drop from the case's
+// definitions using a fresh random seed and creates the dropped item in the
+// owner's inventory. The opening is recorded with its seed, so the drop can
+// be reproduced with loot.Open.
+func (itm *Item) OpenCase(ctx context.Context, collectionID, ownerID uuid.UUID) (*models.CaseOpening, error) {
+	const op = "Item.OpenCase"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+		slog.Any("ownerID", ownerID),
+	)
+
+	log.Info("attempting to open case")
+
+	if ownerID == uuid.Nil {
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	pool, err := itm.dropPool(ctx, log, op, collectionID)
+	if err != nil {
+		return &models.CaseOpening{}, err
+	}
+
+	seed, err := newSeed()
+	if err != nil {
+		log.Error("failed to generate seed", sl.Err(err))
+
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	drop, err := loot.Open(itm.dropTable, pool, seed)
+	if err != nil {
+		log.Warn("case has no droppable items", sl.Err(err))
+
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, ErrEmptyDropPool)
+	}
+
+	opening := &models.CaseOpening{
+		CollectionId: collectionID,
+		OwnerId:      ownerID,
+		DefinitionId: drop.Definition.DefinitionId,
+		Rarity:       drop.Definition.Rarity,
+		Float:        drop.Float,
+		PatternSeed:  drop.PatternSeed,
+		Seed:         seed,
+		DropTable:    itm.dropTable,
+		Pool:         make([]uuid.UUID, 0, len(pool)),
+	}
+	for _, def := range pool {
+		opening.Pool = append(opening.Pool, def.DefinitionId)
+	}
+
+	opening.OpeningId, err = itm.repo.SaveCaseOpening(ctx, opening, dropQuality)
+	if err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("dropped item definition was deleted", sl.Err(err))
+
+			return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to save case opening", sl.Err(err))
+
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("case successfully opened",
+		slog.Any("openingID", opening.OpeningId),
+		slog.Any("itemID", opening.ItemId),
+		slog.String("rarity", opening.Rarity),
+	)
+
+	return opening, nil
+}
+
+// GetCaseOpening returns the record of a case opening.
+func (itm *Item) GetCaseOpening(ctx context.Context, openingID uuid.UUID) (*models.CaseOpening, error) {
+	const op = "Item.GetCaseOpening"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("openingID", openingID),
+	)
+
+	log.Info("attempting to get case opening")
+
+	o, err := itm.repo.GetCaseOpening(ctx, openingID)
+	if err != nil {
+		if errors.Is(err, storage.ErrCaseOpeningNotFound) {
+			log.Warn("case opening not found", sl.Err(err))
+
+			return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, ErrCaseOpeningNotFound)
+		}
+
+		log.Error("failed to get case opening", sl.Err(err))
+
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return o, nil
+}
+
+// dropPool returns the definitions of the case.
+func (itm *Item) dropPool(ctx context.Context, log *slog.Logger, op string, collectionID uuid.UUID) ([]*models.ItemDefinition, error) {
+	c, err := itm.repo.GetCollection(ctx, collectionID)
+	if err != nil {
+		return nil, collectionError(log, op, err)
+	}
+
+	if c.Kind != models.CollectionKindCase {
+		log.Warn("collection is not a case", slog.String("kind", string(c.Kind)))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrNotACase)
+	}
+
+	pool, err := itm.repo.GetCollectionItems(ctx, collectionID)
+	if err != nil {
+		return nil, collectionError(log, op, err)
+	}
+
+	return pool, nil
+}
+
+// newSeed returns a random non-zero RNG seed.
+func newSeed() (int64, error) {
+	var b [8]byte
+
+	for {
+		if _, err := rand.Read(b[:]); err != nil {
+			return 0, err
+		}
+
+		if seed := int64(binary.LittleEndian.Uint64(b[:])); seed != 0 {
+			return seed, nil
+		}
+	}
+}
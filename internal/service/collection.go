@@ -0,0 +1,214 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type RepositoryCollection interface {
+	SaveCollection(ctx context.Context, c *models.Collection) (collectionID uuid.UUID, err error)
+	GetCollection(ctx context.Context, collectionID uuid.UUID) (c *models.Collection, err error)
+	GetAllCollections(ctx context.Context) (collections []*models.Collection, err error)
+	UpdateCollection(ctx context.Context, c *models.Collection) (err error)
+	DeleteCollection(ctx context.Context, collectionID uuid.UUID) (err error)
+	AddCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) (err error)
+	RemoveCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) (err error)
+	GetCollectionItems(ctx context.Context, collectionID uuid.UUID) (defs []*models.ItemDefinition, err error)
+	GetCollectionSiblings(ctx context.Context, definitionID uuid.UUID, rarity string) (defs []*models.ItemDefinition, err error)
+}
+
+var (
+	ErrCollectionExists   = errors.New("collection already exists")
+	ErrCollectionNotFound = errors.New("collection not found")
+)
+
+// CreateCollection creates a new, empty collection.
+func (itm *Item) CreateCollection(ctx context.Context, c *models.Collection) (uuid.UUID, error) {
+	const op = "Item.CreateCollection"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.String("name", c.Name),
+		slog.String("kind", string(c.Kind)),
+	)
+
+	log.Info("attempting to create collection")
+
+	collectionID, err := itm.repo.SaveCollection(ctx, c)
+	if err != nil {
+		return uuid.Nil, collectionError(log, op, err)
+	}
+
+	log.Info("collection successfully created")
+
+	return collectionID, nil
+}
+
+// GetCollection returns the collection with the given ID.
+func (itm *Item) GetCollection(ctx context.Context, collectionID uuid.UUID) (*models.Collection, error) {
+	const op = "Item.GetCollection"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+	)
+
+	log.Info("attempting to get collection")
+
+	c, err := itm.repo.GetCollection(ctx, collectionID)
+	if err != nil {
+		return &models.Collection{}, collectionError(log, op, err)
+	}
+
+	return c, nil
+}
+
+// GetAllCollections returns all collections.
+func (itm *Item) GetAllCollections(ctx context.Context) ([]*models.Collection, error) {
+	const op = "Item.GetAllCollections"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("attempting to get all collections")
+
+	collections, err := itm.repo.GetAllCollections(ctx)
+	if err != nil {
+		log.Error("failed to get all collections", sl.Err(err))
+
+		return []*models.Collection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collections, nil
+}
+
+// UpdateCollection replaces the name, kind and description of the collection.
+func (itm *Item) UpdateCollection(ctx context.Context, c *models.Collection) error {
+	const op = "Item.UpdateCollection"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", c.CollectionId),
+		slog.String("name", c.Name),
+		slog.String("kind", string(c.Kind)),
+	)
+
+	log.Info("attempting to update collection")
+
+	if err := itm.repo.UpdateCollection(ctx, c); err != nil {
+		return collectionError(log, op, err)
+	}
+
+	log.Info("collection successfully updated")
+
+	return nil
+}
+
+// DeleteCollection deletes the collection. Its item definitions are kept.
+func (itm *Item) DeleteCollection(ctx context.Context, collectionID uuid.UUID) error {
+	const op = "Item.DeleteCollection"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+	)
+
+	log.Info("attempting to delete collection")
+
+	if err := itm.repo.DeleteCollection(ctx, collectionID); err != nil {
+		return collectionError(log, op, err)
+	}
+
+	log.Info("collection successfully deleted")
+
+	return nil
+}
+
+// AddCollectionItems adds the item definitions to the collection.
+func (itm *Item) AddCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Item.AddCollectionItems"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+		slog.Int("definitions", len(definitionIDs)),
+	)
+
+	log.Info("attempting to add items to collection")
+
+	if err := itm.repo.AddCollectionItems(ctx, collectionID, definitionIDs); err != nil {
+		return collectionError(log, op, err)
+	}
+
+	log.Info("items successfully added to collection")
+
+	return nil
+}
+
+// RemoveCollectionItems removes the item definitions from the collection.
+func (itm *Item) RemoveCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Item.RemoveCollectionItems"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+		slog.Int("definitions", len(definitionIDs)),
+	)
+
+	log.Info("attempting to remove items from collection")
+
+	if err := itm.repo.RemoveCollectionItems(ctx, collectionID, definitionIDs); err != nil {
+		return collectionError(log, op, err)
+	}
+
+	log.Info("items successfully removed from collection")
+
+	return nil
+}
+
+// GetCollectionItems returns the item definitions of the collection.
+func (itm *Item) GetCollectionItems(ctx context.Context, collectionID uuid.UUID) ([]*models.ItemDefinition, error) {
+	const op = "Item.GetCollectionItems"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("collectionID", collectionID),
+	)
+
+	log.Info("attempting to get collection items")
+
+	defs, err := itm.repo.GetCollectionItems(ctx, collectionID)
+	if err != nil {
+		return []*models.ItemDefinition{}, collectionError(log, op, err)
+	}
+
+	return defs, nil
+}
+
+// collectionError logs err and maps the storage errors of the collection operations to service errors.
+func collectionError(log *slog.Logger, op string, err error) error {
+	for _, e := range []struct{ storage, service error }{
+		{storage.ErrCollectionExists, ErrCollectionExists},
+		{storage.ErrCollectionNotFound, ErrCollectionNotFound},
+		{storage.ErrDefinitionNotFound, ErrDefinitionNotFound},
+	} {
+		if errors.Is(err, e.storage) {
+			log.Warn(e.service.Error(), sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, e.service)
+		}
+	}
+
+	log.Error("collection operation failed", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,140 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type RepositoryDefinition interface {
+	SaveItemDefinition(ctx context.Context, def *models.ItemDefinition) (definitionID uuid.UUID, err error)
+	GetItemDefinition(ctx context.Context, definitionID uuid.UUID) (def *models.ItemDefinition, err error)
+	GetAllItemDefinitions(ctx context.Context) (defs []*models.ItemDefinition, err error)
+	DeleteItemDefinition(ctx context.Context, definitionID uuid.UUID) (err error)
+}
+
+var (
+	ErrDefinitionExists   = errors.New("item definition already exists")
+	ErrDefinitionNotFound = errors.New("item definition not found")
+	ErrDefinitionInUse    = errors.New("item definition has instances")
+)
+
+// CreateItemDefinition creates a new catalogue entry.
+func (itm *Item) CreateItemDefinition(ctx context.Context, def *models.ItemDefinition) (uuid.UUID, error) {
+	const op = "Item.CreateItemDefinition"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.String("name", def.Name),
+		slog.String("rarity", def.Rarity),
+		slog.Float64("minFloat", def.MinFloat),
+		slog.Float64("maxFloat", def.MaxFloat),
+	)
+
+	log.Info("attempting to create item definition")
+
+	definitionID, err := itm.repo.SaveItemDefinition(ctx, def)
+	if err != nil {
+		if errors.Is(err, storage.ErrDefinitionExists) {
+			log.Warn("item definition already exists", sl.Err(err))
+
+			return uuid.Nil, fmt.Errorf("%s: %w", op, ErrDefinitionExists)
+		}
+
+		log.Error("failed to create item definition", sl.Err(err))
+
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item definition successfully created")
+
+	return definitionID, nil
+}
+
+// GetItemDefinition returns the item definition with the given ID.
+func (itm *Item) GetItemDefinition(ctx context.Context, definitionID uuid.UUID) (*models.ItemDefinition, error) {
+	const op = "Item.GetItemDefinition"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+	)
+
+	log.Info("attempting to get item definition")
+
+	def, err := itm.repo.GetItemDefinition(ctx, definitionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition not found", sl.Err(err))
+
+			return &models.ItemDefinition{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to get item definition", sl.Err(err))
+
+		return &models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return def, nil
+}
+
+// GetAllItemDefinitions returns the whole catalogue.
+func (itm *Item) GetAllItemDefinitions(ctx context.Context) ([]*models.ItemDefinition, error) {
+	const op = "Item.GetAllItemDefinitions"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("attempting to get all item definitions")
+
+	defs, err := itm.repo.GetAllItemDefinitions(ctx)
+	if err != nil {
+		log.Error("failed to get all item definitions", sl.Err(err))
+
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return defs, nil
+}
+
+// DeleteItemDefinition deletes the item definition with the given ID.
+// Definitions that still have instances cannot be deleted.
+func (itm *Item) DeleteItemDefinition(ctx context.Context, definitionID uuid.UUID) error {
+	const op = "Item.DeleteItemDefinition"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+	)
+
+	log.Info("attempting to delete item definition")
+
+	if err := itm.repo.DeleteItemDefinition(ctx, definitionID); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrDefinitionNotFound):
+			log.Warn("item definition not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		case errors.Is(err, storage.ErrDefinitionInUse):
+			log.Warn("item definition has instances", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrDefinitionInUse)
+		}
+
+		log.Error("failed to delete item definition", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item definition successfully deleted")
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package item
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+)
+
+// defaultExportBatch is the number of items an export reads at a time
+// unless configured otherwise.
+const defaultExportBatch = 1000
+
+type RepositoryExport interface {
+	ExportItems(ctx context.Context, filter models.ItemFilter, batch int, fn func(item *models.Item) error) error
+}
+
+// ItemWriter is the destination of an export, such as an itemio.Writer.
+type ItemWriter interface {
+	Write(item *models.Item) error
+}
+
+// ExportItems writes the items matching the filter to w, in the order of
+// GetAllItems, and returns the number of items written. The items are
+// streamed from the storage instead of being loaded at once, so exports of
+// the whole catalogue take constant memory.
+func (itm *Item) ExportItems(ctx context.Context, filter models.ItemFilter, w ItemWriter) (int, error) {
+	const op = "Item.ExportItems"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.String("sort", string(filter.Sort)),
+	)
+
+	log.Info("attempting to export items")
+
+	if err := validateItemFilter(&filter); err != nil {
+		log.Warn("invalid item filter", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n := 0
+	err := itm.repo.ExportItems(ctx, filter, itm.exportBatch, func(item *models.Item) error {
+		if err := w.Write(item); err != nil {
+			return err
+		}
+		n++
+
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to export items", sl.Err(err), slog.Int("exported", n))
+
+		return n, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("items exported", slog.Int("exported", n))
+
+	return n, nil
+}
@@ -0,0 +1,201 @@
+package item
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/imaging"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type RepositoryImage interface {
+	SaveItemImage(ctx context.Context, img *models.ItemImage) (err error)
+	GetItemImages(ctx context.Context, definitionIDs []uuid.UUID) (images map[uuid.UUID]*models.ItemImage, err error)
+}
+
+var (
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrImageTooLarge = errors.New("image is too large")
+)
+
+// UploadItemImage stores the image read from r as the image of the
+// definition and renders its thumbnails. Objects are keyed by the SHA-256
+// of the image, so uploading an image that is already stored only links it
+// to the definition.
+func (itm *Item) UploadItemImage(ctx context.Context, definitionID uuid.UUID, r io.Reader) (*models.ItemImage, error) {
+	const op = "Item.UploadItemImage"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+	)
+
+	log.Info("attempting to upload item image")
+
+	if _, err := itm.repo.GetItemDefinition(ctx, definitionID); err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition not found", sl.Err(err))
+
+			return &models.ItemImage{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to get item definition", sl.Err(err))
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// One byte more than the limit tells whether the image exceeds it.
+	data, err := io.ReadAll(io.LimitReader(r, itm.maxImageSize+1))
+	if err != nil {
+		log.Error("failed to read image", sl.Err(err))
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if int64(len(data)) > itm.maxImageSize {
+		log.Warn("image is too large")
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: the limit is %d bytes", ErrImageTooLarge, itm.maxImageSize))
+	}
+
+	decoded, contentType, err := imaging.Decode(data)
+	if err != nil {
+		log.Warn("failed to decode image", sl.Err(err))
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: %v", ErrInvalidImage, err))
+	}
+
+	sum := sha256.Sum256(data)
+	img := &models.ItemImage{
+		DefinitionId:   definitionID,
+		Hash:           hex.EncodeToString(sum[:]),
+		ContentType:    contentType,
+		Width:          decoded.Bounds().Dx(),
+		Height:         decoded.Bounds().Dy(),
+		Size:           int64(len(data)),
+		ThumbnailSizes: itm.thumbnailSizes,
+	}
+
+	log = log.With(slog.String("hash", img.Hash))
+
+	if err := itm.putAsset(ctx, imageKey(img.Hash), contentType, func() ([]byte, error) {
+		return data, nil
+	}); err != nil {
+		log.Error("failed to store image", sl.Err(err))
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, size := range itm.thumbnailSizes {
+		if err := itm.putAsset(ctx, thumbnailKey(img.Hash, size), imaging.ThumbnailContentType, func() ([]byte, error) {
+			return imaging.Thumbnail(decoded, size)
+		}); err != nil {
+			log.Error("failed to store thumbnail", slog.Int("size", size), sl.Err(err))
+
+			return &models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := itm.repo.SaveItemImage(ctx, img); err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition was deleted", sl.Err(err))
+
+			return &models.ItemImage{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to save item image", sl.Err(err))
+
+		return &models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	img.URLs = itm.imageURLs(img)
+
+	log.Info("item image successfully uploaded")
+
+	return img, nil
+}
+
+// putAsset stores the object rendered by render under key, unless an
+// object is already stored there. Keys include the content hash, so a
+// stored object never changes.
+func (itm *Item) putAsset(ctx context.Context, key, contentType string, render func() ([]byte, error)) error {
+	exists, err := itm.assets.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	data, err := render()
+	if err != nil {
+		return err
+	}
+
+	return itm.assets.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// withImages sets the image URLs of the items whose definitions have an
+// image.
+func (itm *Item) withImages(ctx context.Context, items ...*models.Item) error {
+	ids := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if item.DefinitionId != uuid.Nil && !seen[item.DefinitionId] {
+			ids = append(ids, item.DefinitionId)
+			seen[item.DefinitionId] = true
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	images, err := itm.repo.GetItemImages(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if img, ok := images[item.DefinitionId]; ok {
+			urls := itm.imageURLs(img)
+			item.Images = &urls
+		}
+	}
+
+	return nil
+}
+
+func (itm *Item) imageURLs(img *models.ItemImage) models.ImageURLs {
+	urls := models.ImageURLs{
+		Full:       itm.assets.URL(imageKey(img.Hash)),
+		Thumbnails: make(map[int]string, len(img.ThumbnailSizes)),
+	}
+	for _, size := range img.ThumbnailSizes {
+		urls.Thumbnails[size] = itm.assets.URL(thumbnailKey(img.Hash, size))
+	}
+
+	return urls
+}
+
+// imageKey is the key of the original image with the given hash.
+func imageKey(hash string) string {
+	return "images/" + hash + "/original"
+}
+
+// thumbnailKey is the key of a thumbnail of the image with the given hash.
+func thumbnailKey(hash string, size int) string {
+	return "images/" + hash + "/" + strconv.Itoa(size) + ".png"
+}
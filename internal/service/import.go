@@ -0,0 +1,225 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/itemio"
+	"item-service/internal/lib/logger/sl"
+)
+
+const (
+	// defaultImportBatch is the number of imported items written at a time
+	// unless configured otherwise.
+	defaultImportBatch = 1000
+	// defaultMaxImportErrors is the number of row errors reported by an
+	// import unless configured otherwise.
+	defaultMaxImportErrors = 100
+)
+
+type RepositoryImport interface {
+	ImportItems(ctx context.Context, items []*models.Item, dryRun bool) (itemIDs []uuid.UUID, err error)
+}
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// ItemReader is the source of an import, such as an itemio.Reader. Read
+// returns an *itemio.RowError for rows it cannot read and io.EOF at the
+// end; Row returns the line of the row last read.
+type ItemReader interface {
+	Read() (*models.Item, error)
+	Row() int
+}
+
+// ImportItems reads items from r, validates each of them with the rules of
+// CreateItem and saves the valid ones in batches. Invalid rows are
+// reported and skipped. A dry run validates and checks everything but
+// saves nothing; its report counts the items that would be imported.
+//
+// Batches are saved as they fill up, so an error reading r stops the
+// import after the items saved so far; the report is returned either way.
+func (itm *Item) ImportItems(ctx context.Context, r ItemReader, dryRun bool) (*models.ImportReport, error) {
+	const op = "Item.ImportItems"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Bool("dryRun", dryRun),
+	)
+
+	log.Info("attempting to import items")
+
+	report := &models.ImportReport{DryRun: dryRun}
+
+	batch := make([]*models.Item, 0, itm.importBatch)
+	rows := make([]int, 0, itm.importBatch)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		ids, err := itm.repo.ImportItems(ctx, batch, dryRun)
+		if err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			if id == uuid.Nil {
+				itm.importFailed(report, rows[i], itemio.FieldFloat, ErrFloatOutOfRange.Error())
+
+				continue
+			}
+
+			report.Imported++
+		}
+
+		batch, rows = batch[:0], rows[:0]
+
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, fmt.Errorf("%s: %w", op, err)
+		}
+
+		item, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *itemio.RowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			itm.importFailed(report, rowErr.Row, rowErr.Field, rowErr.Err.Error())
+
+			continue
+		}
+
+		if err != nil {
+			log.Warn("failed to read import", sl.Err(err))
+
+			return report, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: %w", ErrInvalidImport, err))
+		}
+
+		report.Rows++
+
+		if field, err := itm.validateNewItem(item); err != nil {
+			itm.importFailed(report, r.Row(), field, err.Error())
+
+			continue
+		}
+
+		batch = append(batch, item)
+		rows = append(rows, r.Row())
+
+		if len(batch) == itm.importBatch {
+			if err := flush(); err != nil {
+				log.Error("failed to import items", sl.Err(err))
+
+				return report, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		log.Error("failed to import items", sl.Err(err))
+
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("items imported",
+		slog.Int("rows", report.Rows),
+		slog.Int("imported", report.Imported),
+		slog.Int("failed", report.Failed),
+	)
+
+	return report, nil
+}
+
+// validateNewItem checks the item with the rules of CreateItem, normalizing
+// its tags, and returns the field at fault.
+func (itm *Item) validateNewItem(item *models.Item) (string, error) {
+	if err := itm.validate.Struct(item); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if errors.As(err, &fieldErrs) && len(fieldErrs) > 0 {
+			return fieldErrs[0].Field(), errors.New(ruleMessage(fieldErrs[0]))
+		}
+
+		return "", err
+	}
+
+	if err := checkNewStatus(item.Status); err != nil {
+		return itemio.FieldStatus, err
+	}
+
+	tags, err := normalizeTags(item.Tags)
+	if err != nil {
+		return itemio.FieldTags, err
+	}
+	sort.Strings(tags)
+	item.Tags = tags
+
+	if err := validateAttributes(item.Attributes); err != nil {
+		return itemio.FieldAttributes, err
+	}
+
+	return "", nil
+}
+
+// newValidator returns a validator naming fields by their JSON names, which
+// are the field names of imports.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	return v
+}
+
+// importFailed records a failed row of the import.
+func (itm *Item) importFailed(report *models.ImportReport, row int, field, msg string) {
+	report.Failed++
+
+	if len(report.Errors) < itm.maxImportErrors {
+		report.Errors = append(report.Errors, models.ImportRowError{
+			Row:     row,
+			Field:   field,
+			Message: msg,
+		})
+	}
+}
+
+// ruleMessage describes the validation rule the field failed.
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	}
+
+	return fmt.Sprintf("fails the %s rule", fe.Tag())
+}
@@ -0,0 +1,114 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+type RepositoryInstance interface {
+	SaveItemInstance(ctx context.Context, inst *models.ItemInstance) (instanceID uuid.UUID, err error)
+	GetItemInstance(ctx context.Context, instanceID uuid.UUID) (inst *models.ItemInstance, err error)
+	GetItemInstances(ctx context.Context, definitionID uuid.UUID) (instances []*models.ItemInstance, err error)
+}
+
+// CreateItemInstance creates a new copy of the given item definition. If
+// inst.OwnerId is not uuid.Nil the copy is created in that owner's inventory.
+// The float must be within the definition's range.
+func (itm *Item) CreateItemInstance(ctx context.Context, inst *models.ItemInstance) (uuid.UUID, error) {
+	const op = "Item.CreateItemInstance"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", inst.DefinitionId),
+		slog.Any("ownerID", inst.OwnerId),
+		slog.String("quality", inst.Quality),
+		slog.Float64("float", inst.Float),
+		slog.Int("patternSeed", inst.PatternSeed),
+	)
+
+	log.Info("attempting to create item instance")
+
+	if err := validateAttributes(inst.Attributes); err != nil {
+		log.Warn("invalid item attributes", sl.Err(err))
+
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	instanceID, err := itm.repo.SaveItemInstance(ctx, inst)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrDefinitionNotFound):
+			log.Warn("item definition not found", sl.Err(err))
+
+			return uuid.Nil, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		case errors.Is(err, storage.ErrFloatOutOfRange):
+			log.Warn("item float is out of range", sl.Err(err))
+
+			return uuid.Nil, fmt.Errorf("%s: %w", op, ErrFloatOutOfRange)
+		}
+
+		log.Error("failed to create item instance", sl.Err(err))
+
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item instance successfully created")
+
+	return instanceID, nil
+}
+
+// GetItemInstance returns the item instance with the given ID.
+func (itm *Item) GetItemInstance(ctx context.Context, instanceID uuid.UUID) (*models.ItemInstance, error) {
+	const op = "Item.GetItemInstance"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("instanceID", instanceID),
+	)
+
+	log.Info("attempting to get item instance")
+
+	inst, err := itm.repo.GetItemInstance(ctx, instanceID)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item instance not found", sl.Err(err))
+
+			return &models.ItemInstance{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get item instance", sl.Err(err))
+
+		return &models.ItemInstance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inst, nil
+}
+
+// GetItemInstances returns all copies of the given item definition.
+func (itm *Item) GetItemInstances(ctx context.Context, definitionID uuid.UUID) ([]*models.ItemInstance, error) {
+	const op = "Item.GetItemInstances"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+	)
+
+	log.Info("attempting to get item instances")
+
+	instances, err := itm.repo.GetItemInstances(ctx, definitionID)
+	if err != nil {
+		log.Error("failed to get item instances", sl.Err(err))
+
+		return []*models.ItemInstance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return instances, nil
+}
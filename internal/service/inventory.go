@@ -0,0 +1,159 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+type RepositoryInventory interface {
+	GetInventory(ctx context.Context, q models.InventoryQuery) (items []*models.Item, err error)
+	TransferItem(ctx context.Context, itemID, from, to uuid.UUID, holdUntil time.Time) (transfer *models.OwnershipTransfer, err error)
+	GetOwnershipHistory(ctx context.Context, itemID uuid.UUID) (transfers []*models.OwnershipTransfer, err error)
+}
+
+var (
+	ErrNotOwner        = errors.New("item is not owned by the sender")
+	ErrInvalidTransfer = errors.New("invalid transfer")
+	ErrInvalidOwner    = errors.New("invalid owner")
+)
+
+// GetInventory returns a page of the items held by q.OwnerId and the ID to
+// pass as q.After for the next page, or uuid.Nil on the last page.
+func (itm *Item) GetInventory(ctx context.Context, q models.InventoryQuery) ([]*models.Item, uuid.UUID, error) {
+	const op = "Item.GetInventory"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("ownerID", q.OwnerId),
+	)
+
+	log.Info("attempting to get inventory")
+
+	if q.OwnerId == uuid.Nil {
+		return []*models.Item{}, uuid.Nil, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	if err := validateStatuses(q.Statuses); err != nil {
+		return []*models.Item{}, uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case q.PageSize <= 0:
+		q.PageSize = defaultPageSize
+	case q.PageSize > maxPageSize:
+		q.PageSize = maxPageSize
+	}
+
+	pageSize := q.PageSize
+	// One extra item tells whether there is a next page.
+	q.PageSize++
+
+	items, err := itm.repo.GetInventory(ctx, q)
+	if err != nil {
+		log.Error("failed to get inventory", sl.Err(err))
+
+		return []*models.Item{}, uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := itm.withImages(ctx, items...); err != nil {
+		log.Error("failed to get item images", sl.Err(err))
+
+		return []*models.Item{}, uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	next := uuid.Nil
+	if len(items) > pageSize {
+		items = items[:pageSize]
+		next = items[pageSize-1].ItemId
+	}
+
+	return items, next, nil
+}
+
+// TransferItem moves the item from one owner's inventory to another's.
+// It fails with ErrNotOwner unless the item is currently held by from, with
+// ErrItemNotTradable unless it is published and with an *ItemLockedError if
+// it is locked. The item is then locked for the purchase hold.
+func (itm *Item) TransferItem(ctx context.Context, itemID, from, to uuid.UUID) (*models.OwnershipTransfer, error) {
+	const op = "Item.TransferItem"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.Any("from", from),
+		slog.Any("to", to),
+	)
+
+	log.Info("attempting to transfer item")
+
+	if from == uuid.Nil || to == uuid.Nil || from == to {
+		return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, ErrInvalidTransfer)
+	}
+
+	if err := itm.checkUnlocked(ctx, itemID); err != nil {
+		return &models.OwnershipTransfer{}, lockCheckError(log, op, err)
+	}
+
+	transfer, err := itm.repo.TransferItem(ctx, itemID, from, to, itm.holdUntil())
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrItemNotFound):
+			log.Warn("item not found", sl.Err(err))
+
+			return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		case errors.Is(err, storage.ErrNotOwner):
+			log.Warn("item is not owned by the sender", sl.Err(err))
+
+			return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, ErrNotOwner)
+		case errors.Is(err, storage.ErrItemNotTradable):
+			log.Warn("item is not tradable", sl.Err(err))
+
+			return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, ErrItemNotTradable)
+		case errors.Is(err, storage.ErrItemLocked):
+			return &models.OwnershipTransfer{}, lockCheckError(log, op, itm.lockedError(ctx, itemID))
+		}
+
+		log.Error("failed to transfer item", sl.Err(err))
+
+		return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item successfully transferred")
+
+	return transfer, nil
+}
+
+// GetOwnershipHistory returns every change of the owner of the item, oldest first.
+func (itm *Item) GetOwnershipHistory(ctx context.Context, itemID uuid.UUID) ([]*models.OwnershipTransfer, error) {
+	const op = "Item.GetOwnershipHistory"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+	)
+
+	log.Info("attempting to get ownership history")
+
+	transfers, err := itm.repo.GetOwnershipHistory(ctx, itemID)
+	if err != nil {
+		log.Error("failed to get ownership history", sl.Err(err))
+
+		return []*models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
@@ -5,59 +5,185 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"golang.org/x/text/language"
 
 	"item-service/internal/domain/models"
+	"item-service/internal/lib/blob"
+	"item-service/internal/lib/fx"
 	"item-service/internal/lib/logger/sl"
+	"item-service/internal/lib/loot"
 	"item-service/internal/storage"
-
 )
 
 type Item struct {
-	log  *slog.Logger
-	repo RepositoryItem
+	log               *slog.Logger
+	repo              Repository
+	dropTable         loot.DropTable
+	rarityOrder       []string
+	valuationCurrency string
+	valuationWindow   time.Duration
+	rates             *fx.Rates
+	assets            blob.Store
+	thumbnailSizes    []int
+	maxImageSize      int64
+	locales           []language.Tag
+	localeMatcher     language.Matcher
+	tradeHold         time.Duration
+	transitionBatch   int
+	purchaseHold      time.Duration
+	importBatch       int
+	maxImportErrors   int
+	exportBatch       int
+	validate          *validator.Validate
+}
+
+// Options configure the Item service.
+type Options struct {
+	// DropTable weights the rarities of case drops.
+	DropTable loot.DropTable
+	// RarityOrder lists the rarities from lowest to highest for trade-ups.
+	RarityOrder []string
+	// ValuationCurrency is the currency items are valued in.
+	ValuationCurrency string
+	// ValuationWindow is how far back prices count towards a valuation.
+	ValuationWindow time.Duration
+	// Rates converts money between currencies; nil allows no conversions.
+	Rates *fx.Rates
+	// Assets stores item images and their thumbnails.
+	Assets blob.Store
+	// ThumbnailSizes are the edge lengths of the thumbnails of item images.
+	ThumbnailSizes []int
+	// MaxImageSize bounds the size of an uploaded image in bytes.
+	MaxImageSize int64
+	// Locales are the locales definitions are translated into. The first
+	// one is the default locale; at least one is required.
+	Locales []language.Tag
+	// TradeHold is how long the trade hold lock of locked items lasts unless
+	// a hold is given. Zero keeps it until it is removed or the item is
+	// published explicitly.
+	TradeHold time.Duration
+	// TransitionBatch bounds the scheduled transitions applied, the trade
+	// holds released and the expired locks deleted at a time.
+	TransitionBatch int
+	// PurchaseHold is how long items stay locked after they changed hands;
+	// zero disables the hold.
+	PurchaseHold time.Duration
+	// ImportBatch is the number of imported items written at a time.
+	ImportBatch int
+	// MaxImportErrors bounds the row errors reported by an import.
+	MaxImportErrors int
+	// ExportBatch is the number of items an export reads at a time.
+	ExportBatch int
+}
+
+// Repository is the storage used by the Item service.
+type Repository interface {
+	RepositoryItem
+	RepositoryDefinition
+	RepositoryInstance
+	RepositoryInventory
+	RepositoryTrade
+	RepositoryAttribute
+	RepositoryCollection
+	RepositoryCases
+	RepositoryTradeUp
+	RepositoryPrice
+	RepositoryImage
+	RepositoryTranslation
+	RepositoryTag
+	RepositoryStatus
+	RepositoryLock
+	RepositoryImport
+	RepositoryExport
 }
 
 type RepositoryItem interface {
-	SaveItem(ctx context.Context, name string, rarity string, quality string) (itemID uuid.UUID, err error)
+	SaveItem(ctx context.Context, item *models.Item) (itemID uuid.UUID, err error)
 	DeleteItem(ctx context.Context, itemID uuid.UUID) (err error)
-	GetAllItems(ctx context.Context) (items []*models.Item, err error)
+	GetAllItems(ctx context.Context, filter models.ItemFilter) (items []*models.Item, err error)
 	GetItem(ctx context.Context, itemID uuid.UUID) (item *models.Item, err error)
 }
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrItemNotFound       = errors.New("item not found")
+	ErrFloatOutOfRange    = errors.New("item float is outside of the definition's range")
+	ErrInvalidFilter      = errors.New("invalid item filter")
 )
 
 // New returns a new instance of the Item service.
-func New(log *slog.Logger, repo RepositoryItem) *Item {
+func New(log *slog.Logger, repo Repository, opts Options) *Item {
+	if opts.TransitionBatch <= 0 {
+		opts.TransitionBatch = defaultTransitionBatch
+	}
+	if opts.ImportBatch <= 0 {
+		opts.ImportBatch = defaultImportBatch
+	}
+	if opts.MaxImportErrors <= 0 {
+		opts.MaxImportErrors = defaultMaxImportErrors
+	}
+	if opts.ExportBatch <= 0 {
+		opts.ExportBatch = defaultExportBatch
+	}
+
 	return &Item{
-		repo: repo,
-		log:  log,
+		repo:              repo,
+		log:               log,
+		dropTable:         opts.DropTable,
+		rarityOrder:       opts.RarityOrder,
+		valuationCurrency: opts.ValuationCurrency,
+		valuationWindow:   opts.ValuationWindow,
+		rates:             opts.Rates,
+		assets:            opts.Assets,
+		thumbnailSizes:    opts.ThumbnailSizes,
+		maxImageSize:      opts.MaxImageSize,
+		locales:           opts.Locales,
+		localeMatcher:     language.NewMatcher(opts.Locales),
+		tradeHold:         opts.TradeHold,
+		transitionBatch:   opts.TransitionBatch,
+		purchaseHold:      opts.PurchaseHold,
+		importBatch:       opts.ImportBatch,
+		maxImportErrors:   opts.MaxImportErrors,
+		exportBatch:       opts.ExportBatch,
+		validate:          newValidator(),
 	}
 }
 
-// CreateItem creates a new item.
-func (itm *Item) CreateItem(ctx context.Context, name, rarity, quality string) (uuid.UUID, error) {
+// CreateItem creates a new item. It is published unless it is created as
+// a draft.
+func (itm *Item) CreateItem(ctx context.Context, item *models.Item) (uuid.UUID, error) {
 	const op = "Item.CreateItem"
 
 	log := itm.log.With(
 		slog.String("op", op),
-		slog.String("name", name),
-		slog.String("rarity", rarity),
-		slog.String("quality", quality),
+		slog.String("name", item.Name),
+		slog.String("rarity", item.Rarity),
+		slog.String("quality", item.Quality),
+		slog.Float64("float", item.Float),
 	)
 
 	log.Info("attempting to create item")
 
-	itemID, err := itm.repo.SaveItem(ctx, name, rarity, quality)
+	if err := checkNewStatus(item.Status); err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	itemID, err := itm.repo.SaveItem(ctx, item)
 	if err != nil {
 		if errors.Is(err, storage.ErrItemExists) {
 			itm.log.Warn("item already exists", sl.Err(err))
 
 			return uuid.Nil, fmt.Errorf("$s: %w", ErrInvalidCredentials)
 		}
+		if errors.Is(err, storage.ErrFloatOutOfRange) {
+			log.Warn("item float is out of range", sl.Err(err))
+
+			return uuid.Nil, fmt.Errorf("%s: %w", op, ErrFloatOutOfRange)
+		}
 
 		itm.log.Error("failed to create item", sl.Err(err))
 
@@ -85,7 +211,7 @@ func (itm *Item) GetItem(ctx context.Context, itemID uuid.UUID) (*models.Item, e
 		if errors.Is(err, storage.ErrItemNotFound) {
 			log.Warn("item not found", sl.Err(err))
 
-			return &models.Item{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+			return &models.Item{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
 		}
 
 		log.Error("failed to get item", sl.Err(err))
@@ -93,47 +219,77 @@ func (itm *Item) GetItem(ctx context.Context, itemID uuid.UUID) (*models.Item, e
 		return &models.Item{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if item.Valuation, err = itm.valuation(ctx, item.DefinitionId); err != nil {
+		log.Error("failed to value item", sl.Err(err))
+
+		return &models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := itm.withImages(ctx, item); err != nil {
+		log.Error("failed to get item image", sl.Err(err))
+
+		return &models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("item successfully got")
 
 	return item, nil
 }
 
-// GetAllItems returns all items.
-func (itm *Item) GetAllItems(ctx context.Context) ([]*models.Item, error) {
+// GetAllItems returns all items matching the filter.
+func (itm *Item) GetAllItems(ctx context.Context, filter models.ItemFilter) ([]*models.Item, error) {
 	const op = "Item.GetAllItems"
 
 	log := itm.log.With(
 		slog.String("op", op),
+		slog.String("sort", string(filter.Sort)),
 	)
 
 	log.Info("attemting to get all items")
 
-	items, err := itm.repo.GetAllItems(ctx)
+	if err := validateItemFilter(&filter); err != nil {
+		log.Warn("invalid item filter", sl.Err(err))
+
+		return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := itm.repo.GetAllItems(ctx, filter)
 	if err != nil {
 		itm.log.Info("failed to get all items", sl.Err(err))
 
 		return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := itm.withImages(ctx, items...); err != nil {
+		log.Error("failed to get item images", sl.Err(err))
+
+		return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("All items received")
 
 	return items, nil
 }
 
-// DeleteItem deletes the item with the given ID.
+// DeleteItem deletes the item with the given ID. Locked items cannot be
+// deleted.
 func (itm *Item) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
-	const op = "Item.GetAllItems"
+	const op = "Item.DeleteItem"
 
 	log := itm.log.With(
 		slog.String("op", op),
+		slog.Any("itemID", itemID),
 	)
 
-	log.Info("attemting to get all items")
+	log.Info("attemting to delete item")
 
 	if err := itm.repo.DeleteItem(ctx, itemID); err != nil {
 		if errors.Is(err, storage.ErrItemNotFound) {
 			itm.log.Warn("items not found", sl.Err(err))
-			return fmt.Errorf("%s: %w", op, err)
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrItemLocked) {
+			return lockCheckError(log, op, itm.lockedError(ctx, itemID))
 		}
 		itm.log.Info("failed to get all items", sl.Err(err))
 
@@ -144,3 +300,44 @@ func (itm *Item) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
 
 	return nil
 }
+
+// validateItemFilter checks the filter and normalizes its tags.
+func validateItemFilter(filter *models.ItemFilter) error {
+	for _, f := range []*float64{filter.MinFloat, filter.MaxFloat} {
+		if f != nil && (*f < 0 || *f > 1) {
+			return fmt.Errorf("%w: float bounds must be within [0, 1]", ErrInvalidFilter)
+		}
+	}
+
+	if filter.MinFloat != nil && filter.MaxFloat != nil && *filter.MinFloat > *filter.MaxFloat {
+		return fmt.Errorf("%w: min float is greater than max float", ErrInvalidFilter)
+	}
+
+	if err := validateAttributes(filter.Attributes); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	filter.Tags = tags
+
+	switch filter.TagMatch {
+	case "", models.TagMatchAll, models.TagMatchAny:
+	default:
+		return fmt.Errorf("%w: unknown tag match %q", ErrInvalidFilter, filter.TagMatch)
+	}
+
+	if err := validateStatuses(filter.Statuses); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	switch filter.Sort {
+	case models.ItemSortDefault, models.ItemSortFloatAsc, models.ItemSortFloatDesc:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, filter.Sort)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+// maxLockReason bounds the length of a lock reason in bytes.
+const maxLockReason = 200
+
+type RepositoryLock interface {
+	SaveItemLock(ctx context.Context, lock *models.ItemLock) (lockID uuid.UUID, err error)
+	DeleteItemLock(ctx context.Context, lockID uuid.UUID) (err error)
+	GetItemLocks(ctx context.Context, itemIDs []uuid.UUID) (locks []*models.ItemLock, err error)
+	DeleteExpiredLocks(ctx context.Context, now time.Time, limit int) (deleted int, err error)
+}
+
+var (
+	ErrItemLocked   = errors.New("item is locked")
+	ErrLockNotFound = errors.New("item lock not found")
+	ErrInvalidLock  = errors.New("invalid item lock")
+)
+
+// ItemLockedError is returned when items cannot be changed because of
+// their active locks. It matches ErrItemLocked.
+type ItemLockedError struct {
+	Locks []*models.ItemLock
+}
+
+func (e *ItemLockedError) Error() string {
+	if len(e.Locks) == 0 {
+		return ErrItemLocked.Error()
+	}
+
+	lock := e.Locks[0]
+	if lock.ExpiresAt.IsZero() {
+		return fmt.Sprintf("%s: %s", ErrItemLocked, lock.Reason)
+	}
+
+	return fmt.Sprintf("%s until %s: %s", ErrItemLocked, lock.ExpiresAt.UTC().Format(time.RFC3339), lock.Reason)
+}
+
+func (e *ItemLockedError) Unwrap() error {
+	return ErrItemLocked
+}
+
+// LockItem keeps the item from being transferred, traded or deleted for
+// ttl, or until it is unlocked if ttl is zero.
+func (itm *Item) LockItem(ctx context.Context, itemID uuid.UUID, reason string, ttl time.Duration) (*models.ItemLock, error) {
+	const op = "Item.LockItem"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.String("reason", reason),
+		slog.Duration("ttl", ttl),
+	)
+
+	log.Info("attempting to lock item")
+
+	reason = strings.TrimSpace(reason)
+
+	switch {
+	case reason == "":
+		return &models.ItemLock{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: a reason is required", ErrInvalidLock))
+	case len(reason) > maxLockReason:
+		return &models.ItemLock{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: the reason is longer than %d bytes", ErrInvalidLock, maxLockReason))
+	case ttl < 0:
+		return &models.ItemLock{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: the expiry must not be negative", ErrInvalidLock))
+	}
+
+	lock := &models.ItemLock{
+		ItemId: itemID,
+		Reason: reason,
+	}
+	if ttl > 0 {
+		lock.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	if _, err := itm.repo.SaveItemLock(ctx, lock); err != nil {
+		return &models.ItemLock{}, itemLockError(log, op, err)
+	}
+
+	log.Info("item successfully locked", slog.Any("lockID", lock.LockId))
+
+	return lock, nil
+}
+
+// UnlockItem removes the lock.
+func (itm *Item) UnlockItem(ctx context.Context, lockID uuid.UUID) error {
+	const op = "Item.UnlockItem"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("lockID", lockID),
+	)
+
+	log.Info("attempting to unlock item")
+
+	if err := itm.repo.DeleteItemLock(ctx, lockID); err != nil {
+		return itemLockError(log, op, err)
+	}
+
+	log.Info("item successfully unlocked")
+
+	return nil
+}
+
+// GetItemLocks returns the active locks of the item, oldest first.
+func (itm *Item) GetItemLocks(ctx context.Context, itemID uuid.UUID) ([]*models.ItemLock, error) {
+	const op = "Item.GetItemLocks"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+	)
+
+	log.Info("attempting to get item locks")
+
+	if _, err := itm.repo.GetItem(ctx, itemID); err != nil {
+		return []*models.ItemLock{}, itemLockError(log, op, err)
+	}
+
+	locks, err := itm.repo.GetItemLocks(ctx, []uuid.UUID{itemID})
+	if err != nil {
+		log.Error("failed to get item locks", sl.Err(err))
+
+		return []*models.ItemLock{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return locks, nil
+}
+
+// DeleteExpiredLocks removes a batch of expired locks and returns how many
+// were removed. Expired locks have no effect; this only keeps them from
+// piling up.
+func (itm *Item) DeleteExpiredLocks(ctx context.Context) (int, error) {
+	const op = "Item.DeleteExpiredLocks"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	deleted, err := itm.repo.DeleteExpiredLocks(ctx, time.Now(), itm.transitionBatch)
+	if err != nil {
+		log.Error("failed to delete expired locks", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if deleted > 0 {
+		log.Info("expired locks deleted", slog.Int("deleted", deleted))
+	}
+
+	return deleted, nil
+}
+
+// checkUnlocked returns an *ItemLockedError if any of the items has an
+// active lock.
+func (itm *Item) checkUnlocked(ctx context.Context, itemIDs ...uuid.UUID) error {
+	locks, err := itm.repo.GetItemLocks(ctx, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	if len(locks) > 0 {
+		return &ItemLockedError{Locks: locks}
+	}
+
+	return nil
+}
+
+// lockedError returns the error of items the storage found locked.
+func (itm *Item) lockedError(ctx context.Context, itemIDs ...uuid.UUID) error {
+	if err := itm.checkUnlocked(ctx, itemIDs...); err != nil {
+		return err
+	}
+
+	// The locks expired in the meantime.
+	return &ItemLockedError{}
+}
+
+// holdUntil returns the end of the trade hold of items received now, or
+// the zero time if there is none.
+func (itm *Item) holdUntil() time.Time {
+	if itm.purchaseHold == 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(itm.purchaseHold)
+}
+
+// lockCheckError wraps the result of checkUnlocked.
+func lockCheckError(log *slog.Logger, op string, err error) error {
+	if errors.Is(err, ErrItemLocked) {
+		log.Warn("item is locked", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Error("failed to check item locks", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func itemLockError(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrItemNotFound):
+		log.Warn("item not found", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+	case errors.Is(err, storage.ErrLockNotFound):
+		log.Warn("item lock not found", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrLockNotFound)
+	}
+
+	log.Error("failed to update item locks", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
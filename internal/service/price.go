@@ -0,0 +1,209 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/fx"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+// maxPriceBatch bounds the observations of a single RecordPrices call.
+const maxPriceBatch = 10_000
+
+type RepositoryPrice interface {
+	SavePriceObservations(ctx context.Context, obs []models.PriceObservation) (err error)
+	GetPriceHistory(ctx context.Context, pq models.PriceHistoryQuery) (candles []*models.PriceCandle, err error)
+	GetValuationPrices(ctx context.Context, definitionID uuid.UUID, since time.Time) (prices []models.PriceAggregate, err error)
+}
+
+var (
+	ErrInvalidPrices       = errors.New("invalid price observations")
+	ErrInvalidPriceHistory = errors.New("invalid price history query")
+	ErrNoExchangeRate      = errors.New("no exchange rate")
+)
+
+// RecordPrices stores a batch of price observations and returns how many
+// were recorded. Recording an observation again replaces it.
+func (itm *Item) RecordPrices(ctx context.Context, obs []models.PriceObservation) (int, error) {
+	const op = "Item.RecordPrices"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Int("observations", len(obs)),
+	)
+
+	log.Info("attempting to record prices")
+
+	if err := validatePriceBatch(obs); err != nil {
+		log.Warn("invalid price observations", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := itm.repo.SavePriceObservations(ctx, obs); err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to record prices", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("prices successfully recorded")
+
+	return len(obs), nil
+}
+
+// GetPriceHistory returns the OHLC candles of a definition's prices. An
+// empty currency uses the valuation currency.
+func (itm *Item) GetPriceHistory(ctx context.Context, pq models.PriceHistoryQuery) ([]*models.PriceCandle, error) {
+	const op = "Item.GetPriceHistory"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", pq.DefinitionId),
+		slog.String("interval", string(pq.Interval)),
+	)
+
+	log.Info("attempting to get price history")
+
+	if pq.Currency == "" {
+		pq.Currency = itm.valuationCurrency
+	}
+
+	if err := validatePriceHistoryQuery(pq); err != nil {
+		log.Warn("invalid price history query", sl.Err(err))
+
+		return []*models.PriceCandle{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	candles, err := itm.repo.GetPriceHistory(ctx, pq)
+	if err != nil {
+		log.Error("failed to get price history", sl.Err(err))
+
+		return []*models.PriceCandle{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candles, nil
+}
+
+// ConvertMoney converts m into the currency using the exchange-rate table.
+func (itm *Item) ConvertMoney(m models.Money, currency string) (models.Money, error) {
+	const op = "Item.ConvertMoney"
+
+	converted, err := itm.rates.Convert(m, currency)
+	if err != nil {
+		if errors.Is(err, fx.ErrNoRate) {
+			return models.Money{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w from %s to %s", ErrNoExchangeRate, m.Currency, currency))
+		}
+
+		return models.Money{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return converted, nil
+}
+
+// valuation returns the current valuation of the definition, or nil if it
+// has no prices that convert into the valuation currency. Prices in other
+// currencies are converted with the exchange-rate table and weighted like
+// the rest; currencies without a rate are left out.
+func (itm *Item) valuation(ctx context.Context, definitionID uuid.UUID) (*models.Valuation, error) {
+	prices, err := itm.repo.GetValuationPrices(ctx, definitionID, time.Now().Add(-itm.valuationWindow))
+	if err != nil {
+		if errors.Is(err, storage.ErrPriceNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	v := models.Valuation{Price: models.Money{Currency: itm.valuationCurrency}}
+
+	weighted, weight := new(big.Int), new(big.Int)
+	for _, p := range prices {
+		price, err := itm.rates.Convert(p.Price, itm.valuationCurrency)
+		if err != nil {
+			if errors.Is(err, fx.ErrNoRate) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		weighted.Add(weighted, new(big.Int).Mul(big.NewInt(price.Amount), big.NewInt(p.Weight)))
+		weight.Add(weight, big.NewInt(p.Weight))
+		v.Volume += p.Volume
+		v.Observations += p.Observations
+		if p.ObservedAt.After(v.ObservedAt) {
+			v.ObservedAt = p.ObservedAt
+		}
+	}
+
+	if weight.Sign() == 0 {
+		return nil, nil
+	}
+
+	// Rounds half away from zero like fx.
+	q, r := new(big.Int).QuoRem(weighted, weight, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(weight) >= 0 {
+		q.Add(q, big.NewInt(int64(weighted.Sign())))
+	}
+	v.Price.Amount = q.Int64()
+
+	return &v, nil
+}
+
+func validatePriceBatch(obs []models.PriceObservation) error {
+	if len(obs) == 0 || len(obs) > maxPriceBatch {
+		return fmt.Errorf("%w: between 1 and %d observations are required, got %d", ErrInvalidPrices, maxPriceBatch, len(obs))
+	}
+
+	type key struct {
+		definitionID     uuid.UUID
+		currency, source string
+		observedAt       int64
+	}
+
+	seen := make(map[key]bool, len(obs))
+	for _, o := range obs {
+		if o.Price.Amount < 0 {
+			return fmt.Errorf("%w: price of %s from %q must not be negative", ErrInvalidPrices, o.DefinitionId, o.Source)
+		}
+
+		k := key{o.DefinitionId, o.Price.Currency, o.Source, o.ObservedAt.UnixNano()}
+		if seen[k] {
+			return fmt.Errorf("%w: observation of %s from %q at %s is listed twice", ErrInvalidPrices, o.DefinitionId, o.Source, o.ObservedAt)
+		}
+		seen[k] = true
+	}
+
+	return nil
+}
+
+func validatePriceHistoryQuery(pq models.PriceHistoryQuery) error {
+	if pq.DefinitionId == uuid.Nil {
+		return fmt.Errorf("%w: definition ID is required", ErrInvalidPriceHistory)
+	}
+
+	if pq.Interval != models.PriceIntervalHour && pq.Interval != models.PriceIntervalDay {
+		return fmt.Errorf("%w: interval must be hour or day, got %q", ErrInvalidPriceHistory, pq.Interval)
+	}
+
+	if !pq.From.IsZero() && !pq.To.IsZero() && !pq.From.Before(pq.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidPriceHistory)
+	}
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+// defaultTransitionBatch is the number of due transitions applied at a time
+// unless configured otherwise.
+const defaultTransitionBatch = 500
+
+type RepositoryStatus interface {
+	TransitionItem(ctx context.Context, itemID uuid.UUID, from, to models.ItemStatus, hold *models.ItemLock) (err error)
+	ScheduleTransition(ctx context.Context, t *models.Transition) (err error)
+	GetScheduledTransition(ctx context.Context, itemID uuid.UUID) (t *models.Transition, err error)
+	DeleteScheduledTransition(ctx context.Context, itemID uuid.UUID) (err error)
+	DueTransitions(ctx context.Context, now time.Time, limit int) (transitions []*models.Transition, err error)
+	ReleaseTradeHolds(ctx context.Context, now time.Time, limit int) (released int, err error)
+}
+
+var (
+	ErrInvalidStatus      = errors.New("invalid item status")
+	ErrIllegalTransition  = errors.New("illegal item status transition")
+	ErrStatusConflict     = errors.New("item status was changed concurrently")
+	ErrTransitionNotFound = errors.New("scheduled transition not found")
+	ErrInvalidTransition  = errors.New("invalid item status transition")
+	ErrItemNotTradable    = errors.New("item is not published")
+)
+
+// itemTransitions lists the statuses each status may change to. Retired
+// items stay retired.
+var itemTransitions = map[models.ItemStatus][]models.ItemStatus{
+	models.ItemStatusDraft:     {models.ItemStatusPublished, models.ItemStatusRetired},
+	models.ItemStatusPublished: {models.ItemStatusLocked, models.ItemStatusRetired},
+	models.ItemStatusLocked:    {models.ItemStatusPublished, models.ItemStatusRetired},
+	models.ItemStatusRetired:   {},
+}
+
+// TransitionItem changes the status of the item to to. If at is in the
+// future the transition is scheduled for then instead; it is checked again
+// when it is applied. Locking an item puts it on a trade hold: a trade
+// hold lock expiring after hold, or after the configured trade hold if hold
+// is zero. The item is published again once no trade hold lock is left.
+func (itm *Item) TransitionItem(ctx context.Context, itemID uuid.UUID, to models.ItemStatus, at time.Time, hold time.Duration) (*models.ItemLifecycle, error) {
+	const op = "Item.TransitionItem"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.String("to", string(to)),
+	)
+
+	log.Info("attempting to transition item")
+
+	if !validStatus(to) {
+		return &models.ItemLifecycle{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: %q", ErrInvalidStatus, to))
+	}
+
+	if hold < 0 {
+		return &models.ItemLifecycle{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: hold must not be negative", ErrInvalidTransition))
+	}
+
+	item, err := itm.repo.GetItem(ctx, itemID)
+	if err != nil {
+		return &models.ItemLifecycle{}, itemStatusError(log, op, err)
+	}
+
+	if err := checkTransition(item.Status, to); err != nil {
+		log.Warn("illegal transition", sl.Err(err))
+
+		return &models.ItemLifecycle{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	if at.After(now) {
+		if hold != 0 {
+			return &models.ItemLifecycle{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: a hold applies to immediate locks only", ErrInvalidTransition))
+		}
+
+		t := &models.Transition{ItemId: itemID, To: to, DueAt: at}
+		if err := itm.repo.ScheduleTransition(ctx, t); err != nil {
+			return &models.ItemLifecycle{}, itemStatusError(log, op, err)
+		}
+
+		log.Info("transition successfully scheduled", slog.Time("dueAt", at))
+
+		return &models.ItemLifecycle{ItemId: itemID, Status: item.Status, Scheduled: t}, nil
+	}
+
+	if err := itm.repo.TransitionItem(ctx, itemID, item.Status, to, itm.tradeHoldLock(itemID, to, hold, now)); err != nil {
+		return &models.ItemLifecycle{}, itemStatusError(log, op, err)
+	}
+
+	log.Info("item successfully transitioned", slog.String("from", string(item.Status)))
+
+	return &models.ItemLifecycle{ItemId: itemID, Status: to}, nil
+}
+
+// GetItemLifecycle returns the status of the item and its scheduled
+// transition, if any.
+func (itm *Item) GetItemLifecycle(ctx context.Context, itemID uuid.UUID) (*models.ItemLifecycle, error) {
+	const op = "Item.GetItemLifecycle"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+	)
+
+	log.Info("attempting to get item lifecycle")
+
+	item, err := itm.repo.GetItem(ctx, itemID)
+	if err != nil {
+		return &models.ItemLifecycle{}, itemStatusError(log, op, err)
+	}
+
+	lifecycle := &models.ItemLifecycle{ItemId: itemID, Status: item.Status}
+
+	t, err := itm.repo.GetScheduledTransition(ctx, itemID)
+	switch {
+	case err == nil:
+		lifecycle.Scheduled = t
+	case !errors.Is(err, storage.ErrTransitionNotFound):
+		log.Error("failed to get scheduled transition", sl.Err(err))
+
+		return &models.ItemLifecycle{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lifecycle, nil
+}
+
+// CancelItemTransition drops the scheduled transition of the item.
+func (itm *Item) CancelItemTransition(ctx context.Context, itemID uuid.UUID) error {
+	const op = "Item.CancelItemTransition"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+	)
+
+	log.Info("attempting to cancel scheduled transition")
+
+	if err := itm.repo.DeleteScheduledTransition(ctx, itemID); err != nil {
+		return itemStatusError(log, op, err)
+	}
+
+	log.Info("scheduled transition successfully cancelled")
+
+	return nil
+}
+
+// ApplyDueTransitions applies a batch of the scheduled transitions that are
+// due and returns how many were applied. Transitions that are no longer
+// legal for the current status of their item are dropped.
+func (itm *Item) ApplyDueTransitions(ctx context.Context) (int, error) {
+	const op = "Item.ApplyDueTransitions"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	now := time.Now()
+
+	due, err := itm.repo.DueTransitions(ctx, now, itm.transitionBatch)
+	if err != nil {
+		log.Error("failed to get due transitions", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	applied := 0
+	for _, t := range due {
+		tlog := log.With(
+			slog.Any("itemID", t.ItemId),
+			slog.String("to", string(t.To)),
+		)
+
+		item, err := itm.repo.GetItem(ctx, t.ItemId)
+		if err != nil {
+			if errors.Is(err, storage.ErrItemNotFound) {
+				continue
+			}
+
+			tlog.Error("failed to get item", sl.Err(err))
+
+			return applied, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := checkTransition(item.Status, t.To); err != nil {
+			tlog.Warn("dropping illegal scheduled transition", sl.Err(err))
+
+			if err := itm.repo.DeleteScheduledTransition(ctx, t.ItemId); err != nil && !errors.Is(err, storage.ErrTransitionNotFound) {
+				tlog.Error("failed to drop scheduled transition", sl.Err(err))
+
+				return applied, fmt.Errorf("%s: %w", op, err)
+			}
+
+			continue
+		}
+
+		if err := itm.repo.TransitionItem(ctx, t.ItemId, item.Status, t.To, itm.tradeHoldLock(t.ItemId, t.To, 0, now)); err != nil {
+			if errors.Is(err, storage.ErrItemNotFound) || errors.Is(err, storage.ErrStatusChanged) {
+				// The next run sees the current status.
+				continue
+			}
+
+			tlog.Error("failed to apply scheduled transition", sl.Err(err))
+
+			return applied, fmt.Errorf("%s: %w", op, err)
+		}
+
+		applied++
+	}
+
+	if applied > 0 {
+		log.Info("scheduled transitions applied", slog.Int("applied", applied))
+	}
+
+	return applied, nil
+}
+
+// tradeHoldLock returns the trade hold lock of an item, if to locks it. It
+// never expires if both hold and the configured trade hold are zero.
+func (itm *Item) tradeHoldLock(itemID uuid.UUID, to models.ItemStatus, hold time.Duration, now time.Time) *models.ItemLock {
+	if to != models.ItemStatusLocked {
+		return nil
+	}
+
+	if hold == 0 {
+		hold = itm.tradeHold
+	}
+
+	lock := &models.ItemLock{
+		ItemId: itemID,
+		Reason: models.LockReasonTradeHold,
+	}
+	if hold > 0 {
+		lock.ExpiresAt = now.Add(hold)
+	}
+
+	return lock
+}
+
+// ReleaseTradeHolds publishes a batch of the locked items that have no
+// active trade hold lock left and returns how many were published.
+func (itm *Item) ReleaseTradeHolds(ctx context.Context) (int, error) {
+	const op = "Item.ReleaseTradeHolds"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	released, err := itm.repo.ReleaseTradeHolds(ctx, time.Now(), itm.transitionBatch)
+	if err != nil {
+		log.Error("failed to release trade holds", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if released > 0 {
+		log.Info("trade holds released", slog.Int("released", released))
+	}
+
+	return released, nil
+}
+
+// checkTransition fails with ErrIllegalTransition unless an item may change
+// from from to to.
+func checkTransition(from, to models.ItemStatus) error {
+	for _, allowed := range itemTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: from %s to %s", ErrIllegalTransition, from, to)
+}
+
+// checkNewStatus checks the status of a new item, which is created as a
+// draft or published.
+func checkNewStatus(status models.ItemStatus) error {
+	switch status {
+	case "", models.ItemStatusDraft, models.ItemStatusPublished:
+		return nil
+	}
+
+	return fmt.Errorf("%w: items are created as draft or published", ErrInvalidStatus)
+}
+
+func validStatus(status models.ItemStatus) bool {
+	_, ok := itemTransitions[status]
+
+	return ok
+}
+
+// validateStatuses checks the statuses of a listing filter.
+func validateStatuses(statuses []models.ItemStatus) error {
+	for _, status := range statuses {
+		if !validStatus(status) {
+			return fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+		}
+	}
+
+	return nil
+}
+
+func itemStatusError(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrItemNotFound):
+		log.Warn("item not found", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+	case errors.Is(err, storage.ErrStatusChanged):
+		log.Warn("item status changed concurrently", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrStatusConflict)
+	case errors.Is(err, storage.ErrTransitionNotFound):
+		log.Warn("scheduled transition not found", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrTransitionNotFound)
+	}
+
+	log.Error("failed to update item status", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,177 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+// maxTagFacets bounds the tags counted by GetFacets.
+const maxTagFacets = 100
+
+type RepositoryTag interface {
+	AddItemTags(ctx context.Context, itemID uuid.UUID, tags []string) (saved []string, err error)
+	RemoveItemTags(ctx context.Context, itemID uuid.UUID, tags []string) (saved []string, err error)
+	GetFacets(ctx context.Context, filter models.ItemFilter, tagLimit int) (facets *models.Facets, err error)
+}
+
+var (
+	ErrInvalidTags = errors.New("invalid tags")
+	ErrTooManyTags = errors.New("item has too many tags")
+)
+
+// tagPattern matches a tag: up to 50 lower-case letters of any script,
+// digits, '.', '_' and '-', starting with a letter or digit.
+var tagPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}][\p{Ll}\p{Lo}\p{N}._-]{0,49}$`)
+
+// AddTags adds the tags to the item and returns all of its tags. Tags are
+// case-insensitive and stored in lower case.
+func (itm *Item) AddTags(ctx context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Item.AddTags"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.Any("tags", tags),
+	)
+
+	log.Info("attempting to add tags")
+
+	tags, err := requiredTags(tags)
+	if err != nil {
+		log.Warn("invalid tags", sl.Err(err))
+
+		return []string{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved, err := itm.repo.AddItemTags(ctx, itemID, tags)
+	if err != nil {
+		if errors.Is(err, storage.ErrTooManyTags) {
+			log.Warn("item has too many tags", sl.Err(err))
+
+			return []string{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: the limit is %d", ErrTooManyTags, models.MaxItemTags))
+		}
+
+		return []string{}, itemTagsError(log, op, err)
+	}
+
+	log.Info("tags successfully added")
+
+	return saved, nil
+}
+
+// RemoveTags removes the tags from the item and returns its remaining tags.
+func (itm *Item) RemoveTags(ctx context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Item.RemoveTags"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("itemID", itemID),
+		slog.Any("tags", tags),
+	)
+
+	log.Info("attempting to remove tags")
+
+	tags, err := requiredTags(tags)
+	if err != nil {
+		log.Warn("invalid tags", sl.Err(err))
+
+		return []string{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved, err := itm.repo.RemoveItemTags(ctx, itemID, tags)
+	if err != nil {
+		return []string{}, itemTagsError(log, op, err)
+	}
+
+	log.Info("tags successfully removed")
+
+	return saved, nil
+}
+
+// GetFacets counts the items matching the filter by rarity, quality and
+// tag, most frequent first. Only the most frequent tags are counted.
+func (itm *Item) GetFacets(ctx context.Context, filter models.ItemFilter) (*models.Facets, error) {
+	const op = "Item.GetFacets"
+
+	log := itm.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("attempting to get facets")
+
+	if err := validateItemFilter(&filter); err != nil {
+		log.Warn("invalid item filter", sl.Err(err))
+
+		return &models.Facets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	facets, err := itm.repo.GetFacets(ctx, filter, maxTagFacets)
+	if err != nil {
+		log.Error("failed to get facets", sl.Err(err))
+
+		return &models.Facets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return facets, nil
+}
+
+// normalizeTags lower-cases and deduplicates the tags and checks that they
+// are well-formed.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > models.MaxItemTags {
+		return nil, fmt.Errorf("at most %d tags are allowed, got %d", models.MaxItemTags, len(tags))
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("tag %q must be 1 to 50 letters, digits, '.', '_' or '-', starting with a letter or digit", tag)
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
+
+// requiredTags normalizes the tags of a request, which must name at least one.
+func requiredTags(tags []string) ([]string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTags, err)
+	}
+
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one tag is required", ErrInvalidTags)
+	}
+
+	return normalized, nil
+}
+
+// itemTagsError maps storage errors of the tag operations to service errors.
+func itemTagsError(log *slog.Logger, op string, err error) error {
+	if errors.Is(err, storage.ErrItemNotFound) {
+		log.Warn("item not found", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+	}
+
+	log.Error("failed to update tags", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,289 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+const (
+	defaultTradeTTL  = 7 * 24 * time.Hour
+	maxTradeTTL      = 30 * 24 * time.Hour
+	maxItemsPerTrade = 64
+)
+
+type RepositoryTrade interface {
+	SaveTradeOffer(ctx context.Context, offer *models.TradeOffer) (tradeID uuid.UUID, err error)
+	AcceptTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID, holdUntil time.Time) (offer *models.TradeOffer, err error)
+	DeclineTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (offer *models.TradeOffer, err error)
+	CancelTradeOffer(ctx context.Context, tradeID, proposerID uuid.UUID) (offer *models.TradeOffer, err error)
+	GetTradeOffer(ctx context.Context, tradeID uuid.UUID) (offer *models.TradeOffer, err error)
+	GetTradeOffers(ctx context.Context, q models.TradeQuery) (offers []*models.TradeOffer, err error)
+}
+
+var (
+	ErrInvalidTrade          = errors.New("invalid trade offer")
+	ErrTradeNotFound         = errors.New("trade offer not found")
+	ErrTradeNotPending       = errors.New("trade offer is not pending")
+	ErrTradeExpired          = errors.New("trade offer has expired")
+	ErrNotTradeParticipant   = errors.New("not a participant of the trade offer")
+	ErrTradeItemsUnavailable = errors.New("trade offer items are no longer owned by the expected party or not tradable")
+)
+
+// CreateTradeOffer offers the proposer's items in exchange for the
+// recipient's items. Every item must be held by the party giving it and
+// must not be locked.
+// A zero ttl uses the default expiry of seven days.
+func (itm *Item) CreateTradeOffer(
+	ctx context.Context,
+	proposerID, recipientID uuid.UUID,
+	proposerItems, recipientItems []uuid.UUID,
+	ttl time.Duration,
+) (*models.TradeOffer, error) {
+	const op = "Item.CreateTradeOffer"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("proposerID", proposerID),
+		slog.Any("recipientID", recipientID),
+	)
+
+	log.Info("attempting to create trade offer")
+
+	if err := validateTradeOffer(proposerID, recipientID, proposerItems, recipientItems, ttl); err != nil {
+		log.Warn("invalid trade offer", sl.Err(err))
+
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if ttl == 0 {
+		ttl = defaultTradeTTL
+	}
+
+	if err := itm.checkUnlocked(ctx, append(append([]uuid.UUID{}, proposerItems...), recipientItems...)...); err != nil {
+		return &models.TradeOffer{}, lockCheckError(log, op, err)
+	}
+
+	now := time.Now()
+	offer := &models.TradeOffer{
+		ProposerId:     proposerID,
+		RecipientId:    recipientID,
+		ProposerItems:  proposerItems,
+		RecipientItems: recipientItems,
+		Status:         models.TradePending,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+
+	tradeID, err := itm.repo.SaveTradeOffer(ctx, offer)
+	if err != nil {
+		if errors.Is(err, storage.ErrTradeItemsUnavailable) {
+			log.Warn("trade offer items are not owned by the parties", sl.Err(err))
+
+			return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, ErrTradeItemsUnavailable)
+		}
+
+		log.Error("failed to create trade offer", sl.Err(err))
+
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	offer.TradeId = tradeID
+
+	log.Info("trade offer successfully created", slog.Any("tradeID", tradeID))
+
+	return offer, nil
+}
+
+// AcceptTradeOffer swaps the items of the offer atomically. Only the recipient
+// can accept, and only while none of the items is locked. The swapped items
+// are then locked for the purchase hold.
+func (itm *Item) AcceptTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Item.AcceptTradeOffer"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("tradeID", tradeID),
+		slog.Any("recipientID", recipientID),
+	)
+
+	log.Info("attempting to accept trade offer")
+
+	offer, err := itm.repo.GetTradeOffer(ctx, tradeID)
+	if err != nil {
+		return &models.TradeOffer{}, tradeError(log, op, err)
+	}
+
+	if err := itm.checkUnlocked(ctx, append(append([]uuid.UUID{}, offer.ProposerItems...), offer.RecipientItems...)...); err != nil {
+		return &models.TradeOffer{}, lockCheckError(log, op, err)
+	}
+
+	offer, err = itm.repo.AcceptTradeOffer(ctx, tradeID, recipientID, itm.holdUntil())
+	if err != nil {
+		return &models.TradeOffer{}, tradeError(log, op, err)
+	}
+
+	log.Info("trade offer successfully accepted")
+
+	return offer, nil
+}
+
+// DeclineTradeOffer declines the offer. Only the recipient can decline.
+func (itm *Item) DeclineTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Item.DeclineTradeOffer"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("tradeID", tradeID),
+		slog.Any("recipientID", recipientID),
+	)
+
+	log.Info("attempting to decline trade offer")
+
+	offer, err := itm.repo.DeclineTradeOffer(ctx, tradeID, recipientID)
+	if err != nil {
+		return &models.TradeOffer{}, tradeError(log, op, err)
+	}
+
+	log.Info("trade offer successfully declined")
+
+	return offer, nil
+}
+
+// CancelTradeOffer withdraws the offer. Only the proposer can cancel.
+func (itm *Item) CancelTradeOffer(ctx context.Context, tradeID, proposerID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Item.CancelTradeOffer"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("tradeID", tradeID),
+		slog.Any("proposerID", proposerID),
+	)
+
+	log.Info("attempting to cancel trade offer")
+
+	offer, err := itm.repo.CancelTradeOffer(ctx, tradeID, proposerID)
+	if err != nil {
+		return &models.TradeOffer{}, tradeError(log, op, err)
+	}
+
+	log.Info("trade offer successfully cancelled")
+
+	return offer, nil
+}
+
+// GetTradeOffer returns the trade offer with the given ID.
+func (itm *Item) GetTradeOffer(ctx context.Context, tradeID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Item.GetTradeOffer"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("tradeID", tradeID),
+	)
+
+	log.Info("attempting to get trade offer")
+
+	offer, err := itm.repo.GetTradeOffer(ctx, tradeID)
+	if err != nil {
+		return &models.TradeOffer{}, tradeError(log, op, err)
+	}
+
+	return offer, nil
+}
+
+// GetTradeOffers returns the trade history of an owner, newest first.
+func (itm *Item) GetTradeOffers(ctx context.Context, q models.TradeQuery) ([]*models.TradeOffer, error) {
+	const op = "Item.GetTradeOffers"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("ownerID", q.OwnerId),
+		slog.String("status", string(q.Status)),
+	)
+
+	log.Info("attempting to get trade offers")
+
+	if q.OwnerId == uuid.Nil {
+		return []*models.TradeOffer{}, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	switch q.Status {
+	case "", models.TradePending, models.TradeAccepted, models.TradeDeclined, models.TradeCancelled, models.TradeExpired:
+	default:
+		return []*models.TradeOffer{}, fmt.Errorf("%s: %w: unknown status %q", op, ErrInvalidTrade, q.Status)
+	}
+
+	offers, err := itm.repo.GetTradeOffers(ctx, q)
+	if err != nil {
+		log.Error("failed to get trade offers", sl.Err(err))
+
+		return []*models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return offers, nil
+}
+
+// tradeError logs err and translates storage errors into service errors.
+func tradeError(log *slog.Logger, op string, err error) error {
+	for _, e := range []struct{ storage, service error }{
+		{storage.ErrTradeNotFound, ErrTradeNotFound},
+		{storage.ErrTradeNotPending, ErrTradeNotPending},
+		{storage.ErrTradeExpired, ErrTradeExpired},
+		{storage.ErrNotTradeParticipant, ErrNotTradeParticipant},
+		{storage.ErrTradeItemsUnavailable, ErrTradeItemsUnavailable},
+	} {
+		if errors.Is(err, e.storage) {
+			log.Warn(e.service.Error(), sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, e.service)
+		}
+	}
+
+	log.Error("trade offer operation failed", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func validateTradeOffer(proposerID, recipientID uuid.UUID, proposerItems, recipientItems []uuid.UUID, ttl time.Duration) error {
+	if proposerID == uuid.Nil || recipientID == uuid.Nil {
+		return fmt.Errorf("%w: proposer and recipient are required", ErrInvalidTrade)
+	}
+
+	if proposerID == recipientID {
+		return fmt.Errorf("%w: cannot trade with yourself", ErrInvalidTrade)
+	}
+
+	n := len(proposerItems) + len(recipientItems)
+	if n == 0 {
+		return fmt.Errorf("%w: no items", ErrInvalidTrade)
+	}
+
+	if n > maxItemsPerTrade {
+		return fmt.Errorf("%w: more than %d items", ErrInvalidTrade, maxItemsPerTrade)
+	}
+
+	seen := make(map[uuid.UUID]struct{}, n)
+	for _, items := range [][]uuid.UUID{proposerItems, recipientItems} {
+		for _, id := range items {
+			if _, ok := seen[id]; ok {
+				return fmt.Errorf("%w: item %s is listed twice", ErrInvalidTrade, id)
+			}
+
+			seen[id] = struct{}{}
+		}
+	}
+
+	if ttl < 0 || ttl > maxTradeTTL {
+		return fmt.Errorf("%w: expiry must be between 0 and %s", ErrInvalidTrade, maxTradeTTL)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/lib/loot"
+	"item-service/internal/storage"
+)
+
+// tradeUpInputs is the number of items a trade-up contract consumes.
+const tradeUpInputs = 10
+
+type RepositoryTradeUp interface {
+	SaveTradeUp(ctx context.Context, tu *models.TradeUp, quality string) (tradeUpID uuid.UUID, err error)
+	GetTradeUp(ctx context.Context, tradeUpID uuid.UUID) (tu *models.TradeUp, err error)
+}
+
+var (
+	ErrInvalidTradeUp           = errors.New("invalid trade-up")
+	ErrNoTradeUpOutcome         = errors.New("no items of the next rarity in the collections of the inputs")
+	ErrTradeUpInputsUnavailable = errors.New("trade-up inputs are no longer held by the owner")
+	ErrTradeUpNotFound          = errors.New("trade-up not found")
+)
+
+// TradeUp consumes ten items of one rarity held by the owner and creates an
+// item of the next rarity in the owner's inventory. The output is picked
+// from the collections of the inputs with loot.TradeUp using a fresh random
+// seed; its float is the average input float mapped into the float range
+// of the output definition. Inputs and output are recorded for audit.
+// Like a transfer, it fails with ErrItemNotTradable unless every input is
+// published and with an *ItemLockedError if any of them is locked.
+func (itm *Item) TradeUp(ctx context.Context, ownerID uuid.UUID, itemIDs []uuid.UUID) (*models.TradeUp, error) {
+	const op = "Item.TradeUp"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("ownerID", ownerID),
+	)
+
+	log.Info("attempting to trade up")
+
+	if ownerID == uuid.Nil {
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	if err := validateTradeUpItems(itemIDs); err != nil {
+		log.Warn("invalid trade-up", sl.Err(err))
+
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tu := &models.TradeUp{
+		OwnerId: ownerID,
+		Inputs:  make([]models.TradeUpInput, 0, len(itemIDs)),
+	}
+
+	var rarity string
+	defs := make(map[uuid.UUID]*models.ItemDefinition)
+
+	for _, id := range itemIDs {
+		inst, err := itm.repo.GetItemInstance(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrItemNotFound) {
+				log.Warn("trade-up input not found", sl.Err(err))
+
+				return &models.TradeUp{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+			}
+
+			log.Error("failed to get trade-up input", sl.Err(err))
+
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !inst.ConsumedAt.IsZero() {
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: item %s was already consumed", ErrInvalidTradeUp, id))
+		}
+
+		if inst.OwnerId != ownerID {
+			log.Warn("trade-up input is not owned by the owner", slog.Any("itemID", id))
+
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, ErrNotOwner)
+		}
+
+		if inst.Status != models.ItemStatusPublished {
+			log.Warn("trade-up input is not tradable", slog.Any("itemID", id))
+
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, ErrItemNotTradable)
+		}
+
+		def, ok := defs[inst.DefinitionId]
+		if !ok {
+			if def, err = itm.repo.GetItemDefinition(ctx, inst.DefinitionId); err != nil {
+				log.Error("failed to get definition of trade-up input", sl.Err(err))
+
+				return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+			}
+			defs[def.DefinitionId] = def
+		}
+
+		if rarity == "" {
+			rarity = def.Rarity
+		}
+		if def.Rarity != rarity {
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: inputs must share one rarity, got %q and %q", ErrInvalidTradeUp, rarity, def.Rarity))
+		}
+
+		tu.Inputs = append(tu.Inputs, models.TradeUpInput{
+			ItemId:       inst.InstanceId,
+			DefinitionId: inst.DefinitionId,
+			Float:        inst.Float,
+		})
+	}
+
+	if err := itm.checkUnlocked(ctx, itemIDs...); err != nil {
+		return &models.TradeUp{}, lockCheckError(log, op, err)
+	}
+
+	next, ok := itm.nextRarity(rarity)
+	if !ok {
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: rarity %q cannot be traded up", ErrInvalidTradeUp, rarity))
+	}
+
+	candidates := make(map[uuid.UUID][]*models.ItemDefinition, len(defs))
+	for id := range defs {
+		siblings, err := itm.repo.GetCollectionSiblings(ctx, id, next)
+		if err != nil {
+			return &models.TradeUp{}, collectionError(log, op, err)
+		}
+
+		candidates[id] = siblings
+	}
+
+	inputs := make([]loot.TradeUpInput, 0, len(tu.Inputs))
+	for _, in := range tu.Inputs {
+		inputs = append(inputs, loot.TradeUpInput{
+			Float:      in.Float,
+			Candidates: candidates[in.DefinitionId],
+		})
+	}
+
+	seed, err := newSeed()
+	if err != nil {
+		log.Error("failed to generate seed", sl.Err(err))
+
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := loot.TradeUp(inputs, seed)
+	if err != nil {
+		log.Warn("trade-up has no outcome", slog.String("rarity", next))
+
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, ErrNoTradeUpOutcome)
+	}
+
+	tu.OutputDefinitionId = out.Definition.DefinitionId
+	tu.OutputRarity = out.Definition.Rarity
+	tu.OutputFloat = out.Float
+	tu.OutputPatternSeed = out.PatternSeed
+	tu.Seed = seed
+
+	tu.TradeUpId, err = itm.repo.SaveTradeUp(ctx, tu, dropQuality)
+	if err != nil {
+		return &models.TradeUp{}, tradeUpError(log, op, err)
+	}
+
+	log.Info("trade-up successfully completed",
+		slog.Any("tradeUpID", tu.TradeUpId),
+		slog.Any("itemID", tu.OutputItemId),
+		slog.String("rarity", tu.OutputRarity),
+	)
+
+	return tu, nil
+}
+
+// GetTradeUp returns the record of a trade-up contract.
+func (itm *Item) GetTradeUp(ctx context.Context, tradeUpID uuid.UUID) (*models.TradeUp, error) {
+	const op = "Item.GetTradeUp"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("tradeUpID", tradeUpID),
+	)
+
+	log.Info("attempting to get trade-up")
+
+	tu, err := itm.repo.GetTradeUp(ctx, tradeUpID)
+	if err != nil {
+		return &models.TradeUp{}, tradeUpError(log, op, err)
+	}
+
+	return tu, nil
+}
+
+// nextRarity returns the rarity following the given one.
+func (itm *Item) nextRarity(rarity string) (string, bool) {
+	for i, r := range itm.rarityOrder {
+		if r == rarity && i+1 < len(itm.rarityOrder) {
+			return itm.rarityOrder[i+1], true
+		}
+	}
+
+	return "", false
+}
+
+func validateTradeUpItems(itemIDs []uuid.UUID) error {
+	if len(itemIDs) != tradeUpInputs {
+		return fmt.Errorf("%w: exactly %d items are required, got %d", ErrInvalidTradeUp, tradeUpInputs, len(itemIDs))
+	}
+
+	seen := make(map[uuid.UUID]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if id == uuid.Nil {
+			return fmt.Errorf("%w: item ID is required", ErrInvalidTradeUp)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: item %s is listed twice", ErrInvalidTradeUp, id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// tradeUpError logs err and translates storage errors into service errors.
+func tradeUpError(log *slog.Logger, op string, err error) error {
+	for _, e := range []struct{ storage, service error }{
+		{storage.ErrTradeUpInputsUnavailable, ErrTradeUpInputsUnavailable},
+		{storage.ErrTradeUpNotFound, ErrTradeUpNotFound},
+		{storage.ErrDefinitionNotFound, ErrDefinitionNotFound},
+	} {
+		if errors.Is(err, e.storage) {
+			log.Warn(e.service.Error(), sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, e.service)
+		}
+	}
+
+	log.Error("trade-up operation failed", sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
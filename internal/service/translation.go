@@ -0,0 +1,334 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchQuery     = 200
+)
+
+type RepositoryTranslation interface {
+	SaveTranslation(ctx context.Context, t *models.Translation) (err error)
+	DeleteTranslation(ctx context.Context, definitionID uuid.UUID, locale string) (err error)
+	GetTranslations(ctx context.Context, definitionIDs []uuid.UUID, locales []string) (translations map[uuid.UUID][]*models.Translation, err error)
+	SearchItemDefinitions(ctx context.Context, ds models.DefinitionSearch) (defs []*models.ItemDefinition, err error)
+}
+
+var (
+	ErrInvalidLocale       = errors.New("invalid locale")
+	ErrUnsupportedLocale   = errors.New("unsupported locale")
+	ErrTranslationNotFound = errors.New("translation not found")
+	ErrInvalidSearch       = errors.New("invalid search")
+)
+
+// SetTranslation creates or replaces the translation of a definition.
+// The locale must be one of the configured locales.
+func (itm *Item) SetTranslation(ctx context.Context, t *models.Translation) (*models.Translation, error) {
+	const op = "Item.SetTranslation"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", t.DefinitionId),
+		slog.String("locale", t.Locale),
+	)
+
+	log.Info("attempting to set translation")
+
+	locale, err := itm.supportedLocale(t.Locale)
+	if err != nil {
+		log.Warn("locale is not supported", sl.Err(err))
+
+		return &models.Translation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved := *t
+	saved.Locale = locale
+
+	if err := itm.repo.SaveTranslation(ctx, &saved); err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition not found", sl.Err(err))
+
+			return &models.Translation{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to save translation", sl.Err(err))
+
+		return &models.Translation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("translation successfully set")
+
+	return &saved, nil
+}
+
+// DeleteTranslation deletes the translation of a definition in the locale.
+func (itm *Item) DeleteTranslation(ctx context.Context, definitionID uuid.UUID, locale string) error {
+	const op = "Item.DeleteTranslation"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+		slog.String("locale", locale),
+	)
+
+	log.Info("attempting to delete translation")
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, fmt.Errorf("%w: %q is not a BCP 47 language tag", ErrInvalidLocale, locale))
+	}
+
+	if err := itm.repo.DeleteTranslation(ctx, definitionID, tag.String()); err != nil {
+		if errors.Is(err, storage.ErrTranslationNotFound) {
+			log.Warn("translation not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTranslationNotFound)
+		}
+
+		log.Error("failed to delete translation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("translation successfully deleted")
+
+	return nil
+}
+
+// GetTranslations returns every translation of a definition.
+func (itm *Item) GetTranslations(ctx context.Context, definitionID uuid.UUID) ([]*models.Translation, error) {
+	const op = "Item.GetTranslations"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.Any("definitionID", definitionID),
+	)
+
+	log.Info("attempting to get translations")
+
+	if _, err := itm.repo.GetItemDefinition(ctx, definitionID); err != nil {
+		if errors.Is(err, storage.ErrDefinitionNotFound) {
+			log.Warn("item definition not found", sl.Err(err))
+
+			return []*models.Translation{}, fmt.Errorf("%s: %w", op, ErrDefinitionNotFound)
+		}
+
+		log.Error("failed to get item definition", sl.Err(err))
+
+		return []*models.Translation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	translations, err := itm.repo.GetTranslations(ctx, []uuid.UUID{definitionID}, nil)
+	if err != nil {
+		log.Error("failed to get translations", sl.Err(err))
+
+		return []*models.Translation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if translations[definitionID] == nil {
+		return []*models.Translation{}, nil
+	}
+
+	return translations[definitionID], nil
+}
+
+// SearchItemDefinitions searches the definitions in the locale best
+// matching accept, an Accept-Language value, and returns them localized.
+// Zero limit uses the default limit.
+func (itm *Item) SearchItemDefinitions(ctx context.Context, query, accept string, limit int) ([]*models.ItemDefinition, error) {
+	const op = "Item.SearchItemDefinitions"
+
+	log := itm.log.With(
+		slog.String("op", op),
+		slog.String("query", query),
+	)
+
+	log.Info("attempting to search item definitions")
+
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > maxSearchQuery {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: query must be between 1 and %d bytes", ErrInvalidSearch, maxSearchQuery))
+	}
+
+	switch {
+	case limit == 0:
+		limit = defaultSearchLimit
+	case limit < 0 || limit > maxSearchLimit:
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, maxSearchLimit))
+	}
+
+	locale, err := itm.matchLocale(accept)
+	if err != nil {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defs, err := itm.repo.SearchItemDefinitions(ctx, models.DefinitionSearch{
+		Query:  query,
+		Locale: locale,
+		Limit:  limit,
+	})
+	if err != nil {
+		log.Error("failed to search item definitions", sl.Err(err))
+
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := itm.localizeDefinitions(ctx, locale, defs); err != nil {
+		log.Error("failed to localize item definitions", sl.Err(err))
+
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return defs, nil
+}
+
+// LocalizeItems replaces the names of the items with their translation in
+// the locale best matching accept, an Accept-Language value. Items without
+// a translation in that locale get the default locale's translation, and
+// keep their name when there is none either.
+func (itm *Item) LocalizeItems(ctx context.Context, accept string, items ...*models.Item) error {
+	const op = "Item.LocalizeItems"
+
+	locale, err := itm.matchLocale(accept)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.DefinitionId)
+	}
+
+	best, err := itm.bestTranslations(ctx, locale, ids)
+	if err != nil {
+		itm.log.Error("failed to get translations", slog.String("op", op), sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, item := range items {
+		if t, ok := best[item.DefinitionId]; ok {
+			item.Name, item.Description, item.Locale = t.Name, t.Description, t.Locale
+		}
+	}
+
+	return nil
+}
+
+// LocalizeDefinitions is LocalizeItems for item definitions.
+func (itm *Item) LocalizeDefinitions(ctx context.Context, accept string, defs ...*models.ItemDefinition) error {
+	const op = "Item.LocalizeDefinitions"
+
+	locale, err := itm.matchLocale(accept)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := itm.localizeDefinitions(ctx, locale, defs); err != nil {
+		itm.log.Error("failed to get translations", slog.String("op", op), sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (itm *Item) localizeDefinitions(ctx context.Context, locale string, defs []*models.ItemDefinition) error {
+	ids := make([]uuid.UUID, 0, len(defs))
+	for _, def := range defs {
+		ids = append(ids, def.DefinitionId)
+	}
+
+	best, err := itm.bestTranslations(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		if t, ok := best[def.DefinitionId]; ok {
+			def.Name, def.Description, def.Locale = t.Name, t.Description, t.Locale
+		}
+	}
+
+	return nil
+}
+
+// bestTranslations returns the translation of each definition in the
+// locale, or in the default locale when there is none.
+func (itm *Item) bestTranslations(ctx context.Context, locale string, definitionIDs []uuid.UUID) (map[uuid.UUID]*models.Translation, error) {
+	if len(definitionIDs) == 0 {
+		return map[uuid.UUID]*models.Translation{}, nil
+	}
+
+	fallback := itm.locales[0].String()
+
+	locales := []string{locale}
+	if locale != fallback {
+		locales = append(locales, fallback)
+	}
+
+	translations, err := itm.repo.GetTranslations(ctx, definitionIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	best := make(map[uuid.UUID]*models.Translation, len(translations))
+	for id, ts := range translations {
+		for _, t := range ts {
+			if best[id] == nil || t.Locale == locale {
+				best[id] = t
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// matchLocale returns the configured locale best matching accept, an
+// Accept-Language value such as "de-AT, de;q=0.9, en;q=0.5". An empty
+// value, or one matching no configured locale, selects the default locale.
+func (itm *Item) matchLocale(accept string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return itm.locales[0].String(), nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(accept)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not a list of BCP 47 language tags", ErrInvalidLocale, accept)
+	}
+
+	_, i, _ := itm.localeMatcher.Match(tags...)
+
+	return itm.locales[i].String(), nil
+}
+
+// supportedLocale returns the canonical form of locale if it is one of the
+// configured locales.
+func (itm *Item) supportedLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not a BCP 47 language tag", ErrInvalidLocale, locale)
+	}
+
+	for _, supported := range itm.locales {
+		if tag == supported {
+			return tag.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s is not one of the configured locales", ErrUnsupportedLocale, tag)
+}
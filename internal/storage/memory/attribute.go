@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SetItemAttributes sets the given attributes of the item, replacing their
+// previous values, and removes the attributes listed in remove. It returns
+// the resulting attributes of the item.
+func (s *Storage) SetItemAttributes(_ context.Context, itemID uuid.UUID, set models.Attributes, remove []string) (models.Attributes, error) {
+	const op = "Storage.SetItemAttributes"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.items[itemID]
+	if !ok {
+		return models.Attributes{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	attrs := cloneAttributes(inst.Attributes)
+	for key, value := range cloneAttributes(set) {
+		attrs[key] = value
+	}
+	for _, key := range remove {
+		delete(attrs, key)
+	}
+
+	inst.Attributes = attrs
+
+	return cloneAttributes(attrs), nil
+}
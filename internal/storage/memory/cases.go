@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveCaseOpening creates the dropped item in the owner's inventory and
+// records the opening. It fills in the item ID and the opening time of o.
+func (s *Storage) SaveCaseOpening(_ context.Context, o *models.CaseOpening, quality string) (uuid.UUID, error) {
+	const op = "Storage.SaveCaseOpening"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.definitions[o.DefinitionId]; !ok {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	inst := &models.ItemInstance{
+		InstanceId:   uuid.New(),
+		DefinitionId: o.DefinitionId,
+		OwnerId:      o.OwnerId,
+		Quality:      quality,
+		Float:        o.Float,
+		PatternSeed:  o.PatternSeed,
+		Attributes:   models.Attributes{},
+		Status:       models.ItemStatusPublished,
+		CreatedAt:    time.Now(),
+	}
+	s.items[inst.InstanceId] = inst
+	s.recordTransfer(&models.OwnershipTransfer{
+		ItemId:  inst.InstanceId,
+		ToOwner: inst.OwnerId,
+	})
+
+	o.ItemId = inst.InstanceId
+	o.OpenedAt = inst.CreatedAt
+
+	saved := cloneCaseOpening(o)
+	saved.OpeningId = uuid.New()
+	s.openings[saved.OpeningId] = saved
+
+	return saved.OpeningId, nil
+}
+
+func (s *Storage) GetCaseOpening(_ context.Context, openingID uuid.UUID) (*models.CaseOpening, error) {
+	const op = "Storage.GetCaseOpening"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.openings[openingID]
+	if !ok {
+		return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, storage.ErrCaseOpeningNotFound)
+	}
+
+	return cloneCaseOpening(o), nil
+}
+
+func cloneCaseOpening(o *models.CaseOpening) *models.CaseOpening {
+	clone := *o
+	clone.Pool = append([]uuid.UUID{}, o.Pool...)
+	clone.DropTable = make(map[string]float64, len(o.DropTable))
+	for rarity, weight := range o.DropTable {
+		clone.DropTable[rarity] = weight
+	}
+
+	return &clone
+}
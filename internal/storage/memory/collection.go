@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+func (s *Storage) SaveCollection(_ context.Context, c *models.Collection) (uuid.UUID, error) {
+	const op = "Storage.SaveCollection"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.collectionByName(c.Name) != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrCollectionExists)
+	}
+
+	saved := *c
+	saved.CollectionId = uuid.New()
+	saved.CreatedAt = time.Now()
+	s.collections[saved.CollectionId] = &saved
+	s.collectionItems[saved.CollectionId] = make(map[uuid.UUID]struct{})
+
+	return saved.CollectionId, nil
+}
+
+func (s *Storage) GetCollection(_ context.Context, collectionID uuid.UUID) (*models.Collection, error) {
+	const op = "Storage.GetCollection"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return &models.Collection{}, fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	clone := *c
+
+	return &clone, nil
+}
+
+func (s *Storage) GetAllCollections(_ context.Context) ([]*models.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := make([]*models.Collection, 0, len(s.collections))
+	for _, c := range s.collections {
+		clone := *c
+		collections = append(collections, &clone)
+	}
+
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+
+	return collections, nil
+}
+
+// UpdateCollection replaces the name, kind and description of the collection.
+func (s *Storage) UpdateCollection(_ context.Context, c *models.Collection) error {
+	const op = "Storage.UpdateCollection"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.collections[c.CollectionId]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	if other := s.collectionByName(c.Name); other != nil && other.CollectionId != c.CollectionId {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionExists)
+	}
+
+	stored.Name = c.Name
+	stored.Kind = c.Kind
+	stored.Description = c.Description
+	c.CreatedAt = stored.CreatedAt
+
+	return nil
+}
+
+// DeleteCollection deletes the collection and its memberships. The item
+// definitions are kept.
+func (s *Storage) DeleteCollection(_ context.Context, collectionID uuid.UUID) error {
+	const op = "Storage.DeleteCollection"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.collections[collectionID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	delete(s.collections, collectionID)
+	delete(s.collectionItems, collectionID)
+
+	return nil
+}
+
+// AddCollectionItems adds the definitions to the collection. Definitions
+// already in the collection are skipped.
+func (s *Storage) AddCollectionItems(_ context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Storage.AddCollectionItems"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members, ok := s.collectionItems[collectionID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	for _, id := range definitionIDs {
+		if _, ok := s.definitions[id]; !ok {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+	}
+
+	for _, id := range definitionIDs {
+		members[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// RemoveCollectionItems removes the definitions from the collection.
+// Definitions not in the collection are skipped.
+func (s *Storage) RemoveCollectionItems(_ context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Storage.RemoveCollectionItems"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members, ok := s.collectionItems[collectionID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	for _, id := range definitionIDs {
+		delete(members, id)
+	}
+
+	return nil
+}
+
+// GetCollectionItems returns the definitions of the collection ordered by name.
+func (s *Storage) GetCollectionItems(_ context.Context, collectionID uuid.UUID) ([]*models.ItemDefinition, error) {
+	const op = "Storage.GetCollectionItems"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members, ok := s.collectionItems[collectionID]
+	if !ok {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	defs := make([]*models.ItemDefinition, 0, len(members))
+	for id := range members {
+		clone := *s.definitions[id]
+		defs = append(defs, &clone)
+	}
+
+	sortDefinitions(defs)
+
+	return defs, nil
+}
+
+// GetCollectionSiblings returns the definitions of the rarity that share at
+// least one collection with the definition, ordered by ID.
+func (s *Storage) GetCollectionSiblings(_ context.Context, definitionID uuid.UUID, rarity string) ([]*models.ItemDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	siblings := make(map[uuid.UUID]struct{})
+	for _, members := range s.collectionItems {
+		if _, ok := members[definitionID]; !ok {
+			continue
+		}
+
+		for id := range members {
+			if s.definitions[id].Rarity == rarity {
+				siblings[id] = struct{}{}
+			}
+		}
+	}
+
+	defs := make([]*models.ItemDefinition, 0, len(siblings))
+	for id := range siblings {
+		clone := *s.definitions[id]
+		defs = append(defs, &clone)
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return uuidLess(defs[i].DefinitionId, defs[j].DefinitionId)
+	})
+
+	return defs, nil
+}
+
+func (s *Storage) collectionByName(name string) *models.Collection {
+	for _, c := range s.collections {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
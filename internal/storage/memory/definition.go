@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+func (s *Storage) SaveItemDefinition(_ context.Context, def *models.ItemDefinition) (uuid.UUID, error) {
+	const op = "Storage.SaveItemDefinition"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.definitionByName(def.Name, def.Rarity) != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionExists)
+	}
+
+	saved := *def
+	saved.DefinitionId = uuid.New()
+	saved.CreatedAt = time.Now()
+	s.definitions[saved.DefinitionId] = &saved
+
+	return saved.DefinitionId, nil
+}
+
+func (s *Storage) GetItemDefinition(_ context.Context, definitionID uuid.UUID) (*models.ItemDefinition, error) {
+	const op = "Storage.GetItemDefinition"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	def, ok := s.definitions[definitionID]
+	if !ok {
+		return &models.ItemDefinition{}, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	clone := *def
+
+	return &clone, nil
+}
+
+func (s *Storage) GetAllItemDefinitions(_ context.Context) ([]*models.ItemDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	defs := make([]*models.ItemDefinition, 0, len(s.definitions))
+	for _, def := range s.definitions {
+		clone := *def
+		defs = append(defs, &clone)
+	}
+
+	sortDefinitions(defs)
+
+	return defs, nil
+}
+
+func (s *Storage) DeleteItemDefinition(_ context.Context, definitionID uuid.UUID) error {
+	const op = "Storage.DeleteItemDefinition"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.definitions[definitionID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	for _, inst := range s.items {
+		if inst.DefinitionId == definitionID {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionInUse)
+		}
+	}
+
+	delete(s.definitions, definitionID)
+	delete(s.prices, definitionID)
+	delete(s.images, definitionID)
+	delete(s.translations, definitionID)
+	for _, defs := range s.collectionItems {
+		delete(defs, definitionID)
+	}
+
+	return nil
+}
+
+// sortDefinitions orders definitions by name and rarity.
+func sortDefinitions(defs []*models.ItemDefinition) {
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Name != defs[j].Name {
+			return defs[i].Name < defs[j].Name
+		}
+
+		return defs[i].Rarity < defs[j].Rarity
+	})
+}
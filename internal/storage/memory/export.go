@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"context"
+
+	"item-service/internal/domain/models"
+)
+
+// ExportItems calls fn with every item matching the filter, in the order
+// of GetAllItems. The items are copied first, so fn runs without holding
+// the lock; batch is ignored.
+func (s *Storage) ExportItems(ctx context.Context, filter models.ItemFilter, _ int, fn func(item *models.Item) error) error {
+	items, err := s.GetAllItems(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
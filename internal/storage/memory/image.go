@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveItemImage sets the image of the definition, replacing the previous
+// one. It fills in the upload time of img.
+func (s *Storage) SaveItemImage(_ context.Context, img *models.ItemImage) error {
+	const op = "Storage.SaveItemImage"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.definitions[img.DefinitionId]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	img.UploadedAt = time.Now()
+	s.images[img.DefinitionId] = cloneItemImage(img)
+
+	return nil
+}
+
+// GetItemImages returns the images of the definitions that have one.
+func (s *Storage) GetItemImages(_ context.Context, definitionIDs []uuid.UUID) (map[uuid.UUID]*models.ItemImage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	images := make(map[uuid.UUID]*models.ItemImage)
+	for _, id := range definitionIDs {
+		if img, ok := s.images[id]; ok {
+			images[id] = cloneItemImage(img)
+		}
+	}
+
+	return images, nil
+}
+
+func cloneItemImage(img *models.ItemImage) *models.ItemImage {
+	clone := *img
+	clone.ThumbnailSizes = append([]int{}, img.ThumbnailSizes...)
+	clone.URLs = models.ImageURLs{}
+
+	return &clone
+}
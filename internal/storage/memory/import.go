@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+)
+
+// ImportItems saves the items, creating their definitions on first use
+// like SaveItem. The returned IDs are in the order of the items; items
+// whose float is outside of their definition's range are not saved and get
+// uuid.Nil. A dry run changes nothing but returns the same result.
+func (s *Storage) ImportItems(_ context.Context, items []*models.Item, dryRun bool) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]uuid.UUID, len(items))
+	// created holds the definitions created by this import, which a dry run
+	// must not keep.
+	created := make(map[[2]string]*models.ItemDefinition)
+
+	now := time.Now()
+	for i, item := range items {
+		def := s.definitionByName(item.Name, item.Rarity)
+		if def == nil {
+			def = created[[2]string{item.Name, item.Rarity}]
+		}
+		if def == nil {
+			def = &models.ItemDefinition{
+				DefinitionId: uuid.New(),
+				Name:         item.Name,
+				Rarity:       item.Rarity,
+				MinFloat:     0,
+				MaxFloat:     1,
+				CreatedAt:    now,
+			}
+			created[[2]string{item.Name, item.Rarity}] = def
+		}
+
+		if !inFloatRange(def, item.Float) {
+			continue
+		}
+
+		status := item.Status
+		if status == "" {
+			status = models.ItemStatusPublished
+		}
+
+		attrs := models.Attributes{}
+		for k, v := range item.Attributes {
+			attrs[k] = v
+		}
+
+		ids[i] = uuid.New()
+		if dryRun {
+			continue
+		}
+
+		s.items[ids[i]] = &models.ItemInstance{
+			InstanceId:   ids[i],
+			DefinitionId: def.DefinitionId,
+			Quality:      item.Quality,
+			Float:        item.Float,
+			PatternSeed:  item.PatternSeed,
+			Attributes:   attrs,
+			Tags:         append([]string(nil), item.Tags...),
+			Status:       status,
+			CreatedAt:    now,
+		}
+	}
+
+	if !dryRun {
+		for _, def := range created {
+			s.definitions[def.DefinitionId] = def
+		}
+	}
+
+	return ids, nil
+}
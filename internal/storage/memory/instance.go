@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveItemInstance creates a copy of the definition. When inst.OwnerId is set
+// the item is created in that owner's inventory and the ownership is recorded.
+func (s *Storage) SaveItemInstance(_ context.Context, inst *models.ItemInstance) (uuid.UUID, error) {
+	const op = "Storage.SaveItemInstance"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	def, ok := s.definitions[inst.DefinitionId]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	if !inFloatRange(def, inst.Float) {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrFloatOutOfRange)
+	}
+
+	saved := *inst
+	saved.InstanceId = uuid.New()
+	saved.Exterior = ""
+	saved.Attributes = cloneAttributes(inst.Attributes)
+	saved.Tags = nil
+	saved.Status = models.ItemStatusPublished
+	saved.CreatedAt = time.Now()
+	s.items[saved.InstanceId] = &saved
+
+	if saved.OwnerId != uuid.Nil {
+		s.recordTransfer(&models.OwnershipTransfer{
+			ItemId:  saved.InstanceId,
+			ToOwner: saved.OwnerId,
+		})
+	}
+
+	return saved.InstanceId, nil
+}
+
+func (s *Storage) GetItemInstance(_ context.Context, instanceID uuid.UUID) (*models.ItemInstance, error) {
+	const op = "Storage.GetItemInstance"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, ok := s.items[instanceID]
+	if !ok {
+		return &models.ItemInstance{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	return instanceOf(inst), nil
+}
+
+func (s *Storage) GetItemInstances(_ context.Context, definitionID uuid.UUID) ([]*models.ItemInstance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	instances := make([]*models.ItemInstance, 0)
+	for _, inst := range s.items {
+		if inst.DefinitionId == definitionID {
+			instances = append(instances, instanceOf(inst))
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+
+		return uuidLess(a.InstanceId, b.InstanceId)
+	})
+
+	return instances, nil
+}
+
+// instanceOf returns a copy of the stored instance with its exterior derived.
+func instanceOf(inst *models.ItemInstance) *models.ItemInstance {
+	clone := *inst
+	clone.Exterior = models.ExteriorOf(inst.Float)
+	clone.Attributes = cloneAttributes(inst.Attributes)
+	clone.Tags = append([]string{}, inst.Tags...)
+
+	return &clone
+}
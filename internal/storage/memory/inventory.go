@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// GetInventory returns up to q.PageSize items of the owner ordered by ID, starting after q.After.
+func (s *Storage) GetInventory(_ context.Context, iq models.InventoryQuery) ([]*models.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0)
+	for id, inst := range s.items {
+		if inst.OwnerId != iq.OwnerId {
+			continue
+		}
+		if iq.DefinitionId != uuid.Nil && inst.DefinitionId != iq.DefinitionId {
+			continue
+		}
+		if iq.Rarity != "" && s.definitions[inst.DefinitionId].Rarity != iq.Rarity {
+			continue
+		}
+		if iq.Quality != "" && inst.Quality != iq.Quality {
+			continue
+		}
+		if len(iq.Statuses) > 0 && !hasStatus(inst.Status, iq.Statuses) {
+			continue
+		}
+		if iq.After != uuid.Nil && !uuidLess(iq.After, id) {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	sortUUIDs(ids)
+	if len(ids) > iq.PageSize {
+		ids = ids[:iq.PageSize]
+	}
+
+	items := make([]*models.Item, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, s.itemOf(s.items[id]))
+	}
+
+	return items, nil
+}
+
+// TransferItem moves the item from one owner to another and records the transfer.
+// It fails with storage.ErrNotOwner if the item is not held by from, with
+// storage.ErrItemNotTradable if it is not published and with
+// storage.ErrItemLocked if it has an active lock. A non-zero holdUntil locks
+// the item for its new owner until then.
+func (s *Storage) TransferItem(_ context.Context, itemID, from, to uuid.UUID, holdUntil time.Time) (*models.OwnershipTransfer, error) {
+	const op = "Storage.TransferItem"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfer := models.OwnershipTransfer{
+		ItemId:    itemID,
+		FromOwner: from,
+		ToOwner:   to,
+	}
+
+	if err := s.transferItem(&transfer, holdUntil); err != nil {
+		return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &transfer, nil
+}
+
+// transferItem changes the owner of t.ItemId and fills in the transfer ID
+// and time. The caller holds the write lock.
+func (s *Storage) transferItem(t *models.OwnershipTransfer, holdUntil time.Time) error {
+	inst, ok := s.items[t.ItemId]
+	if !ok {
+		return storage.ErrItemNotFound
+	}
+
+	if inst.OwnerId != t.FromOwner {
+		return storage.ErrNotOwner
+	}
+
+	if inst.Status != models.ItemStatusPublished {
+		return storage.ErrItemNotTradable
+	}
+
+	if s.isLocked(t.ItemId, time.Now()) {
+		return storage.ErrItemLocked
+	}
+
+	inst.OwnerId = t.ToOwner
+	s.recordTransfer(t)
+
+	if !holdUntil.IsZero() {
+		s.saveItemLock(&models.ItemLock{
+			ItemId:    t.ItemId,
+			Reason:    models.LockReasonTradeHold,
+			ExpiresAt: holdUntil,
+		})
+	}
+
+	return nil
+}
+
+// recordTransfer appends t to the history of its item. The caller holds the write lock.
+func (s *Storage) recordTransfer(t *models.OwnershipTransfer) {
+	t.TransferId = uuid.New()
+	t.TransferredAt = time.Now()
+
+	clone := *t
+	s.history[t.ItemId] = append(s.history[t.ItemId], &clone)
+}
+
+// GetOwnershipHistory returns the transfers of the item, oldest first.
+func (s *Storage) GetOwnershipHistory(_ context.Context, itemID uuid.UUID) ([]*models.OwnershipTransfer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	transfers := make([]*models.OwnershipTransfer, 0, len(s.history[itemID]))
+	for _, t := range s.history[itemID] {
+		clone := *t
+		transfers = append(transfers, &clone)
+	}
+
+	return transfers, nil
+}
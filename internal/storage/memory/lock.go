@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveItemLock locks the item and fills in the lock ID and creation time.
+func (s *Storage) SaveItemLock(_ context.Context, lock *models.ItemLock) (uuid.UUID, error) {
+	const op = "Storage.SaveItemLock"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[lock.ItemId]; !ok {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	s.saveItemLock(lock)
+
+	return lock.LockId, nil
+}
+
+// saveItemLock saves a copy of the lock. The caller holds the write lock.
+func (s *Storage) saveItemLock(lock *models.ItemLock) {
+	lock.LockId = uuid.New()
+	lock.CreatedAt = time.Now()
+
+	saved := *lock
+	s.locks[saved.LockId] = &saved
+}
+
+// DeleteItemLock removes the lock.
+func (s *Storage) DeleteItemLock(_ context.Context, lockID uuid.UUID) error {
+	const op = "Storage.DeleteItemLock"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.locks[lockID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrLockNotFound)
+	}
+
+	delete(s.locks, lockID)
+
+	return nil
+}
+
+// GetItemLocks returns the active locks of the items, ordered by item and
+// creation time.
+func (s *Storage) GetItemLocks(_ context.Context, itemIDs []uuid.UUID) ([]*models.ItemLock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make(map[uuid.UUID]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		ids[id] = true
+	}
+
+	now := time.Now()
+
+	locks := make([]*models.ItemLock, 0)
+	for _, lock := range s.locks {
+		if !ids[lock.ItemId] || !lock.ActiveAt(now) {
+			continue
+		}
+
+		clone := *lock
+		locks = append(locks, &clone)
+	}
+
+	sort.Slice(locks, func(i, j int) bool {
+		a, b := locks[i], locks[j]
+		if a.ItemId != b.ItemId {
+			return uuidLess(a.ItemId, b.ItemId)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+
+		return uuidLess(a.LockId, b.LockId)
+	})
+
+	return locks, nil
+}
+
+// DeleteExpiredLocks removes at most limit locks that expired at now and
+// returns how many were removed.
+func (s *Storage) DeleteExpiredLocks(_ context.Context, now time.Time, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for id, lock := range s.locks {
+		if deleted == limit {
+			break
+		}
+
+		if !lock.ActiveAt(now) {
+			delete(s.locks, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// ReleaseTradeHolds publishes at most limit locked items without a trade
+// hold lock in effect at now and returns how many were published.
+func (s *Storage) ReleaseTradeHolds(_ context.Context, now time.Time, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	held := make(map[uuid.UUID]bool)
+	for _, lock := range s.locks {
+		if lock.Reason == models.LockReasonTradeHold && lock.ActiveAt(now) {
+			held[lock.ItemId] = true
+		}
+	}
+
+	released := 0
+	for id, inst := range s.items {
+		if released == limit {
+			break
+		}
+
+		if inst.Status == models.ItemStatusLocked && !held[id] {
+			inst.Status = models.ItemStatusPublished
+			released++
+		}
+	}
+
+	return released, nil
+}
+
+// isLocked reports whether the item has a lock in effect at now. The caller
+// holds the lock.
+func (s *Storage) isLocked(itemID uuid.UUID, now time.Time) bool {
+	for _, lock := range s.locks {
+		if lock.ItemId == itemID && lock.ActiveAt(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteItemLocks removes every lock of the item. The caller holds the
+// write lock.
+func (s *Storage) deleteItemLocks(itemID uuid.UUID) {
+	for id, lock := range s.locks {
+		if lock.ItemId == itemID {
+			delete(s.locks, id)
+		}
+	}
+}
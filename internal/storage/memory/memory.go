@@ -0,0 +1,325 @@
+// Package memory implements the item storage in process memory. It is meant
+// for local development and tests; nothing survives a restart.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+type Storage struct {
+	mu sync.RWMutex
+
+	definitions map[uuid.UUID]*models.ItemDefinition
+	items       map[uuid.UUID]*models.ItemInstance
+	// history holds the ownership transfers of each item, oldest first.
+	history     map[uuid.UUID][]*models.OwnershipTransfer
+	trades      map[uuid.UUID]*models.TradeOffer
+	collections map[uuid.UUID]*models.Collection
+	// collectionItems holds the definitions of each collection.
+	collectionItems map[uuid.UUID]map[uuid.UUID]struct{}
+	openings        map[uuid.UUID]*models.CaseOpening
+	tradeUps        map[uuid.UUID]*models.TradeUp
+	// prices holds the price observations of each definition.
+	prices map[uuid.UUID]map[priceKey]models.PriceObservation
+	images map[uuid.UUID]*models.ItemImage
+	// translations are keyed by definition and locale.
+	translations map[uuid.UUID]map[string]*models.Translation
+	// schedules holds the scheduled status transition of each item.
+	schedules map[uuid.UUID]*models.Transition
+	locks     map[uuid.UUID]*models.ItemLock
+}
+
+// New returns an empty storage.
+func New() *Storage {
+	return &Storage{
+		definitions:     make(map[uuid.UUID]*models.ItemDefinition),
+		items:           make(map[uuid.UUID]*models.ItemInstance),
+		history:         make(map[uuid.UUID][]*models.OwnershipTransfer),
+		trades:          make(map[uuid.UUID]*models.TradeOffer),
+		collections:     make(map[uuid.UUID]*models.Collection),
+		collectionItems: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		openings:        make(map[uuid.UUID]*models.CaseOpening),
+		tradeUps:        make(map[uuid.UUID]*models.TradeUp),
+		prices:          make(map[uuid.UUID]map[priceKey]models.PriceObservation),
+		images:          make(map[uuid.UUID]*models.ItemImage),
+		translations:    make(map[uuid.UUID]map[string]*models.Translation),
+		schedules:       make(map[uuid.UUID]*models.Transition),
+		locks:           make(map[uuid.UUID]*models.ItemLock),
+	}
+}
+
+// Close is a no-op; it exists so that the storage can replace the PostgreSQL one.
+func (s *Storage) Close() {}
+
+// SaveItem creates an item together with its definition, if there is none
+// for the name and rarity yet. It fails with storage.ErrFloatOutOfRange if
+// the float is outside of the definition's range, without creating the
+// definition.
+func (s *Storage) SaveItem(_ context.Context, item *models.Item) (uuid.UUID, error) {
+	const op = "Storage.SaveItem"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	def := s.definitionByName(item.Name, item.Rarity)
+	if def == nil {
+		def = &models.ItemDefinition{
+			DefinitionId: uuid.New(),
+			Name:         item.Name,
+			Rarity:       item.Rarity,
+			MinFloat:     0,
+			MaxFloat:     1,
+			CreatedAt:    time.Now(),
+		}
+	}
+
+	if !inFloatRange(def, item.Float) {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrFloatOutOfRange)
+	}
+
+	s.definitions[def.DefinitionId] = def
+
+	status := item.Status
+	if status == "" {
+		status = models.ItemStatusPublished
+	}
+
+	inst := &models.ItemInstance{
+		InstanceId:   uuid.New(),
+		DefinitionId: def.DefinitionId,
+		Quality:      item.Quality,
+		Float:        item.Float,
+		PatternSeed:  item.PatternSeed,
+		Attributes:   models.Attributes{},
+		Status:       status,
+		CreatedAt:    time.Now(),
+	}
+	s.items[inst.InstanceId] = inst
+
+	return inst.InstanceId, nil
+}
+
+func (s *Storage) GetItem(_ context.Context, itemID uuid.UUID) (*models.Item, error) {
+	const op = "Storage.GetItem"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, ok := s.items[itemID]
+	if !ok {
+		return &models.Item{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	return s.itemOf(inst), nil
+}
+
+// GetAllItems returns the items matching the filter. Items consumed by
+// trade-up contracts are left out.
+func (s *Storage) GetAllItems(_ context.Context, filter models.ItemFilter) ([]*models.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]*models.Item, 0)
+
+	for _, inst := range s.items {
+		if !s.matchesFilter(inst, filter) {
+			continue
+		}
+
+		items = append(items, s.itemOf(inst))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+
+		switch filter.Sort {
+		case models.ItemSortFloatAsc:
+			if a.Float != b.Float {
+				return a.Float < b.Float
+			}
+		case models.ItemSortFloatDesc:
+			if a.Float != b.Float {
+				return a.Float > b.Float
+			}
+		}
+
+		return uuidLess(a.ItemId, b.ItemId)
+	})
+
+	return items, nil
+}
+
+// DeleteItem deletes the item, its ownership history, its scheduled
+// status transition and its expired locks. It fails with
+// storage.ErrItemLocked if the item has an active lock.
+func (s *Storage) DeleteItem(_ context.Context, itemID uuid.UUID) error {
+	const op = "Storage.DeleteItem"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[itemID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	if s.isLocked(itemID, time.Now()) {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemLocked)
+	}
+
+	delete(s.items, itemID)
+	delete(s.history, itemID)
+	delete(s.schedules, itemID)
+	s.deleteItemLocks(itemID)
+
+	return nil
+}
+
+// itemOf returns a copy of the instance joined with its definition.
+func (s *Storage) itemOf(inst *models.ItemInstance) *models.Item {
+	def := s.definitions[inst.DefinitionId]
+
+	return &models.Item{
+		ItemId:       inst.InstanceId,
+		DefinitionId: inst.DefinitionId,
+		OwnerId:      inst.OwnerId,
+		Name:         def.Name,
+		Rarity:       def.Rarity,
+		Quality:      inst.Quality,
+		Float:        inst.Float,
+		PatternSeed:  inst.PatternSeed,
+		Exterior:     models.ExteriorOf(inst.Float),
+		Attributes:   cloneAttributes(inst.Attributes),
+		Tags:         append([]string{}, inst.Tags...),
+		Status:       inst.Status,
+	}
+}
+
+func (s *Storage) matchesFilter(inst *models.ItemInstance, filter models.ItemFilter) bool {
+	if !inst.ConsumedAt.IsZero() {
+		return false
+	}
+	if filter.MinFloat != nil && inst.Float < *filter.MinFloat {
+		return false
+	}
+	if filter.MaxFloat != nil && inst.Float > *filter.MaxFloat {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !hasStatus(inst.Status, filter.Statuses) {
+		return false
+	}
+	if filter.CollectionId != uuid.Nil {
+		if _, ok := s.collectionItems[filter.CollectionId][inst.DefinitionId]; !ok {
+			return false
+		}
+	}
+
+	return containsAttributes(inst.Attributes, filter.Attributes) &&
+		hasStickers(inst.Attributes, filter.StickerIds) &&
+		hasTags(inst.Tags, filter.Tags, filter.TagMatch)
+}
+
+func (s *Storage) definitionByName(name, rarity string) *models.ItemDefinition {
+	for _, def := range s.definitions {
+		if def.Name == name && def.Rarity == rarity {
+			return def
+		}
+	}
+
+	return nil
+}
+
+func inFloatRange(def *models.ItemDefinition, float float64) bool {
+	return def.MinFloat <= float && float <= def.MaxFloat
+}
+
+// containsAttributes reports whether attrs has every value of want, with
+// the containment semantics of the PostgreSQL storage: stickers match if
+// every wanted sticker is present.
+func containsAttributes(attrs, want models.Attributes) bool {
+	for key, w := range want {
+		v, ok := attrs[key]
+		if !ok {
+			return false
+		}
+
+		wantStickers, isStickers := w.([]models.Sticker)
+		if !isStickers {
+			if !reflect.DeepEqual(v, w) {
+				return false
+			}
+
+			continue
+		}
+
+		stickers, _ := v.([]models.Sticker)
+		for _, ws := range wantStickers {
+			found := false
+			for _, st := range stickers {
+				if st == ws {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// hasStickers reports whether attrs has a sticker with each of the IDs in any slot.
+func hasStickers(attrs models.Attributes, stickerIDs []string) bool {
+	stickers, _ := attrs[models.AttrStickers].([]models.Sticker)
+
+	for _, id := range stickerIDs {
+		found := false
+		for _, st := range stickers {
+			if st.StickerId == id {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func cloneAttributes(attrs models.Attributes) models.Attributes {
+	clone := make(models.Attributes, len(attrs))
+	for key, value := range attrs {
+		if stickers, ok := value.([]models.Sticker); ok {
+			value = append([]models.Sticker(nil), stickers...)
+		}
+
+		clone[key] = value
+	}
+
+	return clone
+}
+
+// uuidLess orders UUIDs bytewise, as PostgreSQL does.
+func uuidLess(a, b uuid.UUID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
+
+func sortUUIDs(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool { return uuidLess(ids[i], ids[j]) })
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// priceKey identifies a price observation of a definition.
+type priceKey struct {
+	currency   string
+	source     string
+	observedAt int64
+}
+
+// SavePriceObservations stores a batch of price observations. An
+// observation of the same definition, currency, source and time replaces
+// the stored one.
+func (s *Storage) SavePriceObservations(_ context.Context, obs []models.PriceObservation) error {
+	const op = "Storage.SavePriceObservations"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, o := range obs {
+		if _, ok := s.definitions[o.DefinitionId]; !ok {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+	}
+
+	for _, o := range obs {
+		prices, ok := s.prices[o.DefinitionId]
+		if !ok {
+			prices = make(map[priceKey]models.PriceObservation)
+			s.prices[o.DefinitionId] = prices
+		}
+
+		o.ObservedAt = o.ObservedAt.UTC()
+		prices[priceKey{o.Price.Currency, o.Source, o.ObservedAt.UnixNano()}] = o
+	}
+
+	return nil
+}
+
+// GetPriceHistory returns the OHLC candles of the definition's prices,
+// oldest first. Buckets are aligned to UTC.
+func (s *Storage) GetPriceHistory(_ context.Context, pq models.PriceHistoryQuery) ([]*models.PriceCandle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	obs := s.observations(pq.DefinitionId, pq.Currency, func(o models.PriceObservation) bool {
+		return (pq.Source == "" || o.Source == pq.Source) &&
+			(pq.From.IsZero() || !o.ObservedAt.Before(pq.From)) &&
+			(pq.To.IsZero() || o.ObservedAt.Before(pq.To))
+	})
+
+	width := 24 * time.Hour
+	if pq.Interval == models.PriceIntervalHour {
+		width = time.Hour
+	}
+
+	candles := make([]*models.PriceCandle, 0)
+
+	var c *models.PriceCandle
+	for _, o := range obs {
+		start := o.ObservedAt.Truncate(width)
+		if c == nil || !c.Start.Equal(start) {
+			c = &models.PriceCandle{Start: start, Open: o.Price, High: o.Price, Low: o.Price}
+			candles = append(candles, c)
+		}
+
+		if o.Price.Amount > c.High.Amount {
+			c.High = o.Price
+		}
+		if o.Price.Amount < c.Low.Amount {
+			c.Low = o.Price
+		}
+		c.Close = o.Price
+		c.Volume += o.Volume
+		c.Observations++
+	}
+
+	return candles, nil
+}
+
+// GetValuationPrices sums up the observations of the definition since the
+// given time per currency, ordered by currency. Observations count by their
+// volume, or once if they have none. Without recent observations the latest
+// one is used.
+func (s *Storage) GetValuationPrices(_ context.Context, definitionID uuid.UUID, since time.Time) ([]models.PriceAggregate, error) {
+	const op = "Storage.GetValuationPrices"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := s.prices[definitionID]
+	if len(all) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPriceNotFound)
+	}
+
+	var latest models.PriceObservation
+	recent := make([]models.PriceObservation, 0)
+	for _, o := range all {
+		if !o.ObservedAt.Before(since) {
+			recent = append(recent, o)
+		}
+		if latest.ObservedAt.IsZero() || o.ObservedAt.After(latest.ObservedAt) {
+			latest = o
+		}
+	}
+	if len(recent) == 0 {
+		recent = append(recent, latest)
+	}
+
+	type sum struct {
+		agg      models.PriceAggregate
+		weighted *big.Int
+	}
+
+	sums := make(map[string]*sum)
+	for _, o := range recent {
+		c, ok := sums[o.Price.Currency]
+		if !ok {
+			c = &sum{weighted: new(big.Int)}
+			c.agg.Price.Currency = o.Price.Currency
+			sums[o.Price.Currency] = c
+		}
+
+		weight := o.Volume
+		if weight == 0 {
+			weight = 1
+		}
+
+		c.weighted.Add(c.weighted, new(big.Int).Mul(big.NewInt(o.Price.Amount), big.NewInt(weight)))
+		c.agg.Weight += weight
+		c.agg.Volume += o.Volume
+		c.agg.Observations++
+		if o.ObservedAt.After(c.agg.ObservedAt) {
+			c.agg.ObservedAt = o.ObservedAt
+		}
+	}
+
+	prices := make([]models.PriceAggregate, 0, len(sums))
+	for _, c := range sums {
+		c.agg.Price.Amount = roundDiv(c.weighted, big.NewInt(c.agg.Weight))
+		prices = append(prices, c.agg)
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Price.Currency < prices[j].Price.Currency })
+
+	return prices, nil
+}
+
+// roundDiv returns n / d of non-negative numbers rounded half up, like
+// round() of PostgreSQL numerics.
+func roundDiv(n, d *big.Int) int64 {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Lsh(r, 1).Cmp(d) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+
+	return q.Int64()
+}
+
+// observations returns the definition's observations in the currency that
+// match keep, ordered by time and source.
+func (s *Storage) observations(definitionID uuid.UUID, currency string, keep func(models.PriceObservation) bool) []models.PriceObservation {
+	obs := make([]models.PriceObservation, 0)
+	for _, o := range s.prices[definitionID] {
+		if o.Price.Currency == currency && keep(o) {
+			obs = append(obs, o)
+		}
+	}
+
+	sort.Slice(obs, func(i, j int) bool {
+		if !obs[i].ObservedAt.Equal(obs[j].ObservedAt) {
+			return obs[i].ObservedAt.Before(obs[j].ObservedAt)
+		}
+
+		return obs[i].Source < obs[j].Source
+	})
+
+	return obs
+}
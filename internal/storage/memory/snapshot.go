@@ -0,0 +1,826 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/lib/snapshot"
+	"item-service/internal/storage"
+)
+
+// The rows of snapshot tables, keyed by the column names of the PostgreSQL
+// schema so that snapshots move between both storages.
+type (
+	definitionRow struct {
+		Id        uuid.UUID `json:"id"`
+		Name      string    `json:"name"`
+		Rarity    string    `json:"rarity"`
+		MinFloat  float64   `json:"min_float"`
+		MaxFloat  float64   `json:"max_float"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	itemRow struct {
+		Id           uuid.UUID         `json:"id"`
+		DefinitionId uuid.UUID         `json:"definition_id"`
+		OwnerId      *uuid.UUID        `json:"owner_id"`
+		Quality      string            `json:"quality"`
+		Float        float64           `json:"float_value"`
+		PatternSeed  int               `json:"pattern_seed"`
+		Attributes   models.Attributes `json:"attributes"`
+		Tags         []string          `json:"tags"`
+		Status       models.ItemStatus `json:"status"`
+		ConsumedAt   *time.Time        `json:"consumed_at"`
+		CreatedAt    time.Time         `json:"created_at"`
+	}
+
+	historyRow struct {
+		Id            uuid.UUID  `json:"id"`
+		ItemId        uuid.UUID  `json:"item_id"`
+		FromOwner     *uuid.UUID `json:"from_owner"`
+		ToOwner       uuid.UUID  `json:"to_owner"`
+		TransferredAt time.Time  `json:"transferred_at"`
+	}
+
+	tradeOfferRow struct {
+		Id          uuid.UUID          `json:"id"`
+		ProposerId  uuid.UUID          `json:"proposer_id"`
+		RecipientId uuid.UUID          `json:"recipient_id"`
+		Status      models.TradeStatus `json:"status"`
+		CreatedAt   time.Time          `json:"created_at"`
+		ExpiresAt   time.Time          `json:"expires_at"`
+		ResolvedAt  *time.Time         `json:"resolved_at"`
+	}
+
+	tradeOfferItemRow struct {
+		TradeId uuid.UUID `json:"trade_id"`
+		ItemId  uuid.UUID `json:"item_id"`
+		OwnerId uuid.UUID `json:"owner_id"`
+	}
+
+	collectionRow struct {
+		Id          uuid.UUID             `json:"id"`
+		Name        string                `json:"name"`
+		Kind        models.CollectionKind `json:"kind"`
+		Description string                `json:"description"`
+		CreatedAt   time.Time             `json:"created_at"`
+	}
+
+	collectionItemRow struct {
+		CollectionId uuid.UUID `json:"collection_id"`
+		DefinitionId uuid.UUID `json:"definition_id"`
+		AddedAt      time.Time `json:"added_at"`
+	}
+
+	caseOpeningRow struct {
+		Id           uuid.UUID          `json:"id"`
+		CollectionId uuid.UUID          `json:"collection_id"`
+		OwnerId      uuid.UUID          `json:"owner_id"`
+		ItemId       uuid.UUID          `json:"item_id"`
+		DefinitionId uuid.UUID          `json:"definition_id"`
+		Rarity       string             `json:"rarity"`
+		Float        float64            `json:"float_value"`
+		PatternSeed  int                `json:"pattern_seed"`
+		Seed         int64              `json:"seed"`
+		DropTable    map[string]float64 `json:"drop_table"`
+		Pool         []uuid.UUID        `json:"pool"`
+		OpenedAt     time.Time          `json:"opened_at"`
+	}
+
+	tradeUpRow struct {
+		Id                 uuid.UUID `json:"id"`
+		OwnerId            uuid.UUID `json:"owner_id"`
+		OutputItemId       uuid.UUID `json:"output_item_id"`
+		OutputDefinitionId uuid.UUID `json:"output_definition_id"`
+		OutputRarity       string    `json:"output_rarity"`
+		OutputFloat        float64   `json:"output_float"`
+		OutputPatternSeed  int       `json:"output_pattern_seed"`
+		Seed               int64     `json:"seed"`
+		CreatedAt          time.Time `json:"created_at"`
+	}
+
+	tradeUpInputRow struct {
+		TradeUpId    uuid.UUID `json:"trade_up_id"`
+		ItemId       uuid.UUID `json:"item_id"`
+		DefinitionId uuid.UUID `json:"definition_id"`
+		Float        float64   `json:"float_value"`
+	}
+
+	priceRow struct {
+		DefinitionId uuid.UUID `json:"definition_id"`
+		Source       string    `json:"source"`
+		Currency     string    `json:"currency"`
+		Price        int64     `json:"price"`
+		Volume       int64     `json:"volume"`
+		ObservedAt   time.Time `json:"observed_at"`
+	}
+
+	imageRow struct {
+		DefinitionId   uuid.UUID `json:"definition_id"`
+		Hash           string    `json:"hash"`
+		ContentType    string    `json:"content_type"`
+		Width          int       `json:"width"`
+		Height         int       `json:"height"`
+		Size           int64     `json:"size_bytes"`
+		ThumbnailSizes []int     `json:"thumbnail_sizes"`
+		UploadedAt     time.Time `json:"uploaded_at"`
+	}
+
+	translationRow struct {
+		DefinitionId uuid.UUID `json:"definition_id"`
+		Locale       string    `json:"locale"`
+		Name         string    `json:"name"`
+		Description  string    `json:"description"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+
+	scheduleRow struct {
+		ItemId    uuid.UUID         `json:"item_id"`
+		To        models.ItemStatus `json:"to_status"`
+		DueAt     time.Time         `json:"due_at"`
+		CreatedAt time.Time         `json:"created_at"`
+	}
+
+	lockRow struct {
+		Id        uuid.UUID  `json:"id"`
+		ItemId    uuid.UUID  `json:"item_id"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+)
+
+// SchemaVersion returns storage.SchemaVersion: the storage always has the
+// schema of the build.
+func (s *Storage) SchemaVersion(context.Context) (int, error) {
+	return storage.SchemaVersion, nil
+}
+
+// Snapshot writes every row of the storage to w in the order of
+// snapshot.Tables. Writes wait until the snapshot is written.
+func (s *Storage) Snapshot(ctx context.Context, w *snapshot.Writer) error {
+	const op = "Storage.Snapshot"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	write := func(table string, rows ...any) error {
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := w.Write(table, row); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, table := range snapshot.Tables {
+		if err := write(table, s.snapshotRows(table)...); err != nil {
+			return fmt.Errorf("%s: %s: %w", op, table, err)
+		}
+	}
+
+	return nil
+}
+
+// snapshotRows returns the rows of the table ordered by primary key, like
+// the PostgreSQL storage writes them.
+func (s *Storage) snapshotRows(table string) []any {
+	var rows []any
+
+	switch table {
+	case "item_definitions":
+		for _, id := range sortedKeys(s.definitions) {
+			d := s.definitions[id]
+			rows = append(rows, definitionRow{
+				Id:        d.DefinitionId,
+				Name:      d.Name,
+				Rarity:    d.Rarity,
+				MinFloat:  d.MinFloat,
+				MaxFloat:  d.MaxFloat,
+				CreatedAt: d.CreatedAt,
+			})
+		}
+	case "items":
+		for _, id := range sortedKeys(s.items) {
+			inst := s.items[id]
+			rows = append(rows, itemRow{
+				Id:           inst.InstanceId,
+				DefinitionId: inst.DefinitionId,
+				OwnerId:      optionalUUID(inst.OwnerId),
+				Quality:      inst.Quality,
+				Float:        inst.Float,
+				PatternSeed:  inst.PatternSeed,
+				Attributes:   nonNilAttributes(inst.Attributes),
+				Tags:         append([]string{}, inst.Tags...),
+				Status:       inst.Status,
+				ConsumedAt:   optionalTime(inst.ConsumedAt),
+				CreatedAt:    inst.CreatedAt,
+			})
+		}
+	case "ownership_history":
+		var transfers []*models.OwnershipTransfer
+		for _, h := range s.history {
+			transfers = append(transfers, h...)
+		}
+		sort.Slice(transfers, func(i, j int) bool { return uuidLess(transfers[i].TransferId, transfers[j].TransferId) })
+
+		for _, t := range transfers {
+			rows = append(rows, historyRow{
+				Id:            t.TransferId,
+				ItemId:        t.ItemId,
+				FromOwner:     optionalUUID(t.FromOwner),
+				ToOwner:       t.ToOwner,
+				TransferredAt: t.TransferredAt,
+			})
+		}
+	case "trade_offers":
+		for _, id := range sortedKeys(s.trades) {
+			t := s.trades[id]
+			rows = append(rows, tradeOfferRow{
+				Id:          t.TradeId,
+				ProposerId:  t.ProposerId,
+				RecipientId: t.RecipientId,
+				Status:      t.Status,
+				CreatedAt:   t.CreatedAt,
+				ExpiresAt:   t.ExpiresAt,
+				ResolvedAt:  optionalTime(t.ResolvedAt),
+			})
+		}
+	case "trade_offer_items":
+		for _, id := range sortedKeys(s.trades) {
+			t := s.trades[id]
+
+			var items []tradeOfferItemRow
+			for _, itemID := range t.ProposerItems {
+				items = append(items, tradeOfferItemRow{TradeId: id, ItemId: itemID, OwnerId: t.ProposerId})
+			}
+			for _, itemID := range t.RecipientItems {
+				items = append(items, tradeOfferItemRow{TradeId: id, ItemId: itemID, OwnerId: t.RecipientId})
+			}
+			sort.Slice(items, func(i, j int) bool { return uuidLess(items[i].ItemId, items[j].ItemId) })
+
+			for _, item := range items {
+				rows = append(rows, item)
+			}
+		}
+	case "collections":
+		for _, id := range sortedKeys(s.collections) {
+			c := s.collections[id]
+			rows = append(rows, collectionRow{
+				Id:          c.CollectionId,
+				Name:        c.Name,
+				Kind:        c.Kind,
+				Description: c.Description,
+				CreatedAt:   c.CreatedAt,
+			})
+		}
+	case "collection_items":
+		for _, id := range sortedKeys(s.collectionItems) {
+			// The storage does not record when definitions were added, so
+			// they count as added with the collection.
+			addedAt := s.collections[id].CreatedAt
+			for _, defID := range sortedKeys(s.collectionItems[id]) {
+				rows = append(rows, collectionItemRow{CollectionId: id, DefinitionId: defID, AddedAt: addedAt})
+			}
+		}
+	case "case_openings":
+		for _, id := range sortedKeys(s.openings) {
+			o := s.openings[id]
+			rows = append(rows, caseOpeningRow{
+				Id:           o.OpeningId,
+				CollectionId: o.CollectionId,
+				OwnerId:      o.OwnerId,
+				ItemId:       o.ItemId,
+				DefinitionId: o.DefinitionId,
+				Rarity:       o.Rarity,
+				Float:        o.Float,
+				PatternSeed:  o.PatternSeed,
+				Seed:         o.Seed,
+				DropTable:    o.DropTable,
+				Pool:         append([]uuid.UUID{}, o.Pool...),
+				OpenedAt:     o.OpenedAt,
+			})
+		}
+	case "trade_ups":
+		for _, id := range sortedKeys(s.tradeUps) {
+			tu := s.tradeUps[id]
+			rows = append(rows, tradeUpRow{
+				Id:                 tu.TradeUpId,
+				OwnerId:            tu.OwnerId,
+				OutputItemId:       tu.OutputItemId,
+				OutputDefinitionId: tu.OutputDefinitionId,
+				OutputRarity:       tu.OutputRarity,
+				OutputFloat:        tu.OutputFloat,
+				OutputPatternSeed:  tu.OutputPatternSeed,
+				Seed:               tu.Seed,
+				CreatedAt:          tu.CreatedAt,
+			})
+		}
+	case "trade_up_inputs":
+		for _, id := range sortedKeys(s.tradeUps) {
+			inputs := append([]models.TradeUpInput{}, s.tradeUps[id].Inputs...)
+			sort.Slice(inputs, func(i, j int) bool { return uuidLess(inputs[i].ItemId, inputs[j].ItemId) })
+
+			for _, in := range inputs {
+				rows = append(rows, tradeUpInputRow{
+					TradeUpId:    id,
+					ItemId:       in.ItemId,
+					DefinitionId: in.DefinitionId,
+					Float:        in.Float,
+				})
+			}
+		}
+	case "price_observations":
+		for _, id := range sortedKeys(s.prices) {
+			obs := make([]models.PriceObservation, 0, len(s.prices[id]))
+			for _, o := range s.prices[id] {
+				obs = append(obs, o)
+			}
+			sort.Slice(obs, func(i, j int) bool {
+				a, b := obs[i], obs[j]
+				if a.Price.Currency != b.Price.Currency {
+					return a.Price.Currency < b.Price.Currency
+				}
+				if a.Source != b.Source {
+					return a.Source < b.Source
+				}
+
+				return a.ObservedAt.Before(b.ObservedAt)
+			})
+
+			for _, o := range obs {
+				rows = append(rows, priceRow{
+					DefinitionId: o.DefinitionId,
+					Source:       o.Source,
+					Currency:     o.Price.Currency,
+					Price:        o.Price.Amount,
+					Volume:       o.Volume,
+					ObservedAt:   o.ObservedAt,
+				})
+			}
+		}
+	case "item_images":
+		for _, id := range sortedKeys(s.images) {
+			img := s.images[id]
+			rows = append(rows, imageRow{
+				DefinitionId:   img.DefinitionId,
+				Hash:           img.Hash,
+				ContentType:    img.ContentType,
+				Width:          img.Width,
+				Height:         img.Height,
+				Size:           img.Size,
+				ThumbnailSizes: append([]int{}, img.ThumbnailSizes...),
+				UploadedAt:     img.UploadedAt,
+			})
+		}
+	case "item_definition_translations":
+		for _, id := range sortedKeys(s.translations) {
+			locales := make([]string, 0, len(s.translations[id]))
+			for locale := range s.translations[id] {
+				locales = append(locales, locale)
+			}
+			sort.Strings(locales)
+
+			for _, locale := range locales {
+				t := s.translations[id][locale]
+				rows = append(rows, translationRow{
+					DefinitionId: t.DefinitionId,
+					Locale:       t.Locale,
+					Name:         t.Name,
+					Description:  t.Description,
+					UpdatedAt:    t.UpdatedAt,
+				})
+			}
+		}
+	case "item_status_schedules":
+		for _, id := range sortedKeys(s.schedules) {
+			t := s.schedules[id]
+			rows = append(rows, scheduleRow{
+				ItemId:    t.ItemId,
+				To:        t.To,
+				DueAt:     t.DueAt,
+				CreatedAt: t.CreatedAt,
+			})
+		}
+	case "item_locks":
+		for _, id := range sortedKeys(s.locks) {
+			l := s.locks[id]
+			rows = append(rows, lockRow{
+				Id:        l.LockId,
+				ItemId:    l.ItemId,
+				Reason:    l.Reason,
+				ExpiresAt: optionalTime(l.ExpiresAt),
+				CreatedAt: l.CreatedAt,
+			})
+		}
+	}
+
+	return rows
+}
+
+// Restore reads the rows of r into a new storage and replaces the empty
+// contents of s with it, so nothing is restored if any row fails. Rows
+// must reference rows restored before them, as foreign keys require in
+// PostgreSQL.
+func (s *Storage) Restore(ctx context.Context, r *snapshot.Reader) error {
+	const op = "Storage.Restore"
+
+	if !s.empty() {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotEmpty)
+	}
+
+	restored := New()
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		table, row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := restored.restoreRow(table, row); err != nil {
+			return fmt.Errorf("%s: %s row %d: %w", op, table, r.Rows()[table], err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Rows may have been written while the snapshot was read.
+	if !s.emptyLocked() {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotEmpty)
+	}
+
+	s.definitions = restored.definitions
+	s.items = restored.items
+	s.history = restored.history
+	s.trades = restored.trades
+	s.collections = restored.collections
+	s.collectionItems = restored.collectionItems
+	s.openings = restored.openings
+	s.tradeUps = restored.tradeUps
+	s.prices = restored.prices
+	s.images = restored.images
+	s.translations = restored.translations
+	s.schedules = restored.schedules
+	s.locks = restored.locks
+
+	return nil
+}
+
+// restoreRow adds a row of the table to s, which no one else uses yet.
+func (s *Storage) restoreRow(table string, raw json.RawMessage) error {
+	switch table {
+	case "item_definitions":
+		var row definitionRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		s.definitions[row.Id] = &models.ItemDefinition{
+			DefinitionId: row.Id,
+			Name:         row.Name,
+			Rarity:       row.Rarity,
+			MinFloat:     row.MinFloat,
+			MaxFloat:     row.MaxFloat,
+			CreatedAt:    row.CreatedAt,
+		}
+	case "items":
+		var row itemRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.definitions, row.DefinitionId, "definition"); err != nil {
+			return err
+		}
+
+		s.items[row.Id] = &models.ItemInstance{
+			InstanceId:   row.Id,
+			DefinitionId: row.DefinitionId,
+			OwnerId:      uuidOf(row.OwnerId),
+			Quality:      row.Quality,
+			Float:        row.Float,
+			PatternSeed:  row.PatternSeed,
+			Attributes:   nonNilAttributes(row.Attributes),
+			Tags:         row.Tags,
+			Status:       row.Status,
+			CreatedAt:    row.CreatedAt,
+			ConsumedAt:   timeOf(row.ConsumedAt),
+		}
+	case "ownership_history":
+		var row historyRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.items, row.ItemId, "item"); err != nil {
+			return err
+		}
+
+		s.history[row.ItemId] = append(s.history[row.ItemId], &models.OwnershipTransfer{
+			TransferId:    row.Id,
+			ItemId:        row.ItemId,
+			FromOwner:     uuidOf(row.FromOwner),
+			ToOwner:       row.ToOwner,
+			TransferredAt: row.TransferredAt,
+		})
+
+		// History is kept oldest first.
+		h := s.history[row.ItemId]
+		sort.SliceStable(h, func(i, j int) bool { return h[i].TransferredAt.Before(h[j].TransferredAt) })
+	case "trade_offers":
+		var row tradeOfferRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		s.trades[row.Id] = &models.TradeOffer{
+			TradeId:        row.Id,
+			ProposerId:     row.ProposerId,
+			RecipientId:    row.RecipientId,
+			ProposerItems:  []uuid.UUID{},
+			RecipientItems: []uuid.UUID{},
+			Status:         row.Status,
+			CreatedAt:      row.CreatedAt,
+			ExpiresAt:      row.ExpiresAt,
+			ResolvedAt:     timeOf(row.ResolvedAt),
+		}
+	case "trade_offer_items":
+		var row tradeOfferItemRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.trades, row.TradeId, "trade offer"); err != nil {
+			return err
+		}
+
+		t := s.trades[row.TradeId]
+		if row.OwnerId == t.ProposerId {
+			t.ProposerItems = append(t.ProposerItems, row.ItemId)
+		} else {
+			t.RecipientItems = append(t.RecipientItems, row.ItemId)
+		}
+	case "collections":
+		var row collectionRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		s.collections[row.Id] = &models.Collection{
+			CollectionId: row.Id,
+			Name:         row.Name,
+			Kind:         row.Kind,
+			Description:  row.Description,
+			CreatedAt:    row.CreatedAt,
+		}
+		s.collectionItems[row.Id] = make(map[uuid.UUID]struct{})
+	case "collection_items":
+		var row collectionItemRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.collections, row.CollectionId, "collection"); err != nil {
+			return err
+		}
+		if err := references(s.definitions, row.DefinitionId, "definition"); err != nil {
+			return err
+		}
+
+		s.collectionItems[row.CollectionId][row.DefinitionId] = struct{}{}
+	case "case_openings":
+		var row caseOpeningRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		s.openings[row.Id] = &models.CaseOpening{
+			OpeningId:    row.Id,
+			CollectionId: row.CollectionId,
+			OwnerId:      row.OwnerId,
+			ItemId:       row.ItemId,
+			DefinitionId: row.DefinitionId,
+			Rarity:       row.Rarity,
+			Float:        row.Float,
+			PatternSeed:  row.PatternSeed,
+			Seed:         row.Seed,
+			DropTable:    row.DropTable,
+			Pool:         row.Pool,
+			OpenedAt:     row.OpenedAt,
+		}
+	case "trade_ups":
+		var row tradeUpRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		s.tradeUps[row.Id] = &models.TradeUp{
+			TradeUpId:          row.Id,
+			OwnerId:            row.OwnerId,
+			OutputItemId:       row.OutputItemId,
+			OutputDefinitionId: row.OutputDefinitionId,
+			OutputRarity:       row.OutputRarity,
+			OutputFloat:        row.OutputFloat,
+			OutputPatternSeed:  row.OutputPatternSeed,
+			Seed:               row.Seed,
+			CreatedAt:          row.CreatedAt,
+		}
+	case "trade_up_inputs":
+		var row tradeUpInputRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.tradeUps, row.TradeUpId, "trade-up"); err != nil {
+			return err
+		}
+
+		tu := s.tradeUps[row.TradeUpId]
+		tu.Inputs = append(tu.Inputs, models.TradeUpInput{
+			ItemId:       row.ItemId,
+			DefinitionId: row.DefinitionId,
+			Float:        row.Float,
+		})
+	case "price_observations":
+		var row priceRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.definitions, row.DefinitionId, "definition"); err != nil {
+			return err
+		}
+
+		prices, ok := s.prices[row.DefinitionId]
+		if !ok {
+			prices = make(map[priceKey]models.PriceObservation)
+			s.prices[row.DefinitionId] = prices
+		}
+
+		observedAt := row.ObservedAt.UTC()
+		prices[priceKey{row.Currency, row.Source, observedAt.UnixNano()}] = models.PriceObservation{
+			DefinitionId: row.DefinitionId,
+			Source:       row.Source,
+			Price:        models.Money{Amount: row.Price, Currency: row.Currency},
+			Volume:       row.Volume,
+			ObservedAt:   observedAt,
+		}
+	case "item_images":
+		var row imageRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.definitions, row.DefinitionId, "definition"); err != nil {
+			return err
+		}
+
+		s.images[row.DefinitionId] = &models.ItemImage{
+			DefinitionId:   row.DefinitionId,
+			Hash:           row.Hash,
+			ContentType:    row.ContentType,
+			Width:          row.Width,
+			Height:         row.Height,
+			Size:           row.Size,
+			ThumbnailSizes: row.ThumbnailSizes,
+			UploadedAt:     row.UploadedAt,
+		}
+	case "item_definition_translations":
+		var row translationRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.definitions, row.DefinitionId, "definition"); err != nil {
+			return err
+		}
+
+		if s.translations[row.DefinitionId] == nil {
+			s.translations[row.DefinitionId] = make(map[string]*models.Translation)
+		}
+		s.translations[row.DefinitionId][row.Locale] = &models.Translation{
+			DefinitionId: row.DefinitionId,
+			Locale:       row.Locale,
+			Name:         row.Name,
+			Description:  row.Description,
+			UpdatedAt:    row.UpdatedAt,
+		}
+	case "item_status_schedules":
+		var row scheduleRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.items, row.ItemId, "item"); err != nil {
+			return err
+		}
+
+		s.schedules[row.ItemId] = &models.Transition{
+			ItemId:    row.ItemId,
+			To:        row.To,
+			DueAt:     row.DueAt,
+			CreatedAt: row.CreatedAt,
+		}
+	case "item_locks":
+		var row lockRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+		if err := references(s.items, row.ItemId, "item"); err != nil {
+			return err
+		}
+
+		s.locks[row.Id] = &models.ItemLock{
+			LockId:    row.Id,
+			ItemId:    row.ItemId,
+			Reason:    row.Reason,
+			ExpiresAt: timeOf(row.ExpiresAt),
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return nil
+}
+
+// references fails unless the row with the id was restored into rows.
+func references[V any](rows map[uuid.UUID]V, id uuid.UUID, what string) error {
+	if _, ok := rows[id]; !ok {
+		return fmt.Errorf("references unknown %s %s", what, id)
+	}
+
+	return nil
+}
+
+func (s *Storage) empty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.emptyLocked()
+}
+
+func (s *Storage) emptyLocked() bool {
+	return len(s.definitions) == 0 && len(s.items) == 0 && len(s.trades) == 0 &&
+		len(s.collections) == 0 && len(s.openings) == 0 && len(s.tradeUps) == 0
+}
+
+// sortedKeys returns the keys of m in the order PostgreSQL sorts UUIDs.
+func sortedKeys[V any](m map[uuid.UUID]V) []uuid.UUID {
+	keys := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		keys = append(keys, id)
+	}
+	sortUUIDs(keys)
+
+	return keys
+}
+
+func optionalUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	return &id
+}
+
+func uuidOf(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+
+	return *id
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func timeOf(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
+
+func nonNilAttributes(attrs models.Attributes) models.Attributes {
+	if attrs == nil {
+		return models.Attributes{}
+	}
+
+	return attrs
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// TransitionItem changes the status of the item from from to to, drops its
+// scheduled transition and saves hold, if any. Leaving the locked status
+// removes the trade hold locks of the item. It fails with
+// storage.ErrStatusChanged if the item is no longer in from.
+func (s *Storage) TransitionItem(_ context.Context, itemID uuid.UUID, from, to models.ItemStatus, hold *models.ItemLock) error {
+	const op = "Storage.TransitionItem"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.items[itemID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	if inst.Status != from {
+		return fmt.Errorf("%s: %w", op, fmt.Errorf("%w: item is %s", storage.ErrStatusChanged, inst.Status))
+	}
+
+	inst.Status = to
+	delete(s.schedules, itemID)
+
+	if from == models.ItemStatusLocked && to != models.ItemStatusLocked {
+		for id, lock := range s.locks {
+			if lock.ItemId == itemID && lock.Reason == models.LockReasonTradeHold {
+				delete(s.locks, id)
+			}
+		}
+	}
+
+	if hold != nil {
+		s.saveItemLock(hold)
+	}
+
+	return nil
+}
+
+// ScheduleTransition schedules t, replacing the scheduled transition of the
+// item, if any. It fills in the creation time of t.
+func (s *Storage) ScheduleTransition(_ context.Context, t *models.Transition) error {
+	const op = "Storage.ScheduleTransition"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[t.ItemId]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	s.scheduleTransition(t)
+
+	return nil
+}
+
+// scheduleTransition saves a copy of t. The caller holds the write lock.
+func (s *Storage) scheduleTransition(t *models.Transition) {
+	t.CreatedAt = time.Now()
+
+	saved := *t
+	s.schedules[t.ItemId] = &saved
+}
+
+// GetScheduledTransition returns the scheduled transition of the item.
+func (s *Storage) GetScheduledTransition(_ context.Context, itemID uuid.UUID) (*models.Transition, error) {
+	const op = "Storage.GetScheduledTransition"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.schedules[itemID]
+	if !ok {
+		return &models.Transition{}, fmt.Errorf("%s: %w", op, storage.ErrTransitionNotFound)
+	}
+
+	clone := *t
+
+	return &clone, nil
+}
+
+// DeleteScheduledTransition drops the scheduled transition of the item.
+func (s *Storage) DeleteScheduledTransition(_ context.Context, itemID uuid.UUID) error {
+	const op = "Storage.DeleteScheduledTransition"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[itemID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTransitionNotFound)
+	}
+
+	delete(s.schedules, itemID)
+
+	return nil
+}
+
+// DueTransitions returns at most limit scheduled transitions due at now,
+// the earliest first.
+func (s *Storage) DueTransitions(_ context.Context, now time.Time, limit int) ([]*models.Transition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	transitions := make([]*models.Transition, 0)
+	for _, t := range s.schedules {
+		if t.DueAt.After(now) {
+			continue
+		}
+
+		clone := *t
+		transitions = append(transitions, &clone)
+	}
+
+	sort.Slice(transitions, func(i, j int) bool {
+		a, b := transitions[i], transitions[j]
+		if !a.DueAt.Equal(b.DueAt) {
+			return a.DueAt.Before(b.DueAt)
+		}
+
+		return uuidLess(a.ItemId, b.ItemId)
+	})
+
+	if len(transitions) > limit {
+		transitions = transitions[:limit]
+	}
+
+	return transitions, nil
+}
+
+// hasStatus reports whether status is one of statuses.
+func hasStatus(status models.ItemStatus, statuses []models.ItemStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// AddItemTags adds the tags to the item and returns its tags, sorted.
+// Tags the item already has are left as they are.
+func (s *Storage) AddItemTags(_ context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Storage.AddItemTags"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.items[itemID]
+	if !ok {
+		return []string{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	set := make(map[string]bool, len(inst.Tags)+len(tags))
+	for _, tag := range append(append([]string{}, inst.Tags...), tags...) {
+		set[tag] = true
+	}
+
+	if len(set) > models.MaxItemTags {
+		return []string{}, fmt.Errorf("%s: %w", op, storage.ErrTooManyTags)
+	}
+
+	inst.Tags = sortedTags(set)
+
+	return append([]string{}, inst.Tags...), nil
+}
+
+// RemoveItemTags removes the tags from the item and returns its remaining
+// tags, sorted. Tags the item does not have are ignored.
+func (s *Storage) RemoveItemTags(_ context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Storage.RemoveItemTags"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.items[itemID]
+	if !ok {
+		return []string{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	set := make(map[string]bool, len(inst.Tags))
+	for _, tag := range inst.Tags {
+		set[tag] = true
+	}
+	for _, tag := range tags {
+		delete(set, tag)
+	}
+
+	inst.Tags = sortedTags(set)
+
+	return append([]string{}, inst.Tags...), nil
+}
+
+// GetFacets counts the items matching the filter by rarity, quality and
+// tag. At most tagLimit tags are returned.
+func (s *Storage) GetFacets(_ context.Context, filter models.ItemFilter, tagLimit int) (*models.Facets, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		total     int64
+		rarities  = make(map[string]int64)
+		qualities = make(map[string]int64)
+		tags      = make(map[string]int64)
+	)
+
+	for _, inst := range s.items {
+		if !s.matchesFilter(inst, filter) {
+			continue
+		}
+
+		total++
+		rarities[s.definitions[inst.DefinitionId].Rarity]++
+		qualities[inst.Quality]++
+		for _, tag := range inst.Tags {
+			tags[tag]++
+		}
+	}
+
+	facets := &models.Facets{
+		Total:     total,
+		Rarities:  facetCounts(rarities),
+		Qualities: facetCounts(qualities),
+		Tags:      facetCounts(tags),
+	}
+	if len(facets.Tags) > tagLimit {
+		facets.Tags = facets.Tags[:tagLimit]
+	}
+
+	return facets, nil
+}
+
+// hasTags reports whether tags match want as selected by match.
+func hasTags(tags, want []string, match models.TagMatch) bool {
+	if len(want) == 0 {
+		return true
+	}
+
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	for _, tag := range want {
+		if has[tag] && match == models.TagMatchAny {
+			return true
+		}
+		if !has[tag] && match != models.TagMatchAny {
+			return false
+		}
+	}
+
+	return match != models.TagMatchAny
+}
+
+func sortedTags(set map[string]bool) []string {
+	tags := make([]string, 0, len(set))
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+func facetCounts(counts map[string]int64) []models.FacetCount {
+	fcs := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		fcs = append(fcs, models.FacetCount{Value: value, Count: count})
+	}
+
+	models.SortFacetCounts(fcs)
+
+	return fcs
+}
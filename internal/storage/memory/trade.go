@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveTradeOffer creates a pending trade offer. It fails with
+// storage.ErrTradeItemsUnavailable unless every item is currently held by
+// the party that gives it.
+func (s *Storage) SaveTradeOffer(_ context.Context, offer *models.TradeOffer) (uuid.UUID, error) {
+	const op = "Storage.SaveTradeOffer"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTradeItems(offer); err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved := cloneTradeOffer(offer)
+	saved.TradeId = uuid.New()
+	saved.Status = models.TradePending
+	saved.CreatedAt = time.Now()
+	saved.ResolvedAt = time.Time{}
+	s.trades[saved.TradeId] = saved
+
+	return saved.TradeId, nil
+}
+
+// AcceptTradeOffer swaps the items of a pending offer and marks it
+// accepted. Every item must still be held by the party that gives it,
+// otherwise nothing changes. An offer past its expiry is marked expired and
+// storage.ErrTradeExpired is returned. A non-zero holdUntil locks the
+// swapped items for their new owners.
+func (s *Storage) AcceptTradeOffer(_ context.Context, tradeID, recipientID uuid.UUID, holdUntil time.Time) (*models.TradeOffer, error) {
+	const op = "Storage.AcceptTradeOffer"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offer, err := s.pendingTradeOffer(tradeID)
+	if err != nil {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if offer.RecipientId != recipientID {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrNotTradeParticipant)
+	}
+
+	if err := s.checkTradeItems(offer); err != nil {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// checkTradeItems guarantees that none of the transfers fails.
+	for _, id := range offer.ProposerItems {
+		_ = s.transferItem(&models.OwnershipTransfer{ItemId: id, FromOwner: offer.ProposerId, ToOwner: offer.RecipientId}, holdUntil)
+	}
+	for _, id := range offer.RecipientItems {
+		_ = s.transferItem(&models.OwnershipTransfer{ItemId: id, FromOwner: offer.RecipientId, ToOwner: offer.ProposerId}, holdUntil)
+	}
+
+	resolveTradeOffer(offer, models.TradeAccepted)
+
+	return cloneTradeOffer(offer), nil
+}
+
+// DeclineTradeOffer marks a pending offer declined on behalf of its recipient.
+func (s *Storage) DeclineTradeOffer(_ context.Context, tradeID, recipientID uuid.UUID) (*models.TradeOffer, error) {
+	return s.resolveTradeOffer("Storage.DeclineTradeOffer", tradeID, models.TradeDeclined,
+		func(offer *models.TradeOffer) bool { return offer.RecipientId == recipientID },
+	)
+}
+
+// CancelTradeOffer marks a pending offer cancelled on behalf of its proposer.
+func (s *Storage) CancelTradeOffer(_ context.Context, tradeID, proposerID uuid.UUID) (*models.TradeOffer, error) {
+	return s.resolveTradeOffer("Storage.CancelTradeOffer", tradeID, models.TradeCancelled,
+		func(offer *models.TradeOffer) bool { return offer.ProposerId == proposerID },
+	)
+}
+
+func (s *Storage) resolveTradeOffer(
+	op string,
+	tradeID uuid.UUID,
+	status models.TradeStatus,
+	allowed func(offer *models.TradeOffer) bool,
+) (*models.TradeOffer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offer, err := s.pendingTradeOffer(tradeID)
+	if err != nil {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !allowed(offer) {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrNotTradeParticipant)
+	}
+
+	resolveTradeOffer(offer, status)
+
+	return cloneTradeOffer(offer), nil
+}
+
+// pendingTradeOffer returns the stored offer if it is pending. A pending
+// offer past its expiry is marked expired and storage.ErrTradeExpired is
+// returned. The caller holds the write lock.
+func (s *Storage) pendingTradeOffer(tradeID uuid.UUID) (*models.TradeOffer, error) {
+	offer, ok := s.trades[tradeID]
+	if !ok {
+		return nil, storage.ErrTradeNotFound
+	}
+
+	if offer.Status != models.TradePending {
+		return nil, storage.ErrTradeNotPending
+	}
+
+	if !offer.ExpiresAt.After(time.Now()) {
+		resolveTradeOffer(offer, models.TradeExpired)
+
+		return nil, storage.ErrTradeExpired
+	}
+
+	return offer, nil
+}
+
+func resolveTradeOffer(offer *models.TradeOffer, status models.TradeStatus) {
+	offer.Status = status
+	offer.ResolvedAt = time.Now()
+}
+
+// checkTradeItems verifies that every item of the offer exists, is
+// published, has no active lock and is held by the party that gives it.
+func (s *Storage) checkTradeItems(offer *models.TradeOffer) error {
+	now := time.Now()
+
+	for _, side := range []struct {
+		owner uuid.UUID
+		items []uuid.UUID
+	}{
+		{offer.ProposerId, offer.ProposerItems},
+		{offer.RecipientId, offer.RecipientItems},
+	} {
+		for _, id := range side.items {
+			inst, ok := s.items[id]
+			if !ok || inst.OwnerId != side.owner || inst.Status != models.ItemStatusPublished || s.isLocked(id, now) {
+				return storage.ErrTradeItemsUnavailable
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTradeOffer(_ context.Context, tradeID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Storage.GetTradeOffer"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	offer, ok := s.trades[tradeID]
+	if !ok {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrTradeNotFound)
+	}
+
+	return tradeOfferView(offer, time.Now()), nil
+}
+
+// GetTradeOffers returns the offers sent or received by tq.OwnerId, newest first.
+func (s *Storage) GetTradeOffers(_ context.Context, tq models.TradeQuery) ([]*models.TradeOffer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+
+	offers := make([]*models.TradeOffer, 0)
+	for _, offer := range s.trades {
+		if offer.ProposerId != tq.OwnerId && offer.RecipientId != tq.OwnerId {
+			continue
+		}
+
+		view := tradeOfferView(offer, now)
+		if tq.Status != "" && view.Status != tq.Status {
+			continue
+		}
+
+		offers = append(offers, view)
+	}
+
+	sort.Slice(offers, func(i, j int) bool {
+		a, b := offers[i], offers[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+
+		return uuidLess(a.TradeId, b.TradeId)
+	})
+
+	return offers, nil
+}
+
+// tradeOfferView returns a copy of the offer that reports a pending offer
+// past its expiry as expired, even before it was marked so.
+func tradeOfferView(offer *models.TradeOffer, now time.Time) *models.TradeOffer {
+	view := cloneTradeOffer(offer)
+	if view.Status == models.TradePending && !view.ExpiresAt.After(now) {
+		view.Status = models.TradeExpired
+	}
+
+	return view
+}
+
+// cloneTradeOffer copies the offer with its items sorted by ID.
+func cloneTradeOffer(offer *models.TradeOffer) *models.TradeOffer {
+	clone := *offer
+	clone.ProposerItems = append([]uuid.UUID{}, offer.ProposerItems...)
+	clone.RecipientItems = append([]uuid.UUID{}, offer.RecipientItems...)
+	sortUUIDs(clone.ProposerItems)
+	sortUUIDs(clone.RecipientItems)
+
+	return &clone
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveTradeUp consumes the input items, creates the output item in the
+// owner's inventory and records the contract. It fails with
+// storage.ErrTradeUpInputsUnavailable unless every input is still held by
+// the owner, not consumed, published, without an active lock and unchanged
+// since it was read. It fills in the output item ID and the creation time
+// of tu.
+func (s *Storage) SaveTradeUp(_ context.Context, tu *models.TradeUp, quality string) (uuid.UUID, error) {
+	const op = "Storage.SaveTradeUp"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, in := range tu.Inputs {
+		inst, ok := s.items[in.ItemId]
+		if !ok ||
+			inst.OwnerId != tu.OwnerId ||
+			!inst.ConsumedAt.IsZero() ||
+			inst.Status != models.ItemStatusPublished ||
+			s.isLocked(in.ItemId, now) ||
+			inst.DefinitionId != in.DefinitionId ||
+			inst.Float != in.Float {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrTradeUpInputsUnavailable)
+		}
+	}
+
+	if _, ok := s.definitions[tu.OutputDefinitionId]; !ok {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	for _, in := range tu.Inputs {
+		inst := s.items[in.ItemId]
+		inst.OwnerId = uuid.Nil
+		inst.ConsumedAt = now
+	}
+
+	out := &models.ItemInstance{
+		InstanceId:   uuid.New(),
+		DefinitionId: tu.OutputDefinitionId,
+		OwnerId:      tu.OwnerId,
+		Quality:      quality,
+		Float:        tu.OutputFloat,
+		PatternSeed:  tu.OutputPatternSeed,
+		Attributes:   models.Attributes{},
+		Status:       models.ItemStatusPublished,
+		CreatedAt:    now,
+	}
+	s.items[out.InstanceId] = out
+	s.recordTransfer(&models.OwnershipTransfer{
+		ItemId:  out.InstanceId,
+		ToOwner: out.OwnerId,
+	})
+
+	tu.OutputItemId = out.InstanceId
+	tu.CreatedAt = now
+
+	saved := cloneTradeUp(tu)
+	saved.TradeUpId = uuid.New()
+	s.tradeUps[saved.TradeUpId] = saved
+
+	return saved.TradeUpId, nil
+}
+
+// GetTradeUp returns the record of a trade-up contract with its inputs.
+func (s *Storage) GetTradeUp(_ context.Context, tradeUpID uuid.UUID) (*models.TradeUp, error) {
+	const op = "Storage.GetTradeUp"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tu, ok := s.tradeUps[tradeUpID]
+	if !ok {
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, storage.ErrTradeUpNotFound)
+	}
+
+	return cloneTradeUp(tu), nil
+}
+
+// cloneTradeUp returns a copy of tu with its inputs ordered by item ID.
+func cloneTradeUp(tu *models.TradeUp) *models.TradeUp {
+	clone := *tu
+	clone.Inputs = append([]models.TradeUpInput{}, tu.Inputs...)
+	sort.Slice(clone.Inputs, func(i, j int) bool {
+		return uuidLess(clone.Inputs[i].ItemId, clone.Inputs[j].ItemId)
+	})
+
+	return &clone
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveTranslation creates or replaces the translation of the definition
+// in t.Locale. It fills in the update time of t.
+func (s *Storage) SaveTranslation(_ context.Context, t *models.Translation) error {
+	const op = "Storage.SaveTranslation"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.definitions[t.DefinitionId]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	if s.translations[t.DefinitionId] == nil {
+		s.translations[t.DefinitionId] = make(map[string]*models.Translation)
+	}
+
+	t.UpdatedAt = time.Now()
+	clone := *t
+	s.translations[t.DefinitionId][t.Locale] = &clone
+
+	return nil
+}
+
+func (s *Storage) DeleteTranslation(_ context.Context, definitionID uuid.UUID, locale string) error {
+	const op = "Storage.DeleteTranslation"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.translations[definitionID][locale]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTranslationNotFound)
+	}
+
+	delete(s.translations[definitionID], locale)
+
+	return nil
+}
+
+// GetTranslations returns the translations of the definitions by
+// definition, ordered by locale. Nil locales return every locale.
+func (s *Storage) GetTranslations(_ context.Context, definitionIDs []uuid.UUID, locales []string) (map[uuid.UUID][]*models.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	translations := make(map[uuid.UUID][]*models.Translation)
+	for _, id := range definitionIDs {
+		for locale, t := range s.translations[id] {
+			if locales != nil && !contains(locales, locale) {
+				continue
+			}
+
+			clone := *t
+			translations[id] = append(translations[id], &clone)
+		}
+
+		sort.Slice(translations[id], func(i, j int) bool {
+			return translations[id][i].Locale < translations[id][j].Locale
+		})
+	}
+
+	return translations, nil
+}
+
+// SearchItemDefinitions returns the definitions whose translation in the
+// locale, or whose name when there is none, contains every word of the
+// query. Unlike the postgres storage it does not stem words, and best
+// matches are those with the most occurrences of the query words.
+func (s *Storage) SearchItemDefinitions(_ context.Context, ds models.DefinitionSearch) ([]*models.ItemDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	terms := searchWords(ds.Query)
+	if len(terms) == 0 {
+		return []*models.ItemDefinition{}, nil
+	}
+
+	type match struct {
+		def  *models.ItemDefinition
+		rank int
+	}
+
+	var matches []match
+	for id, def := range s.definitions {
+		text := def.Name
+		if t, ok := s.translations[id][ds.Locale]; ok {
+			text = t.Name + " " + t.Description
+		}
+
+		counts := make(map[string]int)
+		for _, w := range searchWords(text) {
+			counts[w]++
+		}
+
+		rank := 0
+		for _, term := range terms {
+			if counts[term] == 0 {
+				rank = 0
+
+				break
+			}
+			rank += counts[term]
+		}
+
+		if rank > 0 {
+			clone := *def
+			matches = append(matches, match{def: &clone, rank: rank})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank > matches[j].rank
+		}
+		if matches[i].def.Name != matches[j].def.Name {
+			return matches[i].def.Name < matches[j].def.Name
+		}
+
+		return matches[i].def.Rarity < matches[j].def.Rarity
+	})
+
+	if len(matches) > ds.Limit {
+		matches = matches[:ds.Limit]
+	}
+
+	defs := make([]*models.ItemDefinition, 0, len(matches))
+	for _, m := range matches {
+		defs = append(defs, m.def)
+	}
+
+	return defs, nil
+}
+
+// searchWords splits text into lower-case words.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SetItemAttributes sets the given attributes of the item, replacing their
+// previous values, and removes the attributes listed in remove. It returns
+// the resulting attributes of the item.
+func (s *Storage) SetItemAttributes(ctx context.Context, itemID uuid.UUID, set models.Attributes, remove []string) (models.Attributes, error) {
+	const op = "Storage.SetItemAttributes"
+
+	q := `
+		UPDATE items
+		SET attributes = (attributes || $2::jsonb) - $3::text[]
+		WHERE id = $1
+		RETURNING attributes
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if remove == nil {
+		remove = []string{}
+	}
+
+	var attrs models.Attributes
+
+	if err := s.writer(ctx).QueryRow(ctx, q, itemID, attributesArg(set), remove).Scan(&attrs); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Attributes{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+
+		return models.Attributes{}, pgError(op, err)
+	}
+
+	return attrs, nil
+}
+
+// attributesArg returns attrs as a query argument, encoding nil as an empty object.
+func attributesArg(attrs models.Attributes) models.Attributes {
+	if attrs == nil {
+		return models.Attributes{}
+	}
+
+	return attrs
+}
+
+// attributesFilter returns the JSONB document items must contain to match
+// the attributes and stickers of the filter, or nil if there is none.
+func attributesFilter(filter models.ItemFilter) map[string]any {
+	if len(filter.Attributes) == 0 && len(filter.StickerIds) == 0 {
+		return nil
+	}
+
+	contains := make(map[string]any, len(filter.Attributes)+1)
+	for key, value := range filter.Attributes {
+		contains[key] = value
+	}
+
+	if len(filter.StickerIds) > 0 {
+		// Objects in a JSONB array match on a subset of their keys, so the
+		// slot and wear of the stickers are left out.
+		stickers := make([]map[string]string, 0, len(filter.StickerIds))
+		for _, id := range filter.StickerIds {
+			stickers = append(stickers, map[string]string{"sticker_id": id})
+		}
+
+		contains[models.AttrStickers] = stickers
+	}
+
+	return contains
+}
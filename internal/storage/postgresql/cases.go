@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveCaseOpening creates the dropped item in the owner's inventory and
+// records the opening, in a single statement. It fills in the item ID and
+// the opening time of o.
+func (s *Storage) SaveCaseOpening(ctx context.Context, o *models.CaseOpening, quality string) (uuid.UUID, error) {
+	const op = "Storage.SaveCaseOpening"
+
+	q := `
+		WITH item AS (
+			INSERT INTO items (
+				id,
+				definition_id,
+				owner_id,
+				quality,
+				float_value,
+				pattern_seed
+			)
+			VALUES (
+				gen_random_uuid(),
+				$1,
+				$2,
+				$3,
+				$4,
+				$5
+			)
+			RETURNING id, owner_id
+		), history AS (
+			INSERT INTO ownership_history (
+				item_id,
+				to_owner
+			)
+			SELECT id, owner_id
+			FROM item
+		)
+		INSERT INTO case_openings (
+			collection_id,
+			owner_id,
+			item_id,
+			definition_id,
+			rarity,
+			float_value,
+			pattern_seed,
+			seed,
+			drop_table,
+			pool
+		)
+		SELECT $6, owner_id, id, $1, $7, $4, $5, $8, $9, $10
+		FROM item
+		RETURNING id, item_id, opened_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var id uuid.UUID
+
+	if err := s.writer(ctx).QueryRow(
+		ctx, q,
+		o.DefinitionId, o.OwnerId, quality, o.Float, o.PatternSeed,
+		o.CollectionId, o.Rarity, o.Seed, o.DropTable, o.Pool,
+	).Scan(&id, &o.ItemId, &o.OpenedAt); err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetCaseOpening(ctx context.Context, openingID uuid.UUID) (*models.CaseOpening, error) {
+	const op = "Storage.GetCaseOpening"
+
+	q := `
+		SELECT
+			id,
+			collection_id,
+			owner_id,
+			item_id,
+			definition_id,
+			rarity,
+			float_value,
+			pattern_seed,
+			seed,
+			drop_table,
+			pool,
+			opened_at
+		FROM case_openings
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var o models.CaseOpening
+	if err := s.reader(ctx).QueryRow(ctx, q, openingID).Scan(
+		&o.OpeningId,
+		&o.CollectionId,
+		&o.OwnerId,
+		&o.ItemId,
+		&o.DefinitionId,
+		&o.Rarity,
+		&o.Float,
+		&o.PatternSeed,
+		&o.Seed,
+		&o.DropTable,
+		&o.Pool,
+		&o.OpenedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.CaseOpening{}, fmt.Errorf("%s: %w", op, storage.ErrCaseOpeningNotFound)
+		}
+
+		return &models.CaseOpening{}, pgError(op, err)
+	}
+
+	return &o, nil
+}
@@ -0,0 +1,339 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+const collectionItemsCollectionFK = "collection_items_collection_id_fkey"
+
+func (s *Storage) SaveCollection(ctx context.Context, c *models.Collection) (uuid.UUID, error) {
+	const op = "Storage.SaveCollection"
+
+	q := `
+		INSERT INTO collections (
+			name,
+			kind,
+			description
+		)
+		VALUES (
+			$1,
+			$2,
+			$3
+		)
+		RETURNING id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var id uuid.UUID
+
+	if err := s.writer(ctx).QueryRow(ctx, q, c.Name, c.Kind, c.Description).Scan(&id); err != nil {
+		if isPgCode(err, codeUniqueViolation) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrCollectionExists)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetCollection(ctx context.Context, collectionID uuid.UUID) (*models.Collection, error) {
+	const op = "Storage.GetCollection"
+
+	q := `
+		SELECT
+			id,
+			name,
+			kind,
+			description,
+			created_at
+		FROM collections
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var c models.Collection
+	if err := s.reader(ctx).QueryRow(ctx, q, collectionID).Scan(
+		&c.CollectionId,
+		&c.Name,
+		&c.Kind,
+		&c.Description,
+		&c.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.Collection{}, fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+		}
+
+		return &models.Collection{}, pgError(op, err)
+	}
+
+	return &c, nil
+}
+
+func (s *Storage) GetAllCollections(ctx context.Context) ([]*models.Collection, error) {
+	const op = "Storage.GetAllCollections"
+
+	q := `
+		SELECT
+			id,
+			name,
+			kind,
+			description,
+			created_at
+		FROM collections
+		ORDER BY name
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q)
+	if err != nil {
+		return []*models.Collection{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	collections := make([]*models.Collection, 0)
+
+	for rows.Next() {
+		var c models.Collection
+
+		if err := rows.Scan(&c.CollectionId, &c.Name, &c.Kind, &c.Description, &c.CreatedAt); err != nil {
+			return []*models.Collection{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		collections = append(collections, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.Collection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collections, nil
+}
+
+// UpdateCollection replaces the name, kind and description of the collection.
+func (s *Storage) UpdateCollection(ctx context.Context, c *models.Collection) error {
+	const op = "Storage.UpdateCollection"
+
+	q := `
+		UPDATE collections
+		SET name = $2, kind = $3, description = $4
+		WHERE id = $1
+		RETURNING created_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if err := s.writer(ctx).QueryRow(ctx, q, c.CollectionId, c.Name, c.Kind, c.Description).Scan(&c.CreatedAt); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+		case isPgCode(err, codeUniqueViolation):
+			return fmt.Errorf("%s: %w", op, storage.ErrCollectionExists)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes the collection and its memberships. The item
+// definitions are kept.
+func (s *Storage) DeleteCollection(ctx context.Context, collectionID uuid.UUID) error {
+	const op = "Storage.DeleteCollection"
+
+	q := `
+		DELETE FROM collections
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, collectionID)
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	return nil
+}
+
+// AddCollectionItems adds the definitions to the collection. Definitions
+// already in the collection are skipped.
+func (s *Storage) AddCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Storage.AddCollectionItems"
+
+	q := `
+		INSERT INTO collection_items (
+			collection_id,
+			definition_id
+		)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if _, err := s.writer(ctx).Exec(ctx, q, collectionID, definitionIDs); err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			if pgConstraint(err) == collectionItemsCollectionFK {
+				return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+			}
+
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// RemoveCollectionItems removes the definitions from the collection.
+// Definitions not in the collection are skipped.
+func (s *Storage) RemoveCollectionItems(ctx context.Context, collectionID uuid.UUID, definitionIDs []uuid.UUID) error {
+	const op = "Storage.RemoveCollectionItems"
+
+	q := `
+		WITH removed AS (
+			DELETE FROM collection_items
+			WHERE collection_id = $1 AND definition_id = ANY($2)
+		)
+		SELECT EXISTS (SELECT 1 FROM collections WHERE id = $1)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var exists bool
+
+	if err := s.writer(ctx).QueryRow(ctx, q, collectionID, definitionIDs).Scan(&exists); err != nil {
+		return pgError(op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+	}
+
+	return nil
+}
+
+// GetCollectionItems returns the definitions of the collection ordered by name.
+func (s *Storage) GetCollectionItems(ctx context.Context, collectionID uuid.UUID) ([]*models.ItemDefinition, error) {
+	const op = "Storage.GetCollectionItems"
+
+	q := `
+		SELECT
+			d.id,
+			d.name,
+			d.rarity,
+			d.min_float,
+			d.max_float,
+			d.created_at
+		FROM collections c
+		JOIN collection_items ci ON ci.collection_id = c.id
+		JOIN item_definitions d ON d.id = ci.definition_id
+		WHERE c.id = $1
+		ORDER BY d.name, d.rarity
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	db := s.reader(ctx)
+
+	rows, err := db.Query(ctx, q, collectionID)
+	if err != nil {
+		return []*models.ItemDefinition{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	defs := make([]*models.ItemDefinition, 0)
+
+	for rows.Next() {
+		var def models.ItemDefinition
+
+		if err := rows.Scan(&def.DefinitionId, &def.Name, &def.Rarity, &def.MinFloat, &def.MaxFloat, &def.CreatedAt); err != nil {
+			return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		defs = append(defs, &def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// An empty result is either an empty or a missing collection.
+	if len(defs) == 0 {
+		var exists bool
+
+		q := `SELECT EXISTS (SELECT 1 FROM collections WHERE id = $1)`
+		defer s.logQuery(ctx, op, q)()
+
+		if err := db.QueryRow(ctx, q, collectionID).Scan(&exists); err != nil {
+			return []*models.ItemDefinition{}, pgError(op, err)
+		}
+
+		if !exists {
+			return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+		}
+	}
+
+	return defs, nil
+}
+
+// GetCollectionSiblings returns the definitions of the rarity that share at
+// least one collection with the definition.
+func (s *Storage) GetCollectionSiblings(ctx context.Context, definitionID uuid.UUID, rarity string) ([]*models.ItemDefinition, error) {
+	const op = "Storage.GetCollectionSiblings"
+
+	q := `
+		SELECT
+			d.id,
+			d.name,
+			d.rarity,
+			d.min_float,
+			d.max_float,
+			d.created_at
+		FROM item_definitions d
+		WHERE d.rarity = $2
+		AND EXISTS (
+			SELECT 1
+			FROM collection_items own
+			JOIN collection_items ci ON ci.collection_id = own.collection_id
+			WHERE own.definition_id = $1
+			AND ci.definition_id = d.id
+		)
+		ORDER BY d.id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, definitionID, rarity)
+	if err != nil {
+		return []*models.ItemDefinition{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	defs := make([]*models.ItemDefinition, 0)
+
+	for rows.Next() {
+		var def models.ItemDefinition
+
+		if err := rows.Scan(&def.DefinitionId, &def.Name, &def.Rarity, &def.MinFloat, &def.MaxFloat, &def.CreatedAt); err != nil {
+			return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		defs = append(defs, &def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+func (s *Storage) SaveItemDefinition(ctx context.Context, def *models.ItemDefinition) (uuid.UUID, error) {
+	const op = "Storage.SaveItemDefinition"
+
+	q := `
+		INSERT INTO item_definitions (
+			name,
+			rarity,
+			min_float,
+			max_float
+		)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4
+		)
+		RETURNING id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var id uuid.UUID
+
+	if err := s.writer(ctx).QueryRow(ctx, q, def.Name, def.Rarity, def.MinFloat, def.MaxFloat).Scan(&id); err != nil {
+		if isPgCode(err, codeUniqueViolation) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionExists)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetItemDefinition(ctx context.Context, definitionID uuid.UUID) (*models.ItemDefinition, error) {
+	const op = "Storage.GetItemDefinition"
+
+	q := `
+		SELECT
+			id,
+			name,
+			rarity,
+			min_float,
+			max_float,
+			created_at
+		FROM item_definitions
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var def models.ItemDefinition
+	if err := s.reader(ctx).QueryRow(ctx, q, definitionID).Scan(
+		&def.DefinitionId,
+		&def.Name,
+		&def.Rarity,
+		&def.MinFloat,
+		&def.MaxFloat,
+		&def.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.ItemDefinition{}, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return &models.ItemDefinition{}, pgError(op, err)
+	}
+
+	return &def, nil
+}
+
+func (s *Storage) GetAllItemDefinitions(ctx context.Context) ([]*models.ItemDefinition, error) {
+	const op = "Storage.GetAllItemDefinitions"
+
+	q := `
+		SELECT
+			id,
+			name,
+			rarity,
+			min_float,
+			max_float,
+			created_at
+		FROM item_definitions
+		ORDER BY name, rarity
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q)
+	if err != nil {
+		return []*models.ItemDefinition{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	defs := make([]*models.ItemDefinition, 0)
+
+	for rows.Next() {
+		var def models.ItemDefinition
+
+		if err := rows.Scan(&def.DefinitionId, &def.Name, &def.Rarity, &def.MinFloat, &def.MaxFloat, &def.CreatedAt); err != nil {
+			return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		defs = append(defs, &def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return defs, nil
+}
+
+func (s *Storage) DeleteItemDefinition(ctx context.Context, definitionID uuid.UUID) error {
+	const op = "Storage.DeleteItemDefinition"
+
+	q := `
+		DELETE FROM item_definitions
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, definitionID)
+	if err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionInUse)
+		}
+
+		return pgError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	}
+
+	return nil
+}
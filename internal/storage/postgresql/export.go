@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+)
+
+// ExportItems calls fn with every item matching the filter, in the order
+// of GetAllItems. The items are read through a server-side cursor, batch
+// items at a time, in a read-only transaction that sees one snapshot of
+// the items however long the export takes.
+func (s *Storage) ExportItems(ctx context.Context, filter models.ItemFilter, batch int, fn func(item *models.Item) error) error {
+	const op = "Storage.ExportItems"
+
+	q, args := itemsQuery(filter)
+
+	err := s.withSnapshotTx(ctx, func(tx pgx.Tx) error {
+		declare := "DECLARE export_items NO SCROLL CURSOR FOR " + q
+
+		done := s.logQuery(ctx, op, declare)
+		_, err := tx.Exec(ctx, declare, args...)
+		done()
+		if err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_items", batch)
+		for {
+			n, err := s.fetchItems(ctx, tx, fetch, fn)
+			if err != nil {
+				return err
+			}
+
+			if n < batch {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// fetchItems runs a FETCH of the export cursor, calls fn with each item
+// and returns the number of items fetched.
+func (s *Storage) fetchItems(ctx context.Context, tx pgx.Tx, fetch string, fn func(item *models.Item) error) (int, error) {
+	const op = "Storage.fetchItems"
+
+	defer s.logQuery(ctx, op, fetch)()
+
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return n, err
+		}
+		n++
+
+		if err := fn(item); err != nil {
+			return n, err
+		}
+	}
+
+	return n, rows.Err()
+}
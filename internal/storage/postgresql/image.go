@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveItemImage sets the image of the definition, replacing the previous
+// one. It fills in the upload time of img.
+func (s *Storage) SaveItemImage(ctx context.Context, img *models.ItemImage) error {
+	const op = "Storage.SaveItemImage"
+
+	q := `
+		INSERT INTO item_images (
+			definition_id,
+			hash,
+			content_type,
+			width,
+			height,
+			size_bytes,
+			thumbnail_sizes
+		)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7
+		)
+		ON CONFLICT (definition_id) DO UPDATE
+		SET
+			hash = EXCLUDED.hash,
+			content_type = EXCLUDED.content_type,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			size_bytes = EXCLUDED.size_bytes,
+			thumbnail_sizes = EXCLUDED.thumbnail_sizes,
+			uploaded_at = now()
+		RETURNING uploaded_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if err := s.writer(ctx).QueryRow(
+		ctx, q,
+		img.DefinitionId, img.Hash, img.ContentType, img.Width, img.Height, img.Size, img.ThumbnailSizes,
+	).Scan(&img.UploadedAt); err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// GetItemImages returns the images of the definitions that have one.
+func (s *Storage) GetItemImages(ctx context.Context, definitionIDs []uuid.UUID) (map[uuid.UUID]*models.ItemImage, error) {
+	const op = "Storage.GetItemImages"
+
+	q := `
+		SELECT
+			definition_id,
+			hash,
+			content_type,
+			width,
+			height,
+			size_bytes,
+			thumbnail_sizes,
+			uploaded_at
+		FROM item_images
+		WHERE definition_id = ANY($1)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, definitionIDs)
+	if err != nil {
+		return map[uuid.UUID]*models.ItemImage{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	images := make(map[uuid.UUID]*models.ItemImage)
+
+	for rows.Next() {
+		var img models.ItemImage
+
+		if err := rows.Scan(
+			&img.DefinitionId,
+			&img.Hash,
+			&img.ContentType,
+			&img.Width,
+			&img.Height,
+			&img.Size,
+			&img.ThumbnailSizes,
+			&img.UploadedAt,
+		); err != nil {
+			return map[uuid.UUID]*models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		images[img.DefinitionId] = &img
+	}
+
+	if err := rows.Err(); err != nil {
+		return map[uuid.UUID]*models.ItemImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return images, nil
+}
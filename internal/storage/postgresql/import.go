@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+)
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// importColumns are the columns of items written by ImportItems.
+var importColumns = []string{
+	"id",
+	"definition_id",
+	"quality",
+	"float_value",
+	"pattern_seed",
+	"status",
+	"attributes",
+	"tags",
+}
+
+type definitionKey struct {
+	name   string
+	rarity string
+}
+
+// ImportItems saves the items in one transaction, creating their
+// definitions on first use like SaveItem, and copies them into items with
+// COPY. The returned IDs are in the order of the items; items whose float
+// is outside of their definition's range are not saved and get uuid.Nil.
+// A dry run rolls everything back but returns the same result.
+func (s *Storage) ImportItems(ctx context.Context, items []*models.Item, dryRun bool) ([]uuid.UUID, error) {
+	const op = "Storage.ImportItems"
+
+	ids := make([]uuid.UUID, len(items))
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		defs, err := s.upsertDefinitions(ctx, tx, items)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]any, 0, len(items))
+		for i, item := range items {
+			def := defs[definitionKey{item.Name, item.Rarity}]
+			if item.Float < def.MinFloat || item.Float > def.MaxFloat {
+				continue
+			}
+
+			status := item.Status
+			if status == "" {
+				status = models.ItemStatusPublished
+			}
+
+			tags := item.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+
+			ids[i] = uuid.New()
+			rows = append(rows, []any{
+				ids[i],
+				def.DefinitionId,
+				item.Quality,
+				item.Float,
+				item.PatternSeed,
+				string(status),
+				attributesArg(item.Attributes),
+				tags,
+			})
+		}
+
+		done := s.logQuery(ctx, op, "COPY items FROM STDIN")
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"items"}, importColumns, pgx.CopyFromRows(rows))
+		done()
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, pgError(op, err)
+	}
+
+	return ids, nil
+}
+
+// upsertDefinitions creates the missing definitions of the items and
+// returns the definitions of all of them by name and rarity.
+func (s *Storage) upsertDefinitions(ctx context.Context, tx pgx.Tx, items []*models.Item) (map[definitionKey]*models.ItemDefinition, error) {
+	const op = "Storage.upsertDefinitions"
+
+	// A row may be upserted only once per statement.
+	var names, rarities []string
+	seen := make(map[definitionKey]bool)
+	for _, item := range items {
+		key := definitionKey{item.Name, item.Rarity}
+		if !seen[key] {
+			seen[key] = true
+			names = append(names, item.Name)
+			rarities = append(rarities, item.Rarity)
+		}
+	}
+
+	q := `
+		INSERT INTO item_definitions (
+			name,
+			rarity
+		)
+		SELECT * FROM unnest($1::text[], $2::text[])
+		ON CONFLICT (name, rarity) DO UPDATE
+		SET name = EXCLUDED.name
+		RETURNING id, name, rarity, min_float, max_float
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := tx.Query(ctx, q, names, rarities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	defs := make(map[definitionKey]*models.ItemDefinition, len(names))
+	for rows.Next() {
+		var def models.ItemDefinition
+		if err := rows.Scan(&def.DefinitionId, &def.Name, &def.Rarity, &def.MinFloat, &def.MaxFloat); err != nil {
+			return nil, err
+		}
+
+		defs[definitionKey{def.Name, def.Rarity}] = &def
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(defs) != len(names) {
+		return nil, fmt.Errorf("%s: got %d of %d definitions", op, len(defs), len(names))
+	}
+
+	return defs, nil
+}
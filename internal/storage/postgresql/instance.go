@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveItemInstance creates a copy of the definition. When inst.OwnerId is set
+// the item is created in that owner's inventory and the ownership is recorded.
+// It fails with storage.ErrFloatOutOfRange if the float is outside of the
+// definition's range.
+func (s *Storage) SaveItemInstance(ctx context.Context, inst *models.ItemInstance) (uuid.UUID, error) {
+	const op = "Storage.SaveItemInstance"
+
+	q := `
+		WITH definition AS (
+			SELECT
+				id,
+				$4::double precision BETWEEN min_float AND max_float AS in_range
+			FROM item_definitions
+			WHERE id = $1
+		), item AS (
+			INSERT INTO items (
+				id,
+				definition_id,
+				owner_id,
+				quality,
+				float_value,
+				pattern_seed,
+				attributes
+			)
+			SELECT
+				gen_random_uuid(),
+				id,
+				$2,
+				$3,
+				$4,
+				$5,
+				$6
+			FROM definition
+			WHERE in_range
+			RETURNING id, owner_id
+		), history AS (
+			INSERT INTO ownership_history (
+				item_id,
+				to_owner
+			)
+			SELECT id, owner_id
+			FROM item
+			WHERE owner_id IS NOT NULL
+		)
+		SELECT
+			(SELECT in_range FROM definition),
+			(SELECT id FROM item)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var (
+		inRange *bool
+		id      *uuid.UUID
+	)
+
+	if err := s.writer(ctx).QueryRow(
+		ctx, q, inst.DefinitionId, nullUUID(inst.OwnerId), inst.Quality, inst.Float, inst.PatternSeed, attributesArg(inst.Attributes),
+	).Scan(&inRange, &id); err != nil {
+		return uuid.Nil, pgError(op, err)
+	}
+
+	switch {
+	case inRange == nil:
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+	case !*inRange:
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrFloatOutOfRange)
+	}
+
+	return *id, nil
+}
+
+func (s *Storage) GetItemInstance(ctx context.Context, instanceID uuid.UUID) (*models.ItemInstance, error) {
+	const op = "Storage.GetItemInstance"
+
+	q := `
+		SELECT
+			id,
+			definition_id,
+			owner_id,
+			quality,
+			float_value,
+			pattern_seed,
+			attributes,
+			tags,
+			status,
+			created_at,
+			consumed_at
+		FROM items
+		WHERE id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	inst, err := scanItemInstance(s.reader(ctx).QueryRow(ctx, q, instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.ItemInstance{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+
+		return &models.ItemInstance{}, pgError(op, err)
+	}
+
+	return inst, nil
+}
+
+func (s *Storage) GetItemInstances(ctx context.Context, definitionID uuid.UUID) ([]*models.ItemInstance, error) {
+	const op = "Storage.GetItemInstances"
+
+	q := `
+		SELECT
+			id,
+			definition_id,
+			owner_id,
+			quality,
+			float_value,
+			pattern_seed,
+			attributes,
+			tags,
+			status,
+			created_at,
+			consumed_at
+		FROM items
+		WHERE definition_id = $1
+		ORDER BY created_at, id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, definitionID)
+	if err != nil {
+		return []*models.ItemInstance{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	instances := make([]*models.ItemInstance, 0)
+
+	for rows.Next() {
+		inst, err := scanItemInstance(rows)
+		if err != nil {
+			return []*models.ItemInstance{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		instances = append(instances, inst)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemInstance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return instances, nil
+}
+
+// scanItemInstance scans a row of instance columns selected in the order of
+// the models.ItemInstance fields and derives the exterior.
+func scanItemInstance(row pgx.Row) (*models.ItemInstance, error) {
+	var (
+		inst       models.ItemInstance
+		consumedAt *time.Time
+	)
+
+	if err := row.Scan(
+		&inst.InstanceId,
+		&inst.DefinitionId,
+		&inst.OwnerId,
+		&inst.Quality,
+		&inst.Float,
+		&inst.PatternSeed,
+		&inst.Attributes,
+		&inst.Tags,
+		&inst.Status,
+		&inst.CreatedAt,
+		&consumedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if consumedAt != nil {
+		inst.ConsumedAt = *consumedAt
+	}
+
+	inst.Exterior = models.ExteriorOf(inst.Float)
+
+	return &inst, nil
+}
@@ -0,0 +1,251 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// GetInventory returns up to q.PageSize items of the owner ordered by ID, starting after q.After.
+func (s *Storage) GetInventory(ctx context.Context, iq models.InventoryQuery) ([]*models.Item, error) {
+	const op = "Storage.GetInventory"
+
+	where := []string{"i.owner_id = $1"}
+	args := []any{iq.OwnerId}
+
+	if iq.DefinitionId != uuid.Nil {
+		args = append(args, iq.DefinitionId)
+		where = append(where, fmt.Sprintf("i.definition_id = $%d", len(args)))
+	}
+	if iq.Rarity != "" {
+		args = append(args, iq.Rarity)
+		where = append(where, fmt.Sprintf("d.rarity = $%d", len(args)))
+	}
+	if iq.Quality != "" {
+		args = append(args, iq.Quality)
+		where = append(where, fmt.Sprintf("i.quality = $%d", len(args)))
+	}
+	if len(iq.Statuses) > 0 {
+		args = append(args, statusStrings(iq.Statuses))
+		where = append(where, fmt.Sprintf("i.status = ANY($%d)", len(args)))
+	}
+	if iq.After != uuid.Nil {
+		args = append(args, iq.After)
+		where = append(where, fmt.Sprintf("i.id > $%d", len(args)))
+	}
+
+	args = append(args, iq.PageSize)
+
+	q := `
+		SELECT
+			i.id,
+			i.definition_id,
+			i.owner_id,
+			d.name,
+			d.rarity,
+			i.quality,
+			i.float_value,
+			i.pattern_seed,
+			i.attributes,
+			i.tags,
+			i.status
+		FROM items i
+		JOIN item_definitions d ON d.id = i.definition_id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY i.id
+		LIMIT $` + fmt.Sprint(len(args))
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return []*models.Item{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*models.Item, 0, iq.PageSize)
+
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// TransferItem moves the item from one owner to another and records the transfer.
+// It fails with storage.ErrNotOwner if the item is not held by from, with
+// storage.ErrItemNotTradable if it is not published and with
+// storage.ErrItemLocked if it has an active lock. A non-zero holdUntil locks
+// the item for its new owner until then.
+func (s *Storage) TransferItem(ctx context.Context, itemID, from, to uuid.UUID, holdUntil time.Time) (*models.OwnershipTransfer, error) {
+	const op = "Storage.TransferItem"
+
+	transfer := models.OwnershipTransfer{
+		ItemId:    itemID,
+		FromOwner: from,
+		ToOwner:   to,
+	}
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		return s.transferItem(ctx, tx, &transfer, holdUntil)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrItemNotFound),
+			errors.Is(err, storage.ErrNotOwner),
+			errors.Is(err, storage.ErrItemNotTradable),
+			errors.Is(err, storage.ErrItemLocked):
+			return &models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return &models.OwnershipTransfer{}, pgError(op, err)
+	}
+
+	return &transfer, nil
+}
+
+// transferItem changes the owner of t.ItemId within tx and fills in the
+// transfer ID and time. Only published items without active locks can
+// change hands. A non-zero holdUntil puts a trade hold on the item.
+func (s *Storage) transferItem(ctx context.Context, tx pgx.Tx, t *models.OwnershipTransfer, holdUntil time.Time) error {
+	const op = "Storage.transferItem"
+
+	q := `
+		SELECT
+			i.owner_id,
+			i.status,
+			EXISTS (
+				SELECT 1
+				FROM item_locks l
+				WHERE l.item_id = i.id
+				AND ` + activeLock + `
+			)
+		FROM items i
+		WHERE i.id = $1
+		FOR UPDATE OF i
+	`
+	done := s.logQuery(ctx, op, q)
+
+	var (
+		owner  uuid.UUID
+		status models.ItemStatus
+		locked bool
+	)
+	err := tx.QueryRow(ctx, q, t.ItemId).Scan(&owner, &status, &locked)
+	done()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrItemNotFound
+		}
+
+		return err
+	}
+
+	if owner != t.FromOwner {
+		return storage.ErrNotOwner
+	}
+
+	if status != models.ItemStatusPublished {
+		return storage.ErrItemNotTradable
+	}
+
+	if locked {
+		return storage.ErrItemLocked
+	}
+
+	q = `
+		UPDATE items
+		SET owner_id = $2
+		WHERE id = $1
+	`
+	done = s.logQuery(ctx, op, q)
+	_, err = tx.Exec(ctx, q, t.ItemId, t.ToOwner)
+	done()
+	if err != nil {
+		return err
+	}
+
+	q = `
+		INSERT INTO ownership_history (
+			item_id,
+			from_owner,
+			to_owner
+		)
+		VALUES (
+			$1,
+			$2,
+			$3
+		)
+		RETURNING id, transferred_at
+	`
+	done = s.logQuery(ctx, op, q)
+	err = tx.QueryRow(ctx, q, t.ItemId, nullUUID(t.FromOwner), t.ToOwner).Scan(&t.TransferId, &t.TransferredAt)
+	done()
+	if err != nil || holdUntil.IsZero() {
+		return err
+	}
+
+	return s.insertItemLock(ctx, tx, &models.ItemLock{
+		ItemId:    t.ItemId,
+		Reason:    models.LockReasonTradeHold,
+		ExpiresAt: holdUntil,
+	})
+}
+
+// GetOwnershipHistory returns the transfers of the item, oldest first.
+func (s *Storage) GetOwnershipHistory(ctx context.Context, itemID uuid.UUID) ([]*models.OwnershipTransfer, error) {
+	const op = "Storage.GetOwnershipHistory"
+
+	q := `
+		SELECT
+			id,
+			item_id,
+			from_owner,
+			to_owner,
+			transferred_at
+		FROM ownership_history
+		WHERE item_id = $1
+		ORDER BY transferred_at, id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, itemID)
+	if err != nil {
+		return []*models.OwnershipTransfer{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	transfers := make([]*models.OwnershipTransfer, 0)
+
+	for rows.Next() {
+		var t models.OwnershipTransfer
+
+		if err := rows.Scan(&t.TransferId, &t.ItemId, &t.FromOwner, &t.ToOwner, &t.TransferredAt); err != nil {
+			return []*models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		transfers = append(transfers, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.OwnershipTransfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// activeLock is the condition of a lock l being in effect.
+const activeLock = `(l.expires_at IS NULL OR l.expires_at > now())`
+
+// SaveItemLock locks the item and fills in the lock ID and creation time.
+func (s *Storage) SaveItemLock(ctx context.Context, lock *models.ItemLock) (uuid.UUID, error) {
+	const op = "Storage.SaveItemLock"
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		return s.insertItemLock(ctx, tx, lock)
+	})
+	if err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return lock.LockId, nil
+}
+
+// insertItemLock saves the lock within tx.
+func (s *Storage) insertItemLock(ctx context.Context, tx pgx.Tx, lock *models.ItemLock) error {
+	const op = "Storage.insertItemLock"
+
+	q := `
+		INSERT INTO item_locks (
+			item_id,
+			reason,
+			expires_at
+		)
+		VALUES (
+			$1,
+			$2,
+			$3
+		)
+		RETURNING id, created_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	return tx.QueryRow(ctx, q, lock.ItemId, lock.Reason, nullTime(lock.ExpiresAt)).Scan(&lock.LockId, &lock.CreatedAt)
+}
+
+// DeleteItemLock removes the lock.
+func (s *Storage) DeleteItemLock(ctx context.Context, lockID uuid.UUID) error {
+	const op = "Storage.DeleteItemLock"
+
+	q := `DELETE FROM item_locks WHERE id = $1`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, lockID)
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrLockNotFound)
+	}
+
+	return nil
+}
+
+// GetItemLocks returns the active locks of the items, ordered by item and
+// creation time.
+func (s *Storage) GetItemLocks(ctx context.Context, itemIDs []uuid.UUID) ([]*models.ItemLock, error) {
+	const op = "Storage.GetItemLocks"
+
+	q := `
+		SELECT
+			l.id,
+			l.item_id,
+			l.reason,
+			l.expires_at,
+			l.created_at
+		FROM item_locks l
+		WHERE l.item_id = ANY($1)
+		AND ` + activeLock + `
+		ORDER BY l.item_id, l.created_at, l.id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	// Locks are checked before writes, so they are read from the primary.
+	rows, err := s.primary().Query(ctx, q, itemIDs)
+	if err != nil {
+		return []*models.ItemLock{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	locks := []*models.ItemLock{}
+	for rows.Next() {
+		var (
+			lock      models.ItemLock
+			expiresAt *time.Time
+		)
+		if err := rows.Scan(&lock.LockId, &lock.ItemId, &lock.Reason, &expiresAt, &lock.CreatedAt); err != nil {
+			return []*models.ItemLock{}, pgError(op, err)
+		}
+
+		if expiresAt != nil {
+			lock.ExpiresAt = *expiresAt
+		}
+
+		locks = append(locks, &lock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemLock{}, pgError(op, err)
+	}
+
+	return locks, nil
+}
+
+// ReleaseTradeHolds publishes at most limit locked items without a trade
+// hold lock in effect at now and returns how many were published.
+func (s *Storage) ReleaseTradeHolds(ctx context.Context, now time.Time, limit int) (int, error) {
+	const op = "Storage.ReleaseTradeHolds"
+
+	q := `
+		UPDATE items
+		SET status = 'published'
+		WHERE id IN (
+			SELECT i.id
+			FROM items i
+			WHERE i.status = 'locked'
+			AND NOT EXISTS (
+				SELECT 1
+				FROM item_locks l
+				WHERE l.item_id = i.id
+				AND l.reason = $2
+				AND (l.expires_at IS NULL OR l.expires_at > $1)
+			)
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		AND status = 'locked'
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, now, models.LockReasonTradeHold, limit)
+	if err != nil {
+		return 0, pgError(op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteExpiredLocks removes at most limit locks that expired at now and
+// returns how many were removed.
+func (s *Storage) DeleteExpiredLocks(ctx context.Context, now time.Time, limit int) (int, error) {
+	const op = "Storage.DeleteExpiredLocks"
+
+	q := `
+		DELETE FROM item_locks
+		WHERE id IN (
+			SELECT id
+			FROM item_locks
+			WHERE expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+		)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, now, limit)
+	if err != nil {
+		return 0, pgError(op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -8,65 +8,135 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 
 	"item-service/internal/config"
 	"item-service/internal/domain/models"
+	"item-service/internal/storage"
 	"item-service/pkg/client/postgresql"
-
 )
 
 type Storage struct {
-	client postgresql.Client
-	log    *slog.Logger
+	client   postgresql.Client
+	router   *router
+	log      *slog.Logger
+	queryLog config.QueryLogConfig
 }
 
-func New(log *slog.Logger) *Storage {
-	cfg := config.MustLoad()
-	client, err := postgresql.NewClient(context.TODO(), 3, cfg.Storage)
+// New connects to the PostgreSQL primary and its read replicas and returns the storage.
+// Replicas that are unavailable at startup are used once they become healthy.
+func New(ctx context.Context, log *slog.Logger, cfg config.StorageConfig) (*Storage, error) {
+	const op = "Storage.New"
+
+	client, err := postgresql.NewClient(ctx, cfg.ConnectAttempts, cfg)
 	if err != nil {
-		log.Info("Failed to connect to PostgreSQL: %v", err)
-		return &Storage{}
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	log.Info("connected to PostgreSQL")
 
-	return &Storage{
-		client: client,
-		log:    log,
+	r := &router{readYourWrites: cfg.ReadYourWrites}
+
+	for i, dsn := range cfg.Replicas {
+		pool, err := postgresql.NewPool(ctx, cfg.ReplicaConfig(dsn))
+		if err != nil {
+			r.close()
+			client.Close()
+
+			return nil, fmt.Errorf("%s: replica %d: %w", op, i, err)
+		}
+
+		r.replicas = append(r.replicas, &replica{client: pool})
+	}
+
+	if len(r.replicas) > 0 {
+		r.pingReplicas(log, cfg.ConnectTimeout)
+
+		r.stop, r.done = make(chan struct{}), make(chan struct{})
+		go r.checkReplicas(log, cfg.ReplicaCheckPeriod, cfg.ConnectTimeout)
+
+		log.Info("using read replicas", slog.Int("replicas", len(r.replicas)))
 	}
+
+	return &Storage{
+		client:   client,
+		router:   r,
+		log:      log,
+		queryLog: cfg.QueryLog,
+	}, nil
+}
+
+// Close closes all connections of the storage.
+func (s *Storage) Close() {
+	s.router.close()
+	s.client.Close()
 }
 
-func (s *Storage) SaveItem(ctx context.Context, name string, rarity string, quality string) (uuid.UUID, error) {
+// SaveItem creates an item together with its definition, if there is none
+// for the name and rarity yet. It fails with storage.ErrFloatOutOfRange if
+// the float is outside of the definition's range, without creating the
+// definition.
+func (s *Storage) SaveItem(ctx context.Context, item *models.Item) (uuid.UUID, error) {
 	const op = "Storage.SaveItem"
 
+	// The definition is created on first use, so that every item keeps
+	// pointing to a single catalogue entry per name and rarity.
 	q := `
+		WITH definition AS (
+			INSERT INTO item_definitions (
+				name,
+				rarity
+			)
+			VALUES (
+				$1,
+				$2
+			)
+			ON CONFLICT (name, rarity) DO UPDATE
+			SET name = EXCLUDED.name
+			RETURNING id, min_float, max_float
+		)
 		INSERT INTO items (
-			id, 
-			name, 
-			rarity, 
-			quality
+			id,
+			definition_id,
+			quality,
+			float_value,
+			pattern_seed,
+			status
 		)
-		VALUES (
-			gen_random_uuid(), 
-			$1, 
-			$2, 
-			$3)
+		SELECT
+			gen_random_uuid(),
+			id,
+			$3,
+			$4,
+			$5,
+			COALESCE(NULLIF($6, ''), 'published')
+		FROM definition
+		WHERE $4 BETWEEN min_float AND max_float
 		RETURNING id
 	`
-	s.log.Info(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
 
 	var id uuid.UUID
 
-	if err := s.client.QueryRow(ctx, q, name, rarity, quality).Scan(&id); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.Is(err, pgErr) {
-			pgErr = err.(*pgconn.PgError)
-			newErr := fmt.Errorf(fmt.Sprintf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s, op: %s", pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState(), op))
-			return uuid.Nil, newErr
+	// No item is inserted if the float is out of range; the transaction is
+	// rolled back then, so that the definition is not created either.
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		defer s.logQuery(ctx, op, q)()
+
+		err := tx.QueryRow(
+			ctx, q, item.Name, item.Rarity, item.Quality, item.Float, item.PatternSeed, string(item.Status),
+		).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrFloatOutOfRange
 		}
 
-		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrFloatOutOfRange) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+		}
 
+		return uuid.Nil, pgError(op, err)
 	}
 
 	s.log.Info("Completed to create item")
@@ -78,67 +148,61 @@ func (s *Storage) GetItem(ctx context.Context, itemID uuid.UUID) (*models.Item,
 	const op = "Storage.GetItem"
 
 	q := `
-        SELECT 
-			id, 
-			name, 
-			rarity, 
-			quality 
-		FROM items
-		WHERE id = $1
+		SELECT
+			i.id,
+			i.definition_id,
+			i.owner_id,
+			d.name,
+			d.rarity,
+			i.quality,
+			i.float_value,
+			i.pattern_seed,
+			i.attributes,
+			i.tags,
+			i.status
+		FROM items i
+		JOIN item_definitions d ON d.id = i.definition_id
+		WHERE i.id = $1
 	`
-	s.log.Info(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+	defer s.logQuery(ctx, op, q)()
 
-	var item models.Item
-	if err := s.client.QueryRow(ctx, q, itemID).Scan(&item.ItemId, &item.Name, &item.Rarity, &item.Quality); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.Is(err, pgErr) {
-			pgErr = err.(*pgconn.PgError)
-			newErr := fmt.Errorf(fmt.Sprintf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s, op: %s", pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState(), op))
-			return &models.Item{}, newErr
+	item, err := scanItem(s.reader(ctx).QueryRow(ctx, q, itemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.Item{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
 		}
 
-		return &models.Item{}, fmt.Errorf("%s: %w", op, err)
+		return &models.Item{}, pgError(op, err)
 	}
 
 	s.log.Info("Completed to get user by id")
 
-	return &item, nil
+	return item, nil
 }
 
-func (s *Storage) GetAllItems(ctx context.Context) ([]*models.Item, error) {
+// GetAllItems returns the items matching the filter. Items consumed by
+// trade-up contracts are left out.
+func (s *Storage) GetAllItems(ctx context.Context, filter models.ItemFilter) ([]*models.Item, error) {
 	const op = "Storage.GetAllItems"
 
-	q := `
-        SELECT 
-			id, 
-			name, 
-			rarity, 
-			quality 
-		FROM items
-	`
-	s.log.Info(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
-	rows, err := s.client.Query(ctx, q)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.Is(err, pgErr) {
-			pgErr = err.(*pgconn.PgError)
-			newErr := fmt.Errorf(fmt.Sprintf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s, op: %s", pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState(), op))
-			return []*models.Item{}, newErr
-		}
+	q, args := itemsQuery(filter)
+	defer s.logQuery(ctx, op, q)()
 
-		return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
+	rows, err := s.reader(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return []*models.Item{}, pgError(op, err)
 	}
+	defer rows.Close()
 
 	items := make([]*models.Item, 0)
 
 	for rows.Next() {
-		var item models.Item
-
-		if err := rows.Scan(&item.ItemId, &item.Name, &item.Rarity, &item.Quality); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return []*models.Item{}, fmt.Errorf("%s: %w", op, err)
 		}
 
-		items = append(items, &item)
+		items = append(items, item)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -148,30 +212,215 @@ func (s *Storage) GetAllItems(ctx context.Context) ([]*models.Item, error) {
 	return items, nil
 }
 
+// DeleteItem deletes the item. It fails with storage.ErrItemLocked if the
+// item has an active lock.
 func (s *Storage) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
 	const op = "Storage.DeleteItem"
 
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		// Locking the row keeps new locks of the item from being saved
+		// until the delete is done.
+		q := `SELECT 1 FROM items WHERE id = $1 FOR UPDATE`
+		done := s.logQuery(ctx, op, q)
+
+		var found int
+		err := tx.QueryRow(ctx, q, itemID).Scan(&found)
+		done()
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return storage.ErrItemNotFound
+			}
+
+			return err
+		}
+
+		q = `
+			DELETE FROM items i
+			WHERE i.id = $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM item_locks l
+				WHERE l.item_id = i.id
+				AND ` + activeLock + `
+			)
+		`
+		done = s.logQuery(ctx, op, q)
+		tag, err := tx.Exec(ctx, q, itemID)
+		done()
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return storage.ErrItemLocked
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) || errors.Is(err, storage.ErrItemLocked) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// scanItem scans a row of item columns selected in the order of the
+// models.Item fields and derives the exterior.
+func scanItem(row pgx.Row) (*models.Item, error) {
+	var item models.Item
+
+	if err := row.Scan(
+		&item.ItemId,
+		&item.DefinitionId,
+		&item.OwnerId,
+		&item.Name,
+		&item.Rarity,
+		&item.Quality,
+		&item.Float,
+		&item.PatternSeed,
+		&item.Attributes,
+		&item.Tags,
+		&item.Status,
+	); err != nil {
+		return nil, err
+	}
+
+	item.Exterior = models.ExteriorOf(item.Float)
+
+	return &item, nil
+}
+
+// itemsQuery returns the query of the items matching the filter, selecting
+// the columns read by scanItem.
+func itemsQuery(filter models.ItemFilter) (string, []any) {
+	where, args := itemFilterWhere(filter)
+
 	q := `
-		DELETE FROM items
-		WHERE id = $1
+		SELECT
+			i.id,
+			i.definition_id,
+			i.owner_id,
+			d.name,
+			d.rarity,
+			i.quality,
+			i.float_value,
+			i.pattern_seed,
+			i.attributes,
+			i.tags,
+			i.status
+		FROM items i
+		JOIN item_definitions d ON d.id = i.definition_id
 	`
-	s.log.Info(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+	q += "WHERE " + strings.Join(where, " AND ") + "\n"
+	q += "ORDER BY " + itemOrder(filter.Sort)
 
-	if _, err := s.client.Exec(ctx, q, itemID); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.Is(err, pgErr) {
-			pgErr = err.(*pgconn.PgError)
-			newErr := fmt.Errorf(fmt.Sprintf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s, op: %s", pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState(), op))
+	return q, args
+}
 
-			return newErr
+// itemFilterWhere returns the conditions items must meet to match the
+// filter and their arguments. Items are aliased i; consumed items never match.
+func itemFilterWhere(filter models.ItemFilter) ([]string, []any) {
+	var (
+		where = []string{"i.consumed_at IS NULL"}
+		args  []any
+	)
+
+	if filter.MinFloat != nil {
+		args = append(args, *filter.MinFloat)
+		where = append(where, fmt.Sprintf("i.float_value >= $%d", len(args)))
+	}
+	if filter.MaxFloat != nil {
+		args = append(args, *filter.MaxFloat)
+		where = append(where, fmt.Sprintf("i.float_value <= $%d", len(args)))
+	}
+	if filter.CollectionId != uuid.Nil {
+		args = append(args, filter.CollectionId)
+		where = append(where, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM collection_items ci WHERE ci.collection_id = $%d AND ci.definition_id = i.definition_id)",
+			len(args),
+		))
+	}
+	if contains := attributesFilter(filter); contains != nil {
+		args = append(args, contains)
+		where = append(where, fmt.Sprintf("i.attributes @> $%d::jsonb", len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		if filter.TagMatch == models.TagMatchAny {
+			where = append(where, fmt.Sprintf("i.tags && $%d::text[]", len(args)))
+		} else {
+			where = append(where, fmt.Sprintf("i.tags @> $%d::text[]", len(args)))
 		}
+	}
+	if len(filter.Statuses) > 0 {
+		args = append(args, statusStrings(filter.Statuses))
+		where = append(where, fmt.Sprintf("i.status = ANY($%d)", len(args)))
+	}
 
-		return fmt.Errorf("%s: %w", op, err)
+	return where, args
+}
+
+// itemOrder returns the ORDER BY clause of items for the sort.
+func itemOrder(sort models.ItemSort) string {
+	switch sort {
+	case models.ItemSortFloatAsc:
+		return "i.float_value, i.id"
+	case models.ItemSortFloatDesc:
+		return "i.float_value DESC, i.id"
 	}
 
-	return nil
+	return "i.id"
+}
+
+// PostgreSQL error codes handled by the storage.
+const (
+	codeUniqueViolation      = "23505"
+	codeForeignKeyViolation  = "23503"
+	codeCheckViolation       = "23514"
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+	codeUndefinedTable       = "42P01"
+)
+
+// pgError wraps err with op and the details of the PostgreSQL error, if any.
+func pgError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return fmt.Errorf(
+			"%s: SQL Error: %s, Detail: %s, Where: %s, Code: %s: %w",
+			op, pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, err,
+		)
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
 }
 
-func formatQuery(q string) string {
-	return strings.ReplaceAll(strings.ReplaceAll(q, "\t", ""), "\n", " ")
+// nullUUID returns nil for uuid.Nil so that it is stored as NULL.
+func nullUUID(id uuid.UUID) any {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	return id
+}
+
+// pgConstraint returns the name of the constraint violated by err, if any.
+func pgConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+
+	return ""
+}
+
+// isPgCode reports whether err is a PostgreSQL error with the given code.
+func isPgCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == code
 }
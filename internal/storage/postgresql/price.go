@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SavePriceObservations stores a batch of price observations in one
+// transaction, creating the monthly partitions they fall into. An
+// observation of the same definition, currency, source and time replaces
+// the stored one, so a batch can be recorded again safely.
+func (s *Storage) SavePriceObservations(ctx context.Context, obs []models.PriceObservation) error {
+	const op = "Storage.SavePriceObservations"
+
+	var (
+		definitionIDs = make([]uuid.UUID, 0, len(obs))
+		sources       = make([]string, 0, len(obs))
+		currencies    = make([]string, 0, len(obs))
+		prices        = make([]int64, 0, len(obs))
+		volumes       = make([]int64, 0, len(obs))
+		observedAt    = make([]time.Time, 0, len(obs))
+	)
+	for _, o := range obs {
+		definitionIDs = append(definitionIDs, o.DefinitionId)
+		sources = append(sources, o.Source)
+		currencies = append(currencies, o.Price.Currency)
+		prices = append(prices, o.Price.Amount)
+		volumes = append(volumes, o.Volume)
+		observedAt = append(observedAt, o.ObservedAt)
+	}
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		q := `
+			SELECT ensure_price_partition(month)
+			FROM (
+				SELECT DISTINCT date_trunc('month', t AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS month
+				FROM unnest($1::timestamptz[]) t
+			) months
+		`
+		done := s.logQuery(ctx, op, q)
+		_, err := tx.Exec(ctx, q, observedAt)
+		done()
+		if err != nil {
+			return err
+		}
+
+		q = `
+			INSERT INTO price_observations (
+				definition_id,
+				source,
+				currency,
+				price,
+				volume,
+				observed_at
+			)
+			SELECT *
+			FROM unnest($1::uuid[], $2::text[], $3::text[], $4::bigint[], $5::bigint[], $6::timestamptz[])
+			ON CONFLICT (definition_id, currency, source, observed_at) DO UPDATE
+			SET price = EXCLUDED.price, volume = EXCLUDED.volume
+		`
+		defer s.logQuery(ctx, op, q)()
+
+		_, err = tx.Exec(ctx, q, definitionIDs, sources, currencies, prices, volumes, observedAt)
+
+		return err
+	})
+	if err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// GetPriceHistory returns the OHLC candles of the definition's prices,
+// oldest first. Buckets are aligned to UTC.
+func (s *Storage) GetPriceHistory(ctx context.Context, pq models.PriceHistoryQuery) ([]*models.PriceCandle, error) {
+	const op = "Storage.GetPriceHistory"
+
+	q := `
+		SELECT
+			date_trunc($2, observed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+			(array_agg(price ORDER BY observed_at, source))[1],
+			max(price),
+			min(price),
+			(array_agg(price ORDER BY observed_at DESC, source DESC))[1],
+			sum(volume),
+			count(*)
+		FROM price_observations
+		WHERE definition_id = $1
+		AND currency = $3
+		AND ($4 = '' OR source = $4)
+		AND ($5::timestamptz IS NULL OR observed_at >= $5)
+		AND ($6::timestamptz IS NULL OR observed_at < $6)
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(
+		ctx, q,
+		pq.DefinitionId, string(pq.Interval), pq.Currency, pq.Source, nullTime(pq.From), nullTime(pq.To),
+	)
+	if err != nil {
+		return []*models.PriceCandle{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	candles := make([]*models.PriceCandle, 0)
+
+	for rows.Next() {
+		var c models.PriceCandle
+
+		if err := rows.Scan(&c.Start, &c.Open.Amount, &c.High.Amount, &c.Low.Amount, &c.Close.Amount, &c.Volume, &c.Observations); err != nil {
+			return []*models.PriceCandle{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		c.Open.Currency, c.High.Currency, c.Low.Currency, c.Close.Currency = pq.Currency, pq.Currency, pq.Currency, pq.Currency
+
+		candles = append(candles, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.PriceCandle{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candles, nil
+}
+
+// GetValuationPrices sums up the observations of the definition since the
+// given time per currency, ordered by currency. Observations count by their
+// volume, or once if they have none. Without recent observations the latest
+// one is used. It fails with storage.ErrPriceNotFound if the definition has
+// no prices.
+func (s *Storage) GetValuationPrices(ctx context.Context, definitionID uuid.UUID, since time.Time) ([]models.PriceAggregate, error) {
+	const op = "Storage.GetValuationPrices"
+
+	q := `
+		WITH recent AS (
+			SELECT currency, price, volume, observed_at
+			FROM price_observations
+			WHERE definition_id = $1
+			AND observed_at >= $2
+		), latest AS (
+			SELECT currency, price, volume, observed_at
+			FROM price_observations
+			WHERE definition_id = $1
+			AND NOT EXISTS (SELECT 1 FROM recent)
+			ORDER BY observed_at DESC
+			LIMIT 1
+		), valued AS (
+			SELECT *, coalesce(nullif(volume, 0), 1) AS weight FROM recent
+			UNION ALL
+			SELECT *, coalesce(nullif(volume, 0), 1) AS weight FROM latest
+		)
+		SELECT
+			currency,
+			round(sum(price::numeric * weight) / sum(weight))::bigint,
+			sum(weight),
+			sum(volume),
+			count(*),
+			max(observed_at)
+		FROM valued
+		GROUP BY currency
+		ORDER BY currency
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, definitionID, since)
+	if err != nil {
+		return nil, pgError(op, err)
+	}
+	defer rows.Close()
+
+	var prices []models.PriceAggregate
+	for rows.Next() {
+		var p models.PriceAggregate
+
+		if err := rows.Scan(&p.Price.Currency, &p.Price.Amount, &p.Weight, &p.Volume, &p.Observations, &p.ObservedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPriceNotFound)
+	}
+
+	return prices, nil
+}
+
+// nullTime returns nil for the zero time so that it is passed as NULL.
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"item-service/internal/config"
+)
+
+// logQuery logs q at Debug level according to the configured query log mode.
+// The returned function must be called once the query has completed.
+func (s *Storage) logQuery(ctx context.Context, op, q string) func() {
+	switch s.queryLog.Mode {
+	case config.QueryLogStatement:
+		s.log.DebugContext(ctx, "sql query",
+			slog.String("op", op),
+			slog.String("query", formatQuery(q)),
+		)
+
+		return func() {}
+	case config.QueryLogDuration, config.QueryLogSlow:
+		start := time.Now()
+
+		return func() {
+			elapsed := time.Since(start)
+			if s.queryLog.Mode == config.QueryLogSlow && elapsed < s.queryLog.SlowThreshold {
+				return
+			}
+
+			s.log.DebugContext(ctx, "sql query",
+				slog.String("op", op),
+				slog.String("query", formatQuery(q)),
+				slog.Duration("duration", elapsed),
+			)
+		}
+	default:
+		return func() {}
+	}
+}
+
+func formatQuery(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
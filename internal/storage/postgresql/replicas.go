@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"item-service/internal/lib/logger/sl"
+	"item-service/internal/storage"
+	"item-service/pkg/client/postgresql"
+)
+
+type replica struct {
+	client  postgresql.Client
+	healthy atomic.Bool
+}
+
+// router routes reads to healthy replicas and everything else to the primary.
+type router struct {
+	replicas []*replica
+	next     atomic.Uint64
+
+	readYourWrites time.Duration
+	lastWrites     sync.Map // session id -> time.Time of the last write
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// reader returns the client for read-only queries: a healthy replica in
+// round-robin order, or the primary when the session wrote recently or no
+// replica is healthy.
+func (s *Storage) reader(ctx context.Context) postgresql.Client {
+	r := s.router
+	if len(r.replicas) == 0 || r.wroteRecently(ctx) {
+		return s.client
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep.client
+		}
+	}
+
+	return s.client
+}
+
+// writer returns the primary and remembers the write for read-your-writes routing.
+func (s *Storage) writer(ctx context.Context) postgresql.Client {
+	r := s.router
+	if len(r.replicas) > 0 && r.readYourWrites > 0 {
+		if id, ok := storage.SessionFromContext(ctx); ok {
+			r.lastWrites.Store(id, time.Now())
+		}
+	}
+
+	return s.client
+}
+
+// primary returns the primary for reads that must not lag behind it. Unlike
+// writer, it does not keep the session on the primary afterwards.
+func (s *Storage) primary() postgresql.Client {
+	return s.client
+}
+
+func (r *router) wroteRecently(ctx context.Context) bool {
+	if r.readYourWrites <= 0 {
+		return false
+	}
+
+	id, ok := storage.SessionFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	t, ok := r.lastWrites.Load(id)
+
+	return ok && time.Since(t.(time.Time)) < r.readYourWrites
+}
+
+// checkReplicas pings the replicas every period until stopped and forgets
+// sessions whose read-your-writes window has passed.
+func (r *router) checkReplicas(log *slog.Logger, period, timeout time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		r.pingReplicas(log, timeout)
+
+		r.lastWrites.Range(func(id, t any) bool {
+			if time.Since(t.(time.Time)) >= r.readYourWrites {
+				r.lastWrites.Delete(id)
+			}
+
+			return true
+		})
+	}
+}
+
+func (r *router) pingReplicas(log *slog.Logger, timeout time.Duration) {
+	for i, rep := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := rep.client.Ping(ctx)
+		cancel()
+
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Info("replica is healthy", slog.Int("replica", i))
+			} else {
+				log.Warn("replica is unhealthy, reading from primary", slog.Int("replica", i), sl.Err(err))
+			}
+		}
+	}
+}
+
+func (r *router) close() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+	}
+
+	for _, rep := range r.replicas {
+		rep.client.Close()
+	}
+}
@@ -0,0 +1,343 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/lib/snapshot"
+	"item-service/internal/storage"
+)
+
+// restoreBatch is the number of rows restored with one statement.
+const restoreBatch = 1000
+
+// snapshotTable describes how a table is snapshotted and restored.
+type snapshotTable struct {
+	columns []string
+	order   string
+	// derived are columns computed from the others on restore instead of
+	// being part of snapshots; prepare sets them on every row.
+	derived []string
+	prepare func(row map[string]json.RawMessage) error
+	// before runs ahead of every restored batch.
+	before string
+}
+
+// snapshotTables are the tables of snapshot.Tables. Their columns are those
+// of storage.SchemaVersion; generated columns are left out.
+var snapshotTables = map[string]snapshotTable{
+	"item_definitions": {
+		columns: []string{"id", "name", "rarity", "min_float", "max_float", "created_at"},
+		order:   "id",
+	},
+	"items": {
+		columns: []string{
+			"id", "definition_id", "owner_id", "quality", "float_value", "pattern_seed",
+			"attributes", "tags", "status", "consumed_at", "created_at",
+		},
+		order: "id",
+	},
+	"ownership_history": {
+		columns: []string{"id", "item_id", "from_owner", "to_owner", "transferred_at"},
+		order:   "id",
+	},
+	"trade_offers": {
+		columns: []string{"id", "proposer_id", "recipient_id", "status", "created_at", "expires_at", "resolved_at"},
+		order:   "id",
+	},
+	"trade_offer_items": {
+		columns: []string{"trade_id", "item_id", "owner_id"},
+		order:   "trade_id, item_id",
+	},
+	"collections": {
+		columns: []string{"id", "name", "kind", "description", "created_at"},
+		order:   "id",
+	},
+	"collection_items": {
+		columns: []string{"collection_id", "definition_id", "added_at"},
+		order:   "collection_id, definition_id",
+	},
+	"case_openings": {
+		columns: []string{
+			"id", "collection_id", "owner_id", "item_id", "definition_id", "rarity",
+			"float_value", "pattern_seed", "seed", "drop_table", "pool", "opened_at",
+		},
+		order: "id",
+	},
+	"trade_ups": {
+		columns: []string{
+			"id", "owner_id", "output_item_id", "output_definition_id", "output_rarity",
+			"output_float", "output_pattern_seed", "seed", "created_at",
+		},
+		order: "id",
+	},
+	"trade_up_inputs": {
+		columns: []string{"trade_up_id", "item_id", "definition_id", "float_value"},
+		order:   "trade_up_id, item_id",
+	},
+	"price_observations": {
+		columns: []string{"definition_id", "source", "currency", "price", "volume", "observed_at"},
+		order:   "definition_id, currency, source, observed_at",
+		before: `
+			SELECT ensure_price_partition(month)
+			FROM (
+				SELECT DISTINCT date_trunc('month', observed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS month
+				FROM json_populate_recordset(NULL::price_observations, $1::json)
+			) months
+		`,
+	},
+	"item_images": {
+		columns: []string{
+			"definition_id", "hash", "content_type", "width", "height",
+			"size_bytes", "thumbnail_sizes", "uploaded_at",
+		},
+		order: "definition_id",
+	},
+	"item_definition_translations": {
+		columns: []string{"definition_id", "locale", "name", "description", "updated_at"},
+		order:   "definition_id, locale",
+		// The text search configuration follows from the locale and may
+		// differ between builds, so it is not snapshotted.
+		derived: []string{"search_config"},
+		prepare: func(row map[string]json.RawMessage) error {
+			var locale string
+			if err := json.Unmarshal(row["locale"], &locale); err != nil {
+				return fmt.Errorf("locale: %w", err)
+			}
+
+			cfg, err := json.Marshal(searchConfig(locale))
+			if err != nil {
+				return err
+			}
+			row["search_config"] = cfg
+
+			return nil
+		},
+	},
+	"item_status_schedules": {
+		columns: []string{"item_id", "to_status", "due_at", "created_at"},
+		order:   "item_id",
+	},
+	"item_locks": {
+		columns: []string{"id", "item_id", "reason", "expires_at", "created_at"},
+		order:   "id",
+	},
+}
+
+// SchemaVersion returns the version recorded by the migrations.
+func (s *Storage) SchemaVersion(ctx context.Context) (int, error) {
+	const op = "Storage.SchemaVersion"
+
+	q := `
+		SELECT version
+		FROM schema_version
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var version int
+	if err := s.client.QueryRow(ctx, q).Scan(&version); err != nil {
+		if isPgCode(err, codeUndefinedTable) || errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w: the database has no schema version, apply the migrations", op, storage.ErrSchemaMismatch)
+		}
+
+		return 0, pgError(op, err)
+	}
+
+	return version, nil
+}
+
+// Snapshot writes every row of the snapshot tables to w in one read-only
+// transaction, so the snapshot is consistent even while the service runs.
+func (s *Storage) Snapshot(ctx context.Context, w *snapshot.Writer) error {
+	const op = "Storage.Snapshot"
+
+	err := s.withSnapshotTx(ctx, func(tx pgx.Tx) error {
+		for _, name := range snapshot.Tables {
+			if err := s.snapshotTable(ctx, tx, name, w); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) snapshotTable(ctx context.Context, tx pgx.Tx, name string, w *snapshot.Writer) error {
+	const op = "Storage.snapshotTable"
+
+	t := snapshotTables[name]
+
+	q := fmt.Sprintf(`
+		SELECT row_to_json(t)
+		FROM (
+			SELECT %s
+			FROM %s
+			ORDER BY %s
+		) t
+	`, strings.Join(t.columns, ", "), pgx.Identifier{name}.Sanitize(), t.order)
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := tx.Query(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+
+		if err := w.Write(name, json.RawMessage(row)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Restore writes the rows of r in one transaction. It fails with
+// storage.ErrNotEmpty if any of the snapshot tables has rows.
+func (s *Storage) Restore(ctx context.Context, r *snapshot.Reader) error {
+	const op = "Storage.Restore"
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if err := s.checkEmpty(ctx, tx); err != nil {
+			return err
+		}
+
+		var (
+			table string
+			batch [][]byte
+		)
+		for {
+			name, row, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			if name != table || len(batch) == restoreBatch {
+				if err := s.restoreRows(ctx, tx, table, batch); err != nil {
+					return fmt.Errorf("%s: %w", table, err)
+				}
+				table, batch = name, batch[:0]
+			}
+
+			if prepare := snapshotTables[name].prepare; prepare != nil {
+				if row, err = prepareRow(row, prepare); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			batch = append(batch, row)
+		}
+
+		if err := s.restoreRows(ctx, tx, table, batch); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotEmpty) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// checkEmpty fails with storage.ErrNotEmpty naming the snapshot tables
+// that have rows.
+func (s *Storage) checkEmpty(ctx context.Context, tx pgx.Tx) error {
+	const op = "Storage.checkEmpty"
+
+	var full []string
+	for _, name := range snapshot.Tables {
+		q := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", pgx.Identifier{name}.Sanitize())
+
+		done := s.logQuery(ctx, op, q)
+		var exists bool
+		err := tx.QueryRow(ctx, q).Scan(&exists)
+		done()
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			full = append(full, name)
+		}
+	}
+
+	if len(full) > 0 {
+		return fmt.Errorf("%w: %s have rows", storage.ErrNotEmpty, strings.Join(full, ", "))
+	}
+
+	return nil
+}
+
+// restoreRows inserts a batch of rows of the table, keeping every column
+// as it is in the snapshot.
+func (s *Storage) restoreRows(ctx context.Context, tx pgx.Tx, name string, batch [][]byte) error {
+	const op = "Storage.restoreRows"
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	t := snapshotTables[name]
+	rows := append(append([]byte{'['}, bytes.Join(batch, []byte{','})...), ']')
+
+	if t.before != "" {
+		done := s.logQuery(ctx, op, t.before)
+		_, err := tx.Exec(ctx, t.before, rows)
+		done()
+		if err != nil {
+			return err
+		}
+	}
+
+	columns := strings.Join(append(append([]string(nil), t.columns...), t.derived...), ", ")
+	table := pgx.Identifier{name}.Sanitize()
+
+	q := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		SELECT %s
+		FROM json_populate_recordset(NULL::%s, $1::json)
+	`, table, columns, columns, table)
+	defer s.logQuery(ctx, op, q)()
+
+	_, err := tx.Exec(ctx, q, rows)
+
+	return err
+}
+
+func prepareRow(row json.RawMessage, prepare func(row map[string]json.RawMessage) error) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, err
+	}
+
+	if err := prepare(fields); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
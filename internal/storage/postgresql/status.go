@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// TransitionItem changes the status of the item from from to to, drops its
+// scheduled transition and saves hold, if any. Leaving the locked status
+// removes the trade hold locks of the item. It fails with
+// storage.ErrStatusChanged if the item is no longer in from.
+func (s *Storage) TransitionItem(ctx context.Context, itemID uuid.UUID, from, to models.ItemStatus, hold *models.ItemLock) error {
+	const op = "Storage.TransitionItem"
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		q := `
+			WITH item AS (
+				SELECT id, status
+				FROM items
+				WHERE id = $1
+				FOR UPDATE
+			), updated AS (
+				UPDATE items i
+				SET status = $3
+				FROM item
+				WHERE i.id = item.id
+				AND item.status = $2
+				RETURNING i.id
+			)
+			SELECT item.status, EXISTS (SELECT 1 FROM updated)
+			FROM item
+		`
+		done := s.logQuery(ctx, op, q)
+
+		var (
+			status  models.ItemStatus
+			updated bool
+		)
+		err := tx.QueryRow(ctx, q, itemID, string(from), string(to)).Scan(&status, &updated)
+		done()
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return storage.ErrItemNotFound
+			}
+
+			return err
+		}
+
+		if !updated {
+			return fmt.Errorf("%w: item is %s", storage.ErrStatusChanged, status)
+		}
+
+		q = `DELETE FROM item_status_schedules WHERE item_id = $1`
+		done = s.logQuery(ctx, op, q)
+		_, err = tx.Exec(ctx, q, itemID)
+		done()
+		if err != nil {
+			return err
+		}
+
+		if from == models.ItemStatusLocked && to != models.ItemStatusLocked {
+			q = `DELETE FROM item_locks WHERE item_id = $1 AND reason = $2`
+			done = s.logQuery(ctx, op, q)
+			_, err = tx.Exec(ctx, q, itemID, models.LockReasonTradeHold)
+			done()
+			if err != nil {
+				return err
+			}
+		}
+
+		if hold == nil {
+			return nil
+		}
+
+		return s.insertItemLock(ctx, tx, hold)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) || errors.Is(err, storage.ErrStatusChanged) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// ScheduleTransition schedules t, replacing the scheduled transition of the
+// item, if any. It fills in the creation time of t.
+func (s *Storage) ScheduleTransition(ctx context.Context, t *models.Transition) error {
+	const op = "Storage.ScheduleTransition"
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		return upsertSchedule(ctx, s, tx, op, t)
+	})
+	if err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+// upsertSchedule saves t as the scheduled transition of its item within tx.
+func upsertSchedule(ctx context.Context, s *Storage, tx pgx.Tx, op string, t *models.Transition) error {
+	q := `
+		INSERT INTO item_status_schedules (
+			item_id,
+			to_status,
+			due_at
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_id) DO UPDATE
+		SET to_status = EXCLUDED.to_status,
+			due_at = EXCLUDED.due_at,
+			created_at = now()
+		RETURNING created_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	return tx.QueryRow(ctx, q, t.ItemId, string(t.To), t.DueAt).Scan(&t.CreatedAt)
+}
+
+// GetScheduledTransition returns the scheduled transition of the item.
+func (s *Storage) GetScheduledTransition(ctx context.Context, itemID uuid.UUID) (*models.Transition, error) {
+	const op = "Storage.GetScheduledTransition"
+
+	q := `
+		SELECT
+			item_id,
+			to_status,
+			due_at,
+			created_at
+		FROM item_status_schedules
+		WHERE item_id = $1
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	t, err := scanTransition(s.reader(ctx).QueryRow(ctx, q, itemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.Transition{}, fmt.Errorf("%s: %w", op, storage.ErrTransitionNotFound)
+		}
+
+		return &models.Transition{}, pgError(op, err)
+	}
+
+	return t, nil
+}
+
+// DeleteScheduledTransition drops the scheduled transition of the item.
+func (s *Storage) DeleteScheduledTransition(ctx context.Context, itemID uuid.UUID) error {
+	const op = "Storage.DeleteScheduledTransition"
+
+	q := `DELETE FROM item_status_schedules WHERE item_id = $1`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, itemID)
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTransitionNotFound)
+	}
+
+	return nil
+}
+
+// DueTransitions returns at most limit scheduled transitions due at now,
+// the earliest first.
+func (s *Storage) DueTransitions(ctx context.Context, now time.Time, limit int) ([]*models.Transition, error) {
+	const op = "Storage.DueTransitions"
+
+	q := `
+		SELECT
+			item_id,
+			to_status,
+			due_at,
+			created_at
+		FROM item_status_schedules
+		WHERE due_at <= $1
+		ORDER BY due_at, item_id
+		LIMIT $2
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.primary().Query(ctx, q, now, limit)
+	if err != nil {
+		return []*models.Transition{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	transitions := []*models.Transition{}
+	for rows.Next() {
+		t, err := scanTransition(rows)
+		if err != nil {
+			return []*models.Transition{}, pgError(op, err)
+		}
+
+		transitions = append(transitions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.Transition{}, pgError(op, err)
+	}
+
+	return transitions, nil
+}
+
+func scanTransition(row pgx.Row) (*models.Transition, error) {
+	var t models.Transition
+	if err := row.Scan(&t.ItemId, &t.To, &t.DueAt, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// statusStrings converts statuses for use as a text[] query argument.
+func statusStrings(statuses []models.ItemStatus) []string {
+	out := make([]string, len(statuses))
+	for i, status := range statuses {
+		out[i] = string(status)
+	}
+
+	return out
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// AddItemTags adds the tags to the item and returns its tags, sorted.
+// Tags the item already has are left as they are.
+func (s *Storage) AddItemTags(ctx context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Storage.AddItemTags"
+
+	q := `
+		UPDATE items
+		SET tags = ARRAY(
+			SELECT DISTINCT tag
+			FROM unnest(tags || $2::text[]) AS tag
+			ORDER BY tag
+		)
+		WHERE id = $1
+		RETURNING tags
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var saved []string
+
+	if err := s.writer(ctx).QueryRow(ctx, q, itemID, tags).Scan(&saved); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []string{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+		if isPgCode(err, codeCheckViolation) {
+			return []string{}, fmt.Errorf("%s: %w", op, storage.ErrTooManyTags)
+		}
+
+		return []string{}, pgError(op, err)
+	}
+
+	return saved, nil
+}
+
+// RemoveItemTags removes the tags from the item and returns its remaining
+// tags, sorted. Tags the item does not have are ignored.
+func (s *Storage) RemoveItemTags(ctx context.Context, itemID uuid.UUID, tags []string) ([]string, error) {
+	const op = "Storage.RemoveItemTags"
+
+	q := `
+		UPDATE items
+		SET tags = ARRAY(
+			SELECT tag
+			FROM unnest(tags) AS tag
+			WHERE tag <> ALL ($2::text[])
+			ORDER BY tag
+		)
+		WHERE id = $1
+		RETURNING tags
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	var saved []string
+
+	if err := s.writer(ctx).QueryRow(ctx, q, itemID, tags).Scan(&saved); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []string{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+
+		return []string{}, pgError(op, err)
+	}
+
+	return saved, nil
+}
+
+// GetFacets counts the items matching the filter by rarity, quality and
+// tag in a single scan. At most tagLimit tags are returned.
+func (s *Storage) GetFacets(ctx context.Context, filter models.ItemFilter, tagLimit int) (*models.Facets, error) {
+	const op = "Storage.GetFacets"
+
+	where, args := itemFilterWhere(filter)
+	args = append(args, tagLimit)
+
+	q := `
+		WITH matched AS MATERIALIZED (
+			SELECT d.rarity, i.quality, i.tags
+			FROM items i
+			JOIN item_definitions d ON d.id = i.definition_id
+			WHERE ` + strings.Join(where, " AND ") + `
+		)
+		SELECT 'total', '', count(*) FROM matched
+		UNION ALL
+		(SELECT 'rarity', rarity, count(*) FROM matched GROUP BY rarity)
+		UNION ALL
+		(SELECT 'quality', quality, count(*) FROM matched GROUP BY quality)
+		UNION ALL
+		(
+			SELECT 'tag', tag, count(*)
+			FROM matched, unnest(tags) AS tag
+			GROUP BY tag
+			ORDER BY count(*) DESC, tag
+			LIMIT $` + fmt.Sprint(len(args)) + `
+		)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return &models.Facets{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	facets := &models.Facets{
+		Rarities:  []models.FacetCount{},
+		Qualities: []models.FacetCount{},
+		Tags:      []models.FacetCount{},
+	}
+
+	for rows.Next() {
+		var (
+			facet string
+			fc    models.FacetCount
+		)
+
+		if err := rows.Scan(&facet, &fc.Value, &fc.Count); err != nil {
+			return &models.Facets{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		switch facet {
+		case "total":
+			facets.Total = fc.Count
+		case "rarity":
+			facets.Rarities = append(facets.Rarities, fc)
+		case "quality":
+			facets.Qualities = append(facets.Qualities, fc)
+		case "tag":
+			facets.Tags = append(facets.Tags, fc)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return &models.Facets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	models.SortFacetCounts(facets.Rarities)
+	models.SortFacetCounts(facets.Qualities)
+	models.SortFacetCounts(facets.Tags)
+
+	return facets, nil
+}
@@ -0,0 +1,514 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// tradeStatusColumn reports pending offers past their expiry as expired,
+// even before they were marked so.
+const tradeStatusColumn = `
+	CASE
+		WHEN t.status = 'pending' AND t.expires_at <= now() THEN 'expired'
+		ELSE t.status
+	END
+`
+
+// SaveTradeOffer creates a pending trade offer. It fails with
+// storage.ErrTradeItemsUnavailable unless every item is currently held by
+// the party that gives it.
+func (s *Storage) SaveTradeOffer(ctx context.Context, offer *models.TradeOffer) (uuid.UUID, error) {
+	const op = "Storage.SaveTradeOffer"
+
+	var id uuid.UUID
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		itemIDs, owners := tradeItems(offer)
+
+		if err := s.checkTradeItems(ctx, tx, itemIDs, owners, false); err != nil {
+			return err
+		}
+
+		q := `
+			INSERT INTO trade_offers (
+				proposer_id,
+				recipient_id,
+				expires_at
+			)
+			VALUES (
+				$1,
+				$2,
+				$3
+			)
+			RETURNING id
+		`
+		done := s.logQuery(ctx, op, q)
+		err := tx.QueryRow(ctx, q, offer.ProposerId, offer.RecipientId, offer.ExpiresAt).Scan(&id)
+		done()
+		if err != nil {
+			return err
+		}
+
+		q = `
+			INSERT INTO trade_offer_items (
+				trade_id,
+				item_id,
+				owner_id
+			)
+			SELECT $1, item_id, owner_id
+			FROM unnest($2::uuid[], $3::uuid[]) AS t (item_id, owner_id)
+		`
+		defer s.logQuery(ctx, op, q)()
+
+		_, err = tx.Exec(ctx, q, id, itemIDs, owners)
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrTradeItemsUnavailable) {
+			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return id, nil
+}
+
+// AcceptTradeOffer swaps the items of a pending offer in a single
+// serializable transaction and marks it accepted. Every item must still be
+// held by the party that gives it, otherwise nothing changes. An offer past
+// its expiry is marked expired and storage.ErrTradeExpired is returned.
+// A non-zero holdUntil locks the swapped items for their new owners.
+func (s *Storage) AcceptTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID, holdUntil time.Time) (*models.TradeOffer, error) {
+	const op = "Storage.AcceptTradeOffer"
+
+	var (
+		offer   *models.TradeOffer
+		expired bool
+	)
+
+	err := s.withSerializableTx(ctx, func(tx pgx.Tx) error {
+		var err error
+
+		offer, expired, err = s.lockPendingTradeOffer(ctx, tx, tradeID)
+		if err != nil || expired {
+			return err
+		}
+
+		if offer.RecipientId != recipientID {
+			return storage.ErrNotTradeParticipant
+		}
+
+		itemIDs, owners := tradeItems(offer)
+
+		if err := s.checkTradeItems(ctx, tx, itemIDs, owners, true); err != nil {
+			return err
+		}
+
+		for i, itemID := range itemIDs {
+			to := offer.RecipientId
+			if owners[i] == offer.RecipientId {
+				to = offer.ProposerId
+			}
+
+			if err := s.transferItem(ctx, tx, &models.OwnershipTransfer{
+				ItemId:    itemID,
+				FromOwner: owners[i],
+				ToOwner:   to,
+			}, holdUntil); err != nil {
+				return err
+			}
+		}
+
+		return s.setTradeOfferStatus(ctx, tx, offer, models.TradeAccepted)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTradeNotFound),
+			errors.Is(err, storage.ErrTradeNotPending),
+			errors.Is(err, storage.ErrNotTradeParticipant),
+			errors.Is(err, storage.ErrTradeItemsUnavailable):
+			return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return &models.TradeOffer{}, pgError(op, err)
+	}
+
+	if expired {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrTradeExpired)
+	}
+
+	return offer, nil
+}
+
+// DeclineTradeOffer marks a pending offer declined on behalf of its recipient.
+func (s *Storage) DeclineTradeOffer(ctx context.Context, tradeID, recipientID uuid.UUID) (*models.TradeOffer, error) {
+	return s.resolveTradeOffer(ctx, "Storage.DeclineTradeOffer", tradeID, models.TradeDeclined,
+		func(offer *models.TradeOffer) bool { return offer.RecipientId == recipientID },
+	)
+}
+
+// CancelTradeOffer marks a pending offer cancelled on behalf of its proposer.
+func (s *Storage) CancelTradeOffer(ctx context.Context, tradeID, proposerID uuid.UUID) (*models.TradeOffer, error) {
+	return s.resolveTradeOffer(ctx, "Storage.CancelTradeOffer", tradeID, models.TradeCancelled,
+		func(offer *models.TradeOffer) bool { return offer.ProposerId == proposerID },
+	)
+}
+
+func (s *Storage) resolveTradeOffer(
+	ctx context.Context,
+	op string,
+	tradeID uuid.UUID,
+	status models.TradeStatus,
+	allowed func(offer *models.TradeOffer) bool,
+) (*models.TradeOffer, error) {
+	var (
+		offer   *models.TradeOffer
+		expired bool
+	)
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		var err error
+
+		offer, expired, err = s.lockPendingTradeOffer(ctx, tx, tradeID)
+		if err != nil || expired {
+			return err
+		}
+
+		if !allowed(offer) {
+			return storage.ErrNotTradeParticipant
+		}
+
+		return s.setTradeOfferStatus(ctx, tx, offer, status)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTradeNotFound),
+			errors.Is(err, storage.ErrTradeNotPending),
+			errors.Is(err, storage.ErrNotTradeParticipant):
+			return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return &models.TradeOffer{}, pgError(op, err)
+	}
+
+	if expired {
+		return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrTradeExpired)
+	}
+
+	return offer, nil
+}
+
+// lockPendingTradeOffer locks the offer for update. It fails with
+// storage.ErrTradeNotPending if the offer is resolved. A pending offer past
+// its expiry is marked expired and reported through expired.
+func (s *Storage) lockPendingTradeOffer(ctx context.Context, tx pgx.Tx, tradeID uuid.UUID) (offer *models.TradeOffer, expired bool, err error) {
+	const op = "Storage.lockPendingTradeOffer"
+
+	q := `
+		SELECT
+			id,
+			proposer_id,
+			recipient_id,
+			status,
+			created_at,
+			expires_at
+		FROM trade_offers
+		WHERE id = $1
+		FOR UPDATE
+	`
+	done := s.logQuery(ctx, op, q)
+
+	offer = &models.TradeOffer{}
+	err = tx.QueryRow(ctx, q, tradeID).Scan(
+		&offer.TradeId,
+		&offer.ProposerId,
+		&offer.RecipientId,
+		&offer.Status,
+		&offer.CreatedAt,
+		&offer.ExpiresAt,
+	)
+	done()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, storage.ErrTradeNotFound
+		}
+
+		return nil, false, err
+	}
+
+	if offer.Status != models.TradePending {
+		return nil, false, storage.ErrTradeNotPending
+	}
+
+	if err := s.loadTradeItems(ctx, tx, offer); err != nil {
+		return nil, false, err
+	}
+
+	if !offer.ExpiresAt.After(time.Now()) {
+		return offer, true, s.setTradeOfferStatus(ctx, tx, offer, models.TradeExpired)
+	}
+
+	return offer, false, nil
+}
+
+func (s *Storage) setTradeOfferStatus(ctx context.Context, tx pgx.Tx, offer *models.TradeOffer, status models.TradeStatus) error {
+	const op = "Storage.setTradeOfferStatus"
+
+	q := `
+		UPDATE trade_offers
+		SET status = $2, resolved_at = now()
+		WHERE id = $1
+		RETURNING resolved_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if err := tx.QueryRow(ctx, q, offer.TradeId, status).Scan(&offer.ResolvedAt); err != nil {
+		return err
+	}
+
+	offer.Status = status
+
+	return nil
+}
+
+// checkTradeItems verifies that every item exists, is published, has no
+// active lock and is held by the corresponding owner, optionally locking
+// the item rows.
+func (s *Storage) checkTradeItems(ctx context.Context, tx pgx.Tx, itemIDs, owners []uuid.UUID, lock bool) error {
+	const op = "Storage.checkTradeItems"
+
+	q := `
+		SELECT i.id, i.owner_id
+		FROM items i
+		WHERE i.id = ANY($1)
+		AND i.status = 'published'
+		AND NOT EXISTS (
+			SELECT 1
+			FROM item_locks l
+			WHERE l.item_id = i.id
+			AND ` + activeLock + `
+		)
+	`
+	if lock {
+		q += "FOR UPDATE"
+	}
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := tx.Query(ctx, q, itemIDs)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	held := make(map[uuid.UUID]uuid.UUID, len(itemIDs))
+	for rows.Next() {
+		var id, owner uuid.UUID
+		if err := rows.Scan(&id, &owner); err != nil {
+			return err
+		}
+
+		held[id] = owner
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, id := range itemIDs {
+		if owner, ok := held[id]; !ok || owner != owners[i] {
+			return storage.ErrTradeItemsUnavailable
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) loadTradeItems(ctx context.Context, db querier, offer *models.TradeOffer) error {
+	const op = "Storage.loadTradeItems"
+
+	q := `
+		SELECT item_id, owner_id
+		FROM trade_offer_items
+		WHERE trade_id = $1
+		ORDER BY item_id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := db.Query(ctx, q, offer.TradeId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	offer.ProposerItems, offer.RecipientItems = []uuid.UUID{}, []uuid.UUID{}
+
+	for rows.Next() {
+		var id, owner uuid.UUID
+		if err := rows.Scan(&id, &owner); err != nil {
+			return err
+		}
+
+		if owner == offer.ProposerId {
+			offer.ProposerItems = append(offer.ProposerItems, id)
+		} else {
+			offer.RecipientItems = append(offer.RecipientItems, id)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Storage) GetTradeOffer(ctx context.Context, tradeID uuid.UUID) (*models.TradeOffer, error) {
+	const op = "Storage.GetTradeOffer"
+
+	q := `
+		SELECT
+			t.id,
+			t.proposer_id,
+			t.recipient_id,
+			` + tradeStatusColumn + `,
+			t.created_at,
+			t.expires_at,
+			t.resolved_at
+		FROM trade_offers t
+		WHERE t.id = $1
+	`
+	done := s.logQuery(ctx, op, q)
+
+	var (
+		offer      models.TradeOffer
+		resolvedAt *time.Time
+	)
+
+	db := s.reader(ctx)
+
+	err := db.QueryRow(ctx, q, tradeID).Scan(
+		&offer.TradeId,
+		&offer.ProposerId,
+		&offer.RecipientId,
+		&offer.Status,
+		&offer.CreatedAt,
+		&offer.ExpiresAt,
+		&resolvedAt,
+	)
+	done()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.TradeOffer{}, fmt.Errorf("%s: %w", op, storage.ErrTradeNotFound)
+		}
+
+		return &models.TradeOffer{}, pgError(op, err)
+	}
+
+	if resolvedAt != nil {
+		offer.ResolvedAt = *resolvedAt
+	}
+
+	if err := s.loadTradeItems(ctx, db, &offer); err != nil {
+		return &models.TradeOffer{}, pgError(op, err)
+	}
+
+	return &offer, nil
+}
+
+// GetTradeOffers returns the offers sent or received by tq.OwnerId, newest first.
+func (s *Storage) GetTradeOffers(ctx context.Context, tq models.TradeQuery) ([]*models.TradeOffer, error) {
+	const op = "Storage.GetTradeOffers"
+
+	q := `
+		SELECT
+			t.id,
+			t.proposer_id,
+			t.recipient_id,
+			` + tradeStatusColumn + ` AS status,
+			t.created_at,
+			t.expires_at,
+			t.resolved_at,
+			i.item_id,
+			i.owner_id
+		FROM trade_offers t
+		JOIN trade_offer_items i ON i.trade_id = t.id
+		WHERE (t.proposer_id = $1 OR t.recipient_id = $1)
+			AND ($2 = '' OR ` + tradeStatusColumn + ` = $2)
+		ORDER BY t.created_at DESC, t.id, i.item_id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, tq.OwnerId, string(tq.Status))
+	if err != nil {
+		return []*models.TradeOffer{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	offers := make([]*models.TradeOffer, 0)
+
+	var offer *models.TradeOffer
+	for rows.Next() {
+		var (
+			row         models.TradeOffer
+			resolvedAt  *time.Time
+			item, owner uuid.UUID
+		)
+
+		if err := rows.Scan(
+			&row.TradeId,
+			&row.ProposerId,
+			&row.RecipientId,
+			&row.Status,
+			&row.CreatedAt,
+			&row.ExpiresAt,
+			&resolvedAt,
+			&item,
+			&owner,
+		); err != nil {
+			return []*models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if offer == nil || offer.TradeId != row.TradeId {
+			if resolvedAt != nil {
+				row.ResolvedAt = *resolvedAt
+			}
+			row.ProposerItems, row.RecipientItems = []uuid.UUID{}, []uuid.UUID{}
+
+			offer = &row
+			offers = append(offers, offer)
+		}
+
+		if owner == offer.ProposerId {
+			offer.ProposerItems = append(offer.ProposerItems, item)
+		} else {
+			offer.RecipientItems = append(offer.RecipientItems, item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.TradeOffer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return offers, nil
+}
+
+// tradeItems returns the items of the offer together with the party expected to hold each.
+func tradeItems(offer *models.TradeOffer) (itemIDs, owners []uuid.UUID) {
+	for _, id := range offer.ProposerItems {
+		itemIDs = append(itemIDs, id)
+		owners = append(owners, offer.ProposerId)
+	}
+
+	for _, id := range offer.RecipientItems {
+		itemIDs = append(itemIDs, id)
+		owners = append(owners, offer.RecipientId)
+	}
+
+	return itemIDs, owners
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// SaveTradeUp consumes the input items, creates the output item in the
+// owner's inventory and records the contract, in one transaction. It fails
+// with storage.ErrTradeUpInputsUnavailable unless every input is still held
+// by the owner, not consumed and unchanged since it was read. It fills in
+// the output item ID and the creation time of tu.
+func (s *Storage) SaveTradeUp(ctx context.Context, tu *models.TradeUp, quality string) (uuid.UUID, error) {
+	const op = "Storage.SaveTradeUp"
+
+	var id uuid.UUID
+
+	err := s.withTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if err := s.consumeTradeUpInputs(ctx, tx, tu); err != nil {
+			return err
+		}
+
+		q := `
+			WITH item AS (
+				INSERT INTO items (
+					id,
+					definition_id,
+					owner_id,
+					quality,
+					float_value,
+					pattern_seed
+				)
+				VALUES (
+					gen_random_uuid(),
+					$1,
+					$2,
+					$3,
+					$4,
+					$5
+				)
+				RETURNING id, owner_id
+			), history AS (
+				INSERT INTO ownership_history (
+					item_id,
+					to_owner
+				)
+				SELECT id, owner_id
+				FROM item
+			)
+			INSERT INTO trade_ups (
+				owner_id,
+				output_item_id,
+				output_definition_id,
+				output_rarity,
+				output_float,
+				output_pattern_seed,
+				seed
+			)
+			SELECT owner_id, id, $1, $6, $4, $5, $7
+			FROM item
+			RETURNING id, output_item_id, created_at
+		`
+		done := s.logQuery(ctx, op, q)
+		err := tx.QueryRow(
+			ctx, q,
+			tu.OutputDefinitionId, tu.OwnerId, quality, tu.OutputFloat, tu.OutputPatternSeed,
+			tu.OutputRarity, tu.Seed,
+		).Scan(&id, &tu.OutputItemId, &tu.CreatedAt)
+		done()
+		if err != nil {
+			return err
+		}
+
+		itemIDs := make([]uuid.UUID, 0, len(tu.Inputs))
+		definitionIDs := make([]uuid.UUID, 0, len(tu.Inputs))
+		floats := make([]float64, 0, len(tu.Inputs))
+		for _, in := range tu.Inputs {
+			itemIDs = append(itemIDs, in.ItemId)
+			definitionIDs = append(definitionIDs, in.DefinitionId)
+			floats = append(floats, in.Float)
+		}
+
+		q = `
+			INSERT INTO trade_up_inputs (
+				trade_up_id,
+				item_id,
+				definition_id,
+				float_value
+			)
+			SELECT $1, *
+			FROM unnest($2::uuid[], $3::uuid[], $4::double precision[])
+		`
+		defer s.logQuery(ctx, op, q)()
+
+		_, err = tx.Exec(ctx, q, id, itemIDs, definitionIDs, floats)
+
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTradeUpInputsUnavailable):
+			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+		case isPgCode(err, codeForeignKeyViolation):
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		case isPgCode(err, codeUniqueViolation):
+			return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrTradeUpInputsUnavailable)
+		}
+
+		return uuid.Nil, pgError(op, err)
+	}
+
+	return id, nil
+}
+
+// consumeTradeUpInputs locks the inputs of tu, checks that they are still
+// as the contract expects them, published and without an active lock, and
+// marks them consumed.
+func (s *Storage) consumeTradeUpInputs(ctx context.Context, tx pgx.Tx, tu *models.TradeUp) error {
+	const op = "Storage.consumeTradeUpInputs"
+
+	itemIDs := make([]uuid.UUID, 0, len(tu.Inputs))
+	for _, in := range tu.Inputs {
+		itemIDs = append(itemIDs, in.ItemId)
+	}
+
+	q := `
+		SELECT
+			i.id,
+			i.definition_id,
+			i.owner_id,
+			i.float_value,
+			i.consumed_at IS NOT NULL
+		FROM items i
+		WHERE i.id = ANY($1)
+		AND i.status = 'published'
+		AND NOT EXISTS (
+			SELECT 1
+			FROM item_locks l
+			WHERE l.item_id = i.id
+			AND ` + activeLock + `
+		)
+		FOR UPDATE OF i
+	`
+	done := s.logQuery(ctx, op, q)
+	rows, err := tx.Query(ctx, q, itemIDs)
+	done()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	held := make(map[uuid.UUID]models.TradeUpInput, len(itemIDs))
+	for rows.Next() {
+		var (
+			in       models.TradeUpInput
+			owner    uuid.UUID
+			consumed bool
+		)
+		if err := rows.Scan(&in.ItemId, &in.DefinitionId, &owner, &in.Float, &consumed); err != nil {
+			return err
+		}
+
+		if owner == tu.OwnerId && !consumed {
+			held[in.ItemId] = in
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, in := range tu.Inputs {
+		if held[in.ItemId] != in {
+			return storage.ErrTradeUpInputsUnavailable
+		}
+	}
+
+	q = `
+		UPDATE items
+		SET owner_id = NULL, consumed_at = now()
+		WHERE id = ANY($1)
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	_, err = tx.Exec(ctx, q, itemIDs)
+
+	return err
+}
+
+// GetTradeUp returns the record of a trade-up contract with its inputs.
+func (s *Storage) GetTradeUp(ctx context.Context, tradeUpID uuid.UUID) (*models.TradeUp, error) {
+	const op = "Storage.GetTradeUp"
+
+	q := `
+		SELECT
+			id,
+			owner_id,
+			output_item_id,
+			output_definition_id,
+			output_rarity,
+			output_float,
+			output_pattern_seed,
+			seed,
+			created_at
+		FROM trade_ups
+		WHERE id = $1
+	`
+	done := s.logQuery(ctx, op, q)
+
+	db := s.reader(ctx)
+
+	var tu models.TradeUp
+	err := db.QueryRow(ctx, q, tradeUpID).Scan(
+		&tu.TradeUpId,
+		&tu.OwnerId,
+		&tu.OutputItemId,
+		&tu.OutputDefinitionId,
+		&tu.OutputRarity,
+		&tu.OutputFloat,
+		&tu.OutputPatternSeed,
+		&tu.Seed,
+		&tu.CreatedAt,
+	)
+	done()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, storage.ErrTradeUpNotFound)
+		}
+
+		return &models.TradeUp{}, pgError(op, err)
+	}
+
+	q = `
+		SELECT item_id, definition_id, float_value
+		FROM trade_up_inputs
+		WHERE trade_up_id = $1
+		ORDER BY item_id
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := db.Query(ctx, q, tradeUpID)
+	if err != nil {
+		return &models.TradeUp{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var in models.TradeUpInput
+		if err := rows.Scan(&in.ItemId, &in.DefinitionId, &in.Float); err != nil {
+			return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		tu.Inputs = append(tu.Inputs, in)
+	}
+
+	if err := rows.Err(); err != nil {
+		return &models.TradeUp{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &tu, nil
+}
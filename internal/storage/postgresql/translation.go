@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"item-service/internal/domain/models"
+	"item-service/internal/storage"
+)
+
+// searchConfigs are the built-in text search configurations by language.
+// Languages without one are indexed by the simple configuration, which
+// does not stem.
+var searchConfigs = map[string]string{
+	"ar": "arabic",
+	"ca": "catalan",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"id": "indonesian",
+	"it": "italian",
+	"lt": "lithuanian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+// searchConfig returns the text search configuration of the locale's language.
+func searchConfig(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	if cfg, ok := searchConfigs[lang]; ok {
+		return cfg
+	}
+
+	return "simple"
+}
+
+// SaveTranslation creates or replaces the translation of the definition
+// in t.Locale. It fills in the update time of t.
+func (s *Storage) SaveTranslation(ctx context.Context, t *models.Translation) error {
+	const op = "Storage.SaveTranslation"
+
+	q := `
+		INSERT INTO item_definition_translations (
+			definition_id,
+			locale,
+			name,
+			description,
+			search_config
+		)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5::regconfig
+		)
+		ON CONFLICT (definition_id, locale) DO UPDATE
+		SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			search_config = EXCLUDED.search_config,
+			updated_at = now()
+		RETURNING updated_at
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	if err := s.writer(ctx).QueryRow(
+		ctx, q,
+		t.DefinitionId, t.Locale, t.Name, t.Description, searchConfig(t.Locale),
+	).Scan(&t.UpdatedAt); err != nil {
+		if isPgCode(err, codeForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, storage.ErrDefinitionNotFound)
+		}
+
+		return pgError(op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteTranslation(ctx context.Context, definitionID uuid.UUID, locale string) error {
+	const op = "Storage.DeleteTranslation"
+
+	q := `
+		DELETE FROM item_definition_translations
+		WHERE definition_id = $1 AND locale = $2
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	tag, err := s.writer(ctx).Exec(ctx, q, definitionID, locale)
+	if err != nil {
+		return pgError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTranslationNotFound)
+	}
+
+	return nil
+}
+
+// GetTranslations returns the translations of the definitions by
+// definition, ordered by locale. Nil locales return every locale.
+func (s *Storage) GetTranslations(ctx context.Context, definitionIDs []uuid.UUID, locales []string) (map[uuid.UUID][]*models.Translation, error) {
+	const op = "Storage.GetTranslations"
+
+	q := `
+		SELECT
+			definition_id,
+			locale,
+			name,
+			description,
+			updated_at
+		FROM item_definition_translations
+		WHERE definition_id = ANY($1)
+		AND ($2::text[] IS NULL OR locale = ANY($2))
+		ORDER BY definition_id, locale
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, definitionIDs, locales)
+	if err != nil {
+		return map[uuid.UUID][]*models.Translation{}, pgError(op, err)
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID][]*models.Translation)
+
+	for rows.Next() {
+		var t models.Translation
+
+		if err := rows.Scan(&t.DefinitionId, &t.Locale, &t.Name, &t.Description, &t.UpdatedAt); err != nil {
+			return map[uuid.UUID][]*models.Translation{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		translations[t.DefinitionId] = append(translations[t.DefinitionId], &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return map[uuid.UUID][]*models.Translation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return translations, nil
+}
+
+// SearchItemDefinitions returns the definitions matching the query in the
+// locale, best matches first. The definitions are not localized.
+func (s *Storage) SearchItemDefinitions(ctx context.Context, ds models.DefinitionSearch) ([]*models.ItemDefinition, error) {
+	const op = "Storage.SearchItemDefinitions"
+
+	q := `
+		SELECT
+			id,
+			name,
+			rarity,
+			min_float,
+			max_float,
+			created_at
+		FROM (
+			SELECT d.*, ts_rank(t.search, websearch_to_tsquery($2::regconfig, $3)) AS rank
+			FROM item_definition_translations t
+			JOIN item_definitions d ON d.id = t.definition_id
+			WHERE t.locale = $1
+			AND t.search @@ websearch_to_tsquery($2::regconfig, $3)
+
+			UNION ALL
+
+			SELECT d.*, ts_rank(to_tsvector('simple', d.name), websearch_to_tsquery('simple', $3)) AS rank
+			FROM item_definitions d
+			WHERE to_tsvector('simple', d.name) @@ websearch_to_tsquery('simple', $3)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM item_definition_translations t
+				WHERE t.definition_id = d.id AND t.locale = $1
+			)
+		) found
+		ORDER BY rank DESC, name, rarity
+		LIMIT $4
+	`
+	defer s.logQuery(ctx, op, q)()
+
+	rows, err := s.reader(ctx).Query(ctx, q, ds.Locale, searchConfig(ds.Locale), ds.Query, ds.Limit)
+	if err != nil {
+		return []*models.ItemDefinition{}, pgError(op, err)
+	}
+
+	defer rows.Close()
+
+	defs := make([]*models.ItemDefinition, 0)
+
+	for rows.Next() {
+		var def models.ItemDefinition
+
+		if err := rows.Scan(&def.DefinitionId, &def.Name, &def.Rarity, &def.MinFloat, &def.MaxFloat, &def.CreatedAt); err != nil {
+			return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		defs = append(defs, &def)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*models.ItemDefinition{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// querier is implemented by both connection pools and transactions.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// maxSerializableAttempts limits retries of serializable transactions that
+// fail because of concurrent updates.
+const maxSerializableAttempts = 3
+
+// withTx runs fn in a transaction on the primary. The transaction is
+// committed if fn succeeds and rolled back otherwise.
+func (s *Storage) withTx(ctx context.Context, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, err := s.writer(ctx).BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// withSnapshotTx runs fn in a read-only transaction on a replica, or the
+// primary, that sees a single snapshot of the database.
+func (s *Storage) withSnapshotTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := s.reader(ctx).BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// withSerializableTx runs fn in a serializable transaction, retrying it when
+// PostgreSQL aborts it because of a serialization failure or a deadlock.
+func (s *Storage) withSerializableTx(ctx context.Context, fn func(tx pgx.Tx) error) (err error) {
+	for attempt := 0; attempt < maxSerializableAttempts; attempt++ {
+		err = s.withTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}, fn)
+		if !isPgCode(err, codeSerializationFailure) && !isPgCode(err, codeDeadlockDetected) {
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,19 @@
+package storage
+
+import "context"
+
+type sessionKey struct{}
+
+// WithSession returns a copy of ctx that carries the client session id.
+// Storages use it to route reads of a session that has just written
+// to the primary database.
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+// SessionFromContext returns the client session id stored in ctx.
+func SessionFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionKey{}).(string)
+
+	return id, ok && id != ""
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"item-service/internal/lib/snapshot"
+)
+
+// SchemaVersion is the version of the database schema this build reads
+// and writes: the number of the last migration. Migrations record it in
+// the schema_version table.
+const SchemaVersion = 16
+
+var (
+	ErrSchemaMismatch = errors.New("Schema version does not match")
+	ErrNotEmpty       = errors.New("Database is not empty")
+)
+
+// Snapshotter is a storage that can be snapshotted and restored.
+type Snapshotter interface {
+	// SchemaVersion returns the version of the schema of the database.
+	SchemaVersion(ctx context.Context) (int, error)
+	// Snapshot writes every row of the database to w, as of one point in
+	// time.
+	Snapshot(ctx context.Context, w *snapshot.Writer) error
+	// Restore writes the rows of r into the database, keeping their IDs.
+	// The database must be empty; nothing is restored if any row fails.
+	Restore(ctx context.Context, r *snapshot.Reader) error
+}
+
+// WriteSnapshot writes a snapshot of the storage to w and returns the
+// number of rows written per table.
+func WriteSnapshot(ctx context.Context, s Snapshotter, w io.Writer) (map[string]int, error) {
+	const op = "storage.WriteSnapshot"
+
+	version, err := checkSchemaVersion(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sw, err := snapshot.NewWriter(w, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.Snapshot(ctx, sw); err != nil {
+		_ = sw.Abort()
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := sw.Close(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sw.Rows(), nil
+}
+
+// RestoreSnapshot restores the snapshot in r into the storage and returns
+// its header and the number of rows restored per table. The snapshot, the
+// database and this build must have the same schema version.
+func RestoreSnapshot(ctx context.Context, s Snapshotter, r io.Reader) (snapshot.Header, map[string]int, error) {
+	const op = "storage.RestoreSnapshot"
+
+	sr, err := snapshot.NewReader(r)
+	if err != nil {
+		return snapshot.Header{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer sr.Close()
+
+	h := sr.Header()
+
+	version, err := checkSchemaVersion(ctx, s)
+	if err != nil {
+		return h, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if h.SchemaVersion != version {
+		return h, nil, fmt.Errorf("%s: %w: the snapshot has schema version %d, the database %d",
+			op, ErrSchemaMismatch, h.SchemaVersion, version)
+	}
+
+	if err := s.Restore(ctx, sr); err != nil {
+		return h, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return h, sr.Rows(), nil
+}
+
+// checkSchemaVersion returns the schema version of the storage if this
+// build knows its tables.
+func checkSchemaVersion(ctx context.Context, s Snapshotter) (int, error) {
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if version != SchemaVersion {
+		return 0, fmt.Errorf("%w: the database has schema version %d, this build %d", ErrSchemaMismatch, version, SchemaVersion)
+	}
+
+	return version, nil
+}
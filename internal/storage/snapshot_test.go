@@ -0,0 +1,157 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"item-service/internal/lib/seed"
+	"item-service/internal/lib/snapshot"
+	"item-service/internal/storage"
+	"item-service/internal/storage/memory"
+)
+
+var opts = seed.Options{
+	Seed:        7,
+	Items:       300,
+	Definitions: 20,
+	Owners:      10,
+	Rarities:    map[string]float64{"Mil-Spec": 80, "Restricted": 16, "Covert": 4},
+}
+
+// seeded returns a snapshot of generated data.
+func seeded(t *testing.T, schemaVersion int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := snapshot.NewWriter(&buf, schemaVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := seed.Write(w, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// rows returns the rows of a snapshot per table, as written.
+func rows(t *testing.T, data []byte) map[string][]string {
+	t.Helper()
+
+	r, err := snapshot.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	got := make(map[string][]string)
+	for {
+		table, row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return got
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got[table] = append(got[table], string(row))
+	}
+}
+
+func restore(t *testing.T, s storage.Snapshotter, data []byte) map[string]int {
+	t.Helper()
+
+	_, n, err := storage.RestoreSnapshot(context.Background(), s, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func snapshotOf(t *testing.T, s storage.Snapshotter) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if _, err := storage.WriteSnapshot(context.Background(), s, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	first := memory.New()
+	n := restore(t, first, seeded(t, storage.SchemaVersion))
+
+	if n["item_definitions"] != opts.Definitions || n["items"] != opts.Items {
+		t.Fatalf("restored %v, want %d definitions and %d items", n, opts.Definitions, opts.Items)
+	}
+
+	snap := snapshotOf(t, first)
+
+	second := memory.New()
+	restore(t, second, snap)
+
+	if got, want := rows(t, snapshotOf(t, second)), rows(t, snap); !reflect.DeepEqual(got, want) {
+		t.Error("the snapshot of the restored storage differs from the one restored")
+	}
+}
+
+func TestRestoreNotEmpty(t *testing.T) {
+	s := memory.New()
+	data := seeded(t, storage.SchemaVersion)
+	restore(t, s, data)
+
+	if _, _, err := storage.RestoreSnapshot(context.Background(), s, bytes.NewReader(data)); !errors.Is(err, storage.ErrNotEmpty) {
+		t.Errorf("second restore error = %v, want %v", err, storage.ErrNotEmpty)
+	}
+}
+
+// versioned is a storage of another schema version than this build.
+type versioned struct {
+	storage.Snapshotter
+	version int
+}
+
+func (v versioned) SchemaVersion(context.Context) (int, error) {
+	return v.version, nil
+}
+
+func TestSchemaMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		snapshot int
+		database int
+	}{
+		{"older snapshot", storage.SchemaVersion - 1, storage.SchemaVersion},
+		{"newer snapshot", storage.SchemaVersion + 1, storage.SchemaVersion},
+		{"older database", storage.SchemaVersion - 1, storage.SchemaVersion - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := versioned{Snapshotter: memory.New(), version: tt.database}
+
+			_, _, err := storage.RestoreSnapshot(ctx, s, bytes.NewReader(seeded(t, tt.snapshot)))
+			if !errors.Is(err, storage.ErrSchemaMismatch) {
+				t.Errorf("restore error = %v, want %v", err, storage.ErrSchemaMismatch)
+			}
+		})
+	}
+
+	s := versioned{Snapshotter: memory.New(), version: storage.SchemaVersion - 1}
+	if _, err := storage.WriteSnapshot(ctx, s, io.Discard); !errors.Is(err, storage.ErrSchemaMismatch) {
+		t.Errorf("snapshot of an older database error = %v, want %v", err, storage.ErrSchemaMismatch)
+	}
+}
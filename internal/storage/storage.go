@@ -5,4 +5,36 @@ import "errors"
 var (
 	ErrItemExists   = errors.New("Item already exists")
 	ErrItemNotFound = errors.New("Item not found")
+
+	ErrDefinitionExists   = errors.New("Item definition already exists")
+	ErrDefinitionNotFound = errors.New("Item definition not found")
+	ErrDefinitionInUse    = errors.New("Item definition has instances")
+	ErrFloatOutOfRange    = errors.New("Item float is outside of the definition's range")
+	ErrTooManyTags        = errors.New("Item has too many tags")
+
+	ErrNotOwner        = errors.New("Item is not owned by the given owner")
+	ErrItemNotTradable = errors.New("Item is not published")
+	ErrStatusChanged   = errors.New("Item status has changed")
+	ErrItemLocked      = errors.New("Item is locked")
+
+	ErrTransitionNotFound = errors.New("Scheduled transition not found")
+	ErrLockNotFound       = errors.New("Item lock not found")
+
+	ErrCollectionExists   = errors.New("Collection already exists")
+	ErrCollectionNotFound = errors.New("Collection not found")
+
+	ErrCaseOpeningNotFound = errors.New("Case opening not found")
+
+	ErrPriceNotFound = errors.New("No prices recorded")
+
+	ErrTranslationNotFound = errors.New("Translation not found")
+
+	ErrTradeUpNotFound          = errors.New("Trade-up not found")
+	ErrTradeUpInputsUnavailable = errors.New("Trade-up inputs are no longer held by the owner")
+
+	ErrTradeNotFound         = errors.New("Trade offer not found")
+	ErrTradeNotPending       = errors.New("Trade offer is not pending")
+	ErrTradeExpired          = errors.New("Trade offer has expired")
+	ErrNotTradeParticipant   = errors.New("Not a participant of the trade offer")
+	ErrTradeItemsUnavailable = errors.New("Trade offer items are no longer owned by the expected party or not tradable")
 )
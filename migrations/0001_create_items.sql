@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS items (
+    id      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name    TEXT NOT NULL,
+    rarity  TEXT NOT NULL,
+    quality TEXT NOT NULL
+);
+
+COMMIT;
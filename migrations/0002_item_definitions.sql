@@ -0,0 +1,30 @@
+-- Split items into catalogue entries (item_definitions) and concrete copies.
+-- Rows of items become instances of a definition per distinct (name, rarity).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS item_definitions (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       TEXT NOT NULL,
+    rarity     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (name, rarity)
+);
+
+INSERT INTO item_definitions (name, rarity)
+SELECT DISTINCT name, rarity FROM items
+ON CONFLICT (name, rarity) DO NOTHING;
+
+ALTER TABLE items ADD COLUMN definition_id UUID REFERENCES item_definitions (id);
+
+UPDATE items i
+SET definition_id = d.id
+FROM item_definitions d
+WHERE d.name = i.name AND d.rarity = i.rarity;
+
+ALTER TABLE items ALTER COLUMN definition_id SET NOT NULL;
+ALTER TABLE items DROP COLUMN name, DROP COLUMN rarity;
+ALTER TABLE items ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS items_definition_id_idx ON items (definition_id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Item ownership: the current owner on items and every change of owner.
+BEGIN;
+
+ALTER TABLE items ADD COLUMN owner_id UUID;
+
+CREATE INDEX IF NOT EXISTS items_owner_id_idx ON items (owner_id, id);
+
+CREATE TABLE IF NOT EXISTS ownership_history (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id        UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    from_owner     UUID,
+    to_owner       UUID NOT NULL,
+    transferred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS ownership_history_item_id_idx ON ownership_history (item_id, transferred_at);
+
+COMMIT;
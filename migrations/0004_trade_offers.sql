@@ -0,0 +1,28 @@
+-- Trade offers: items offered by the proposer in exchange for items of the recipient.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS trade_offers (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    proposer_id  UUID NOT NULL,
+    recipient_id UUID NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    resolved_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS trade_offers_proposer_id_idx ON trade_offers (proposer_id, created_at);
+CREATE INDEX IF NOT EXISTS trade_offers_recipient_id_idx ON trade_offers (recipient_id, created_at);
+
+-- owner_id is the party expected to hold the item when the offer is accepted.
+-- item_id has no foreign key: offers must outlive deleted items, acceptance
+-- fails instead.
+CREATE TABLE IF NOT EXISTS trade_offer_items (
+    trade_id UUID NOT NULL REFERENCES trade_offers (id) ON DELETE CASCADE,
+    item_id  UUID NOT NULL,
+    owner_id UUID NOT NULL,
+    PRIMARY KEY (trade_id, item_id)
+);
+
+COMMIT;
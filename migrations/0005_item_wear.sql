@@ -0,0 +1,30 @@
+-- Wear of item instances: a float in [0, 1] within the range allowed by the
+-- definition, and a pattern seed. The exterior grade is derived from the float.
+BEGIN;
+
+ALTER TABLE item_definitions
+    ADD COLUMN min_float DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN max_float DOUBLE PRECISION NOT NULL DEFAULT 1,
+    ADD CONSTRAINT item_definitions_float_range_check
+        CHECK (min_float >= 0 AND max_float <= 1 AND min_float < max_float);
+
+-- Existing instances get a random wear within their definition's range.
+ALTER TABLE items
+    ADD COLUMN float_value  DOUBLE PRECISION,
+    ADD COLUMN pattern_seed INTEGER;
+
+UPDATE items i
+SET float_value  = d.min_float + random() * (d.max_float - d.min_float),
+    pattern_seed = floor(random() * 1000)
+FROM item_definitions d
+WHERE d.id = i.definition_id;
+
+ALTER TABLE items
+    ALTER COLUMN float_value SET NOT NULL,
+    ALTER COLUMN pattern_seed SET NOT NULL,
+    ADD CONSTRAINT items_float_value_check CHECK (float_value >= 0 AND float_value <= 1),
+    ADD CONSTRAINT items_pattern_seed_check CHECK (pattern_seed BETWEEN 0 AND 999);
+
+CREATE INDEX IF NOT EXISTS items_float_value_idx ON items (float_value, id);
+
+COMMIT;
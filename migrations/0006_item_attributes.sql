@@ -0,0 +1,14 @@
+-- Typed attributes of item instances (stickers, StatTrak kills, name tags,
+-- souvenir flags), stored as a JSONB object keyed by attribute name.
+BEGIN;
+
+ALTER TABLE items ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE items ADD CONSTRAINT items_attributes_object_check
+    CHECK (jsonb_typeof(attributes) = 'object');
+
+-- jsonb_path_ops supports containment (@>) queries such as
+-- attributes @> '{"stickers": [{"sticker_id": "..."}]}'.
+CREATE INDEX IF NOT EXISTS items_attributes_idx ON items USING GIN (attributes jsonb_path_ops);
+
+COMMIT;
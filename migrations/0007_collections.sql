@@ -0,0 +1,22 @@
+-- Collections group item definitions, e.g. the drop pool of a case. A
+-- definition can belong to any number of collections.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS collections (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        TEXT NOT NULL UNIQUE,
+    kind        TEXT NOT NULL DEFAULT 'collection' CHECK (kind IN ('collection', 'case')),
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id UUID NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    definition_id UUID NOT NULL REFERENCES item_definitions (id) ON DELETE CASCADE,
+    added_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (collection_id, definition_id)
+);
+
+CREATE INDEX IF NOT EXISTS collection_items_definition_id_idx ON collection_items (definition_id);
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Audit log of case openings. Every row keeps the RNG seed, the drop table
+-- and the drop pool in effect, so the drop can be reproduced later. There are
+-- no foreign keys: records outlive the collection, the item and the definition.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS case_openings (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    collection_id UUID NOT NULL,
+    owner_id      UUID NOT NULL,
+    item_id       UUID NOT NULL,
+    definition_id UUID NOT NULL,
+    rarity        TEXT NOT NULL,
+    float_value   DOUBLE PRECISION NOT NULL,
+    pattern_seed  INTEGER NOT NULL,
+    seed          BIGINT NOT NULL,
+    drop_table    JSONB NOT NULL,
+    pool          UUID[] NOT NULL,
+    opened_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS case_openings_owner_id_idx ON case_openings (owner_id, opened_at);
+CREATE INDEX IF NOT EXISTS case_openings_collection_id_idx ON case_openings (collection_id, opened_at);
+
+COMMIT;
@@ -0,0 +1,33 @@
+-- Trade-up contracts. Consumed items are kept for the audit trail: they lose
+-- their owner and get consumed_at set, so they drop out of inventories,
+-- listings and trades. Every contract records its inputs, its output and the
+-- RNG seed that picked the output. An item can be consumed only once.
+BEGIN;
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS consumed_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS trade_ups (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id             UUID NOT NULL,
+    output_item_id       UUID NOT NULL,
+    output_definition_id UUID NOT NULL,
+    output_rarity        TEXT NOT NULL,
+    output_float         DOUBLE PRECISION NOT NULL,
+    output_pattern_seed  INTEGER NOT NULL,
+    seed                 BIGINT NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS trade_ups_owner_id_idx ON trade_ups (owner_id, created_at);
+
+CREATE TABLE IF NOT EXISTS trade_up_inputs (
+    trade_up_id   UUID NOT NULL REFERENCES trade_ups (id) ON DELETE CASCADE,
+    item_id       UUID NOT NULL,
+    definition_id UUID NOT NULL,
+    float_value   DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (trade_up_id, item_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS trade_up_inputs_item_id_idx ON trade_up_inputs (item_id);
+
+COMMIT;
@@ -0,0 +1,36 @@
+-- Price observations per item definition, partitioned by month. Prices and
+-- volumes are integers; prices are in minor units of the currency. Monthly
+-- partitions are created on demand by ensure_price_partition, which the
+-- storage calls before inserting.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS price_observations (
+    definition_id UUID NOT NULL REFERENCES item_definitions (id) ON DELETE CASCADE,
+    source        TEXT NOT NULL,
+    currency      CHAR(3) NOT NULL,
+    price         BIGINT NOT NULL CHECK (price >= 0),
+    volume        BIGINT NOT NULL DEFAULT 0 CHECK (volume >= 0),
+    observed_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (definition_id, currency, source, observed_at)
+) PARTITION BY RANGE (observed_at);
+
+CREATE INDEX IF NOT EXISTS price_observations_currency_idx ON price_observations (definition_id, currency, observed_at);
+
+CREATE OR REPLACE FUNCTION ensure_price_partition(ts TIMESTAMPTZ) RETURNS void AS $$
+DECLARE
+    month_start TIMESTAMPTZ := date_trunc('month', ts AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+    partition   TEXT := format('price_observations_%s', to_char(month_start AT TIME ZONE 'UTC', 'YYYY_MM'));
+BEGIN
+    IF to_regclass(partition) IS NULL THEN
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF price_observations FOR VALUES FROM (%L) TO (%L)',
+            partition, month_start, month_start + INTERVAL '1 month'
+        );
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT ensure_price_partition(now());
+SELECT ensure_price_partition(now() + INTERVAL '1 month');
+
+COMMIT;
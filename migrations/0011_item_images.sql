@@ -0,0 +1,18 @@
+-- Images of item definitions. The objects live in the asset store under
+-- the SHA-256 of their content; this table links definitions to them.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS item_images (
+    definition_id   UUID PRIMARY KEY REFERENCES item_definitions (id) ON DELETE CASCADE,
+    hash            TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    width           INTEGER NOT NULL,
+    height          INTEGER NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    thumbnail_sizes INTEGER[] NOT NULL,
+    uploaded_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS item_images_hash_idx ON item_images (hash);
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Names and descriptions of item definitions per locale. The search vector
+-- is built with the text search configuration of the translation's
+-- language, so every language is stemmed by its own rules.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS item_definition_translations (
+    definition_id UUID NOT NULL REFERENCES item_definitions (id) ON DELETE CASCADE,
+    locale        TEXT NOT NULL,
+    name          TEXT NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    search_config REGCONFIG NOT NULL,
+    search        TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_config, name), 'A') ||
+        setweight(to_tsvector(search_config, description), 'B')
+    ) STORED,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (definition_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS item_definition_translations_search_idx
+    ON item_definition_translations USING GIN (search);
+
+-- Untranslated definitions are searched by name without stemming.
+CREATE INDEX IF NOT EXISTS item_definitions_name_search_idx
+    ON item_definitions USING GIN (to_tsvector('simple', name));
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Free-form tags of items, such as "souvenir" or "2024-operation". The GIN
+-- index serves both the any (&&) and the all (@>) tag filters.
+BEGIN;
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'
+    CONSTRAINT items_tags_limit CHECK (cardinality(tags) <= 32);
+
+CREATE INDEX IF NOT EXISTS items_tags_idx ON items USING GIN (tags);
+
+COMMIT;
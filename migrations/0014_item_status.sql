@@ -0,0 +1,20 @@
+-- Lifecycle status of items and their scheduled status changes. Existing
+-- items are live, so they start out published. An item has at most one
+-- scheduled transition; scheduling another replaces it.
+BEGIN;
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+    CONSTRAINT items_status_check CHECK (status IN ('draft', 'published', 'locked', 'retired'));
+
+CREATE INDEX IF NOT EXISTS items_status_idx ON items (status);
+
+CREATE TABLE IF NOT EXISTS item_status_schedules (
+    item_id    UUID PRIMARY KEY REFERENCES items (id) ON DELETE CASCADE,
+    to_status  TEXT NOT NULL CHECK (to_status IN ('draft', 'published', 'locked', 'retired')),
+    due_at     TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS item_status_schedules_due_at_idx ON item_status_schedules (due_at);
+
+COMMIT;
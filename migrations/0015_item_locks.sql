@@ -0,0 +1,17 @@
+-- Locks keep items from being transferred, traded or deleted. A lock without
+-- expires_at holds until it is removed; expired locks have no effect and are
+-- cleaned up by the service.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS item_locks (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id    UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    reason     TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS item_locks_item_id_idx ON item_locks (item_id);
+CREATE INDEX IF NOT EXISTS item_locks_expires_at_idx ON item_locks (expires_at) WHERE expires_at IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Version of the schema, the number of the last migration applied. Snapshots
+-- record it and restores refuse snapshots of another version. Every later
+-- migration must update it.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS schema_version (
+    version    INTEGER NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- At most one row.
+    singleton  BOOLEAN PRIMARY KEY DEFAULT true CHECK (singleton)
+);
+
+INSERT INTO schema_version (version) VALUES (16)
+ON CONFLICT (singleton) DO UPDATE
+SET version = EXCLUDED.version, applied_at = now();
+
+COMMIT;
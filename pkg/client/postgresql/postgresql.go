@@ -3,7 +3,8 @@ package postgresql
 import (
 	"context"
 	"fmt"
-	"log"
+	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -13,47 +14,132 @@ import (
 	"item-service/internal/config"
 )
 
+const (
+	baseRetryDelay = 500 * time.Millisecond
+	maxRetryDelay  = 10 * time.Second
+)
+
 type Client interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Begin(ctx context.Context) (pgx.Tx, error)
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	Ping(ctx context.Context) error
+	Stat() *pgxpool.Stat
+	Close()
 }
 
-func NewClient(ctx context.Context, maxAttemps int, sc config.StorageConfig) (pool *pgxpool.Pool, err error) {
-	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", sc.Username, sc.Password, sc.Host, sc.Port, sc.Database)
-
-	err = doWithTries(func() error {
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
+// NewClient creates a connection pool and pings the database until it
+// answers, retrying up to maxAttempts times with exponential backoff and
+// jitter. It gives up early when ctx is done.
+func NewClient(ctx context.Context, maxAttempts int, sc config.StorageConfig) (pool *pgxpool.Pool, err error) {
+	pool, err = NewPool(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
 
-		pool, err = pgxpool.New(ctx, dsn)
-		if err != nil {
-			return err
+	err = doWithTries(ctx, func(ctx context.Context) error {
+		if sc.ConnectTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, sc.ConnectTimeout)
+			defer cancel()
 		}
 
-		return nil
+		return pool.Ping(ctx)
+	}, maxAttempts)
+	if err != nil {
+		pool.Close()
+
+		return nil, fmt.Errorf("ping postgresql after %d attempts: %w", maxAttempts, err)
+	}
+
+	return pool, nil
+}
 
-	}, maxAttemps, 5*time.Second)
+// NewPool creates a connection pool without waiting for the database;
+// connections are established lazily.
+func NewPool(ctx context.Context, sc config.StorageConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := poolConfig(sc)
+	if err != nil {
+		return nil, err
+	}
 
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
-		log.Fatal("Error do with tries postgresql")
+		return nil, fmt.Errorf("create pool: %w", err)
 	}
 
 	return pool, nil
 }
-func doWithTries(fn func() error, attemtps int, delay time.Duration) (err error) {
-	for attemtps > 0 {
-		if err = fn(); err != nil {
-			time.Sleep(delay)
-			attemtps--
 
-			continue
+func poolConfig(sc config.StorageConfig) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(sc.ConnString())
+	if err != nil {
+		// The parse error may echo the connection string, which holds the password.
+		return nil, fmt.Errorf("parse connection string: invalid storage dsn")
+	}
+
+	if sc.MaxConns > 0 {
+		cfg.MaxConns = sc.MaxConns
+	}
+	if sc.MinConns > 0 {
+		cfg.MinConns = sc.MinConns
+	}
+	if sc.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = sc.MaxConnLifetime
+	}
+	if sc.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = sc.MaxConnIdleTime
+	}
+	if sc.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = sc.HealthCheckPeriod
+	}
+	if sc.ConnectTimeout > 0 {
+		cfg.ConnConfig.ConnectTimeout = sc.ConnectTimeout
+	}
+
+	params := cfg.ConnConfig.RuntimeParams
+	if sc.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(sc.StatementTimeout.Milliseconds(), 10)
+	}
+	if sc.ApplicationName != "" {
+		params["application_name"] = sc.ApplicationName
+	}
+
+	return cfg, nil
+}
+
+func doWithTries(ctx context.Context, fn func(ctx context.Context) error, attempts int) (err error) {
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == attempts-1 {
+			break
 		}
 
-		return nil
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+// backoff returns the delay before the next attempt: the exponential delay
+// capped at maxRetryDelay, half of which is randomized.
+func backoff(attempt int) time.Duration {
+	d := maxRetryDelay
+	if attempt < 16 {
+		d = min(baseRetryDelay<<attempt, maxRetryDelay)
 	}
 
-	return
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
 }